@@ -13,17 +13,24 @@ import (
 // Analyser 语义分析器
 type Analyser struct {
 	methods       *symbol.SymbolTable[hir.Method] // 方法表
-	scope         *symbol.SymbolTable[ast.Type]   // 作用域内的变量表
+	types         *symbol.SymbolTable[hir.Struct] // 类型表，结构体名到结构体
+	structs       []hir.Struct                    // 按声明顺序排列的结构体，下标即结构体类型中记录的下标
+	globals       *symbol.SymbolTable[hir.Global] // 全局变量、常量表，在所有方法中可见
+	scope         *symbol.SymbolTable[hir.Type]   // 作用域内的变量表
 	unusedVars    *symbol.SymbolTable[hir.ID]     // 未使用的变量表
 	unusedMethods *symbol.SymbolTable[hir.ID]     // 未使用的方法表
 	methodIn      ast.Method                      // 当前分析的方法
+	loops         int                             // 当前所在循环的层数，用于检查break、continue语句
+	switches      int                             // 当前所在switch语句的层数，用于检查break语句
 }
 
 // NewAnalyser 新建语义分析器
 func NewAnalyser() *Analyser {
 	return &Analyser{
 		methods:       symbol.NewSymbolTable[hir.Method](),
-		scope:         symbol.NewSymbolTable[ast.Type](),
+		types:         symbol.NewSymbolTable[hir.Struct](),
+		globals:       symbol.NewSymbolTable[hir.Global](),
+		scope:         symbol.NewSymbolTable[hir.Type](),
 		unusedVars:    symbol.NewSymbolTable[hir.ID](),
 		unusedMethods: symbol.NewSymbolTable[hir.ID](),
 	}
@@ -31,8 +38,10 @@ func NewAnalyser() *Analyser {
 
 // ScopeInit 对新的作用域进行初始化
 func (a *Analyser) ScopeInit() {
-	a.scope = symbol.NewSymbolTable[ast.Type]()
+	a.scope = symbol.NewSymbolTable[hir.Type]()
 	a.unusedVars = symbol.NewSymbolTable[hir.ID]()
+	a.loops = 0
+	a.switches = 0
 }
 
 // Analyse 对AST进行语义分析
@@ -52,6 +61,26 @@ func (a *Analyser) Analyse(AST *ast.Program) (*hir.Program, int) {
 		return nil, errs
 	}
 
+	// 分析结构体声明，结构体只能使用之前声明的结构体作为字段类型
+	for _, declaration := range AST.Struct {
+		if err := a.analyseStruct(declaration); err != nil {
+			_ = glg.Error(err)
+			errs++
+		}
+	}
+
+	// 分析全局变量、常量声明，全局作用域在方法之前建立
+	globals := make([]hir.Global, 0)
+	for _, global := range AST.Global {
+		resGlobals, err := a.analyseGlobal(global)
+		if err != nil {
+			_ = glg.Error(err)
+			errs++
+			continue
+		}
+		globals = append(globals, resGlobals...)
+	}
+
 	//遍历分析AST中的每个方法
 	for _, method := range AST.Method {
 		// 在子程序中进行分析
@@ -81,7 +110,138 @@ func (a *Analyser) Analyse(AST *ast.Program) (*hir.Program, int) {
 	}
 
 	// 返回HIR和错误计数
-	return hir.NewProgram(a.methods.ToArray()), errs
+	return hir.NewProgram(a.structs, globals, a.methods.ToArray()), errs
+}
+
+// analyseStruct 对结构体声明进行语义分析，分析成功后添加到类型表
+// 字段不能有初始值，不能为数组，字段名不能重复
+func (a *Analyser) analyseStruct(declaration ast.StructDeclaration) error {
+	a.methodIn = ast.Method{}
+	name := declaration.ID.Literal.(string)
+	// 结构体重复声明
+	if a.types.HasSymbol(name) {
+		return errors.New(fmt.Sprintf("struct %s is duplicated", name))
+	}
+
+	fields := make([]hir.TypeIDPair, 0)
+	for _, fieldDecl := range declaration.Fields {
+		decls, _ := fieldDecl.Integrate()
+		for _, decl := range decls.Seq {
+			fieldName := decl.ID.Literal.(string)
+			if _, ok := hir.NewStruct(name, hir.TErr, fields).GetField(fieldName); ok {
+				return errors.New(fmt.Sprintf("field %s is duplicated in struct %s", fieldName, name))
+			}
+			if decl.Init != nil {
+				return errors.New(fmt.Sprintf("field %s cannot be initialised in struct %s", fieldName, name))
+			}
+			if decl.Dim != nil {
+				return errors.New(fmt.Sprintf("field %s cannot be an array in struct %s", fieldName, name))
+			}
+			// 字段的类型，结构体类型必须已经声明，因此结构体不能包含自身
+			field, err := a.resolveType(decl)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, *field)
+		}
+	}
+	if len(fields) == 0 {
+		return errors.New(fmt.Sprintf("struct %s has no fields", name))
+	}
+
+	// 添加到类型表
+	s := hir.NewStruct(name, hir.NewStructType(len(a.structs)), fields)
+	a.types.AddSymbol(name, *s)
+	a.structs = append(a.structs, *s)
+	return nil
+}
+
+// resolveType 将AST中的类型-标识符对转换为HIR，结构体名转换为结构体类型
+func (a *Analyser) resolveType(decl ast.TypeIDPair) (*hir.TypeIDPair, error) {
+	pair := hir.AstTypeIDPair(decl).ToHIR()
+	if decl.Type.Type != lexer.IDENTIFIER {
+		return pair, nil
+	}
+	name := decl.Type.Literal.(string)
+	s, ok := a.types.GetSymbol(name)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf("type %s is not defined in %s", name, a.scopeName()))
+	}
+	if decl.Dim != nil {
+		return nil, errors.New(fmt.Sprintf("array of struct %s is not supported in %s", name, a.scopeName()))
+	}
+	pair.Type = s.Type
+	return pair, nil
+}
+
+// typeName 获取类型在源程序中的名称，输出错误信息时使用
+func (a *Analyser) typeName(t hir.Type) string {
+	return hir.TypeName(t, a.structs)
+}
+
+// analyseGlobal 对全局变量、常量声明进行语义分析
+// 全局变量从左到右依次进入全局作用域，初始值中只能使用之前声明的全局变量
+func (a *Analyser) analyseGlobal(declaration ast.GlobalDeclaration) ([]hir.Global, error) {
+	// 不在任何方法中，作用域为空
+	a.methodIn = ast.Method{}
+	a.ScopeInit()
+
+	isConst := declaration.Const != nil
+	decls, _ := declaration.Declaration.Integrate()
+	globals := make([]hir.Global, 0)
+
+	for _, decl := range decls.Seq {
+		name := decl.ID.Literal.(string)
+		// 全局变量重复声明
+		if a.globals.HasSymbol(name) {
+			return nil, errors.New(fmt.Sprintf("global %s is duplicated", name))
+		}
+		// 常量必须有初始值
+		if isConst && decl.Init == nil {
+			return nil, errors.New(fmt.Sprintf("constant %s must be initialised", name))
+		}
+		if err := a.checkArrayDecl(decl); err != nil {
+			return nil, err
+		}
+
+		// 分析初始值，检查类型
+		pair, err := a.resolveType(decl)
+		if err != nil {
+			return nil, err
+		}
+		t := pair.Type
+		var init hir.Exp
+		if decl.Init != nil {
+			resExp, err := a.analyseExp(*decl.Init)
+			if err != nil {
+				return nil, err
+			}
+			if initType := a.typeOfExp(resExp); !assignable(t, initType) {
+				return nil, errors.New(fmt.Sprintf("global %s is declared as %s, but initialised with %s", name, a.typeName(t), a.typeName(initType)))
+			}
+			init = resExp
+		}
+
+		// 添加到全局作用域
+		global := hir.NewGlobal(t, name, isConst, init)
+		a.globals.AddSymbol(name, *global)
+		globals = append(globals, *global)
+	}
+
+	return globals, nil
+}
+
+// scopeName 当前分析位置的名称，输出错误信息时使用
+func (a *Analyser) scopeName() string {
+	if a.methodIn.ID.Literal == nil {
+		return "global scope"
+	}
+	return fmt.Sprintf("method %s", a.methodIn.GetMethodName())
+}
+
+// hasVar 判断变量是否在当前方法的作用域或全局作用域中
+func (a *Analyser) hasVar(name string) bool {
+	return a.scope.HasSymbol(name) || a.globals.HasSymbol(name)
 }
 
 // analyseMethod 对方法进行语义分析
@@ -92,30 +252,49 @@ func (a *Analyser) analyseMethod(method ast.Method) (*hir.Method, error) {
 	if a.methods.HasSymbol(a.methodIn.GetMethodName()) {
 		return nil, errors.New(fmt.Sprintf("method name %s is duplicated with another method", a.methodIn.GetMethodName()))
 	}
+	// 检查方法名是否与全局变量重名
+	if a.globals.HasSymbol(a.methodIn.GetMethodName()) {
+		return nil, errors.New(fmt.Sprintf("method name %s is duplicated with a global", a.methodIn.GetMethodName()))
+	}
 
 	// 初始化作用域
 	a.ScopeInit()
 
 	// 分析方法的参数并添加到作用域中
 	paramsSeq, _ := method.ParamList.Integrate()
-	a.scope.AddSymbol(a.methodIn.GetMethodName(), ast.Type{})
-
-	// 将方法添加到方法表中
-	a.methods.AddSymbol(a.methodIn.GetMethodName(), hir.Method{})
+	a.scope.AddSymbol(a.methodIn.GetMethodName(), hir.TErr)
 
 	// 分析参数表
+	params := make([]*hir.TypeIDPair, 0)
 	if paramsSeq != nil {
 		for _, param := range paramsSeq.Seq {
 			// 作用域中已经存在同名的变量
 			if a.scope.HasSymbol(param.ID.Literal.(string)) {
-				a.methods.RemoveSymbol(a.methodIn.GetMethodName())
 				return nil, errors.New(fmt.Sprintf("param name %s is duplicated", param.ID.Literal.(string)))
 			}
+			// 参数不能遮蔽全局变量
+			if a.globals.HasSymbol(param.ID.Literal.(string)) {
+				return nil, errors.New(fmt.Sprintf("param %s shadows a global in method %s", param.ID.Literal.(string), a.methodIn.GetMethodName()))
+			}
+			// 数组参数的长度
+			if err := a.checkArrayDecl(param); err != nil {
+				return nil, err
+			}
+			// 参数类型
+			paramHIR, err := a.resolveType(param)
+			if err != nil {
+				return nil, err
+			}
 			// 将参数添加到作用域中
-			a.scope.AddSymbol(param.ID.Literal.(string), param.Type)
+			a.scope.AddSymbol(param.ID.Literal.(string), paramHIR.Type)
+			params = append(params, paramHIR)
 		}
 	}
 
+	// 将方法添加到方法表中，方法体分析完成前只有签名，供递归调用检查
+	resultType := hir.AstResultType(method.ResultType)
+	a.methods.AddSymbol(a.methodIn.GetMethodName(), *hir.NewMethod(resultType.ToHIR(), a.methodIn.GetMethodName(), params, nil))
+
 	// 分析方法体
 	stmts, err := a.analyseBlock(method.Block)
 	if err != nil {
@@ -132,10 +311,8 @@ func (a *Analyser) analyseMethod(method ast.Method) (*hir.Method, error) {
 	}
 
 	// 整合为HIR的方法
-	resultType := hir.AstResultType(method.ResultType)
-	paramList := hir.AstParamList(method.ParamList)
-
-	resMethod := hir.NewMethod(resultType.ToHIR(), a.methodIn.GetMethodName(), paramList.ToHIR(), &stmts)
+	resMethod := hir.NewMethod(resultType.ToHIR(), a.methodIn.GetMethodName(), params, &stmts)
+	resMethod.Doc = method.Doc
 
 	return resMethod, nil
 }
@@ -172,6 +349,15 @@ func (a *Analyser) analyseStmt(stmts ast.Statement) (*hir.Statement, error) {
 	case ast.LOOPSTATEMENT:
 		loopStmt, err := a.analyseLoopStmt(*((stmts.Statement).(*ast.LoopStatement)))
 		return &loopStmt, err
+	case ast.FORSTATEMENT:
+		forStmt, err := a.analyseForStmt(*((stmts.Statement).(*ast.ForStatement)))
+		return &forStmt, err
+	case ast.DOWHILESTATEMENT:
+		doWhileStmt, err := a.analyseDoWhileStmt(*((stmts.Statement).(*ast.DoWhileStatement)))
+		return &doWhileStmt, err
+	case ast.SWITCHSTATEMENT:
+		switchStmt, err := a.analyseSwitchStmt(*((stmts.Statement).(*ast.SwitchStatement)))
+		return &switchStmt, err
 	case ast.CALLSTATEMENT:
 		callStmt, err := a.analyseCallStmt(*((stmts.Statement).(*ast.CallStatement)))
 		return &callStmt, err
@@ -187,6 +373,12 @@ func (a *Analyser) analyseStmt(stmts ast.Statement) (*hir.Statement, error) {
 	case ast.CONTINUESTATEMENT:
 		contStmt, err := a.analyseContinueStmt(*((stmts.Statement).(*ast.ContinueStatement)))
 		return &contStmt, err
+	case ast.READSTATEMENT:
+		readStmt, err := a.analyseReadStmt(*((stmts.Statement).(*ast.ReadStatement)))
+		return &readStmt, err
+	case ast.WRITESTATEMENT:
+		writeStmt, err := a.analyseWriteStmt(*((stmts.Statement).(*ast.WriteStatement)))
+		return &writeStmt, err
 	case ast.LOCALVARIABLEDECLARATION:
 		varDeclStmt, err := a.analyseLocalVarDecl(*((stmts.Statement).(*ast.LocalVariableDeclaration)))
 		return &varDeclStmt, err
@@ -206,7 +398,7 @@ func (a *Analyser) analyseStmt(stmts ast.Statement) (*hir.Statement, error) {
 // analyseConditionalStmt 对条件语句进行语义分析
 func (a *Analyser) analyseConditionalStmt(statement ast.ConditionalStatement) (hir.Statement, error) {
 	// 分析条件表达式
-	condExp, err := a.analyseConditionalExp(statement.ConditionalExp)
+	condExp, err := a.analyseExp(statement.Condition)
 	if err != nil {
 		return nil, err
 	}
@@ -225,47 +417,194 @@ func (a *Analyser) analyseConditionalStmt(statement ast.ConditionalStatement) (h
 			return nil, err
 		}
 		//返回if-else语句
-		return hir.NewConditionalStatement(*condExp, ifStmt, elseStmt), nil
+		return hir.NewConditionalStatement(condExp, ifStmt, elseStmt), nil
 	}
 
 	// 返回if语句
-	return hir.NewConditionalStatement(*condExp, ifStmt, nil), nil
+	return hir.NewConditionalStatement(condExp, ifStmt, nil), nil
 }
 
 // analyseLoopStmt 对循环语句进行语义分析
 func (a *Analyser) analyseLoopStmt(statement ast.LoopStatement) (hir.Statement, error) {
 	// 分析条件表达式
-	condExp, err := a.analyseConditionalExp(statement.ConditionalExp)
+	condExp, err := a.analyseExp(statement.Condition)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分析循环体
+	whileStmt, err := a.analyseLoopBody(statement.Statement)
+	if err != nil {
+		return nil, err
+	}
+
+	return hir.NewLoopStatement(condExp, whileStmt), nil
+}
+
+// analyseForStmt 对for循环语句进行语义分析
+// 初始化语句中声明的变量只在循环内可见
+func (a *Analyser) analyseForStmt(statement ast.ForStatement) (hir.Statement, error) {
+	// 分析初始化语句
+	initStmt, err := a.analyseStmt(statement.Init)
+	if err != nil {
+		return nil, err
+	}
+	if statement.Init.Type == ast.LOCALVARIABLEDECLARATION {
+		decls, _ := statement.Init.Statement.(*ast.LocalVariableDeclaration).Integrate()
+		defer func() {
+			for _, decl := range decls.Seq {
+				a.scope.RemoveSymbol(decl.ID.Literal.(string))
+				a.unusedVars.RemoveSymbol(decl.ID.Literal.(string))
+			}
+		}()
+	}
+
+	// 分析条件表达式，为空时总是继续循环
+	var condExp hir.Exp
+	if statement.Condition != nil {
+		condExp, err = a.analyseExp(*statement.Condition)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// 分析步进语句，与赋值语句相同
+	var stepStmt *hir.Statement
+	if statement.Step != nil {
+		step, err := a.analyseAssignmentStmt(ast.AssignmentStatement{
+			ID:        statement.Step.ID,
+			Index:     statement.Step.Index,
+			Selectors: statement.Step.Selectors,
+			Assign:    statement.Step.Assign,
+			Exp:       statement.Step.Exp,
+		})
+		if err != nil {
+			return nil, err
+		}
+		stepStmt = &step
+	}
+
+	// 分析循环体
+	forStmt, err := a.analyseLoopBody(statement.Statement)
 	if err != nil {
 		return nil, err
 	}
 
+	return hir.NewForStatement(initStmt, condExp, stepStmt, forStmt), nil
+}
+
+// analyseDoWhileStmt 对do-while循环语句进行语义分析
+func (a *Analyser) analyseDoWhileStmt(statement ast.DoWhileStatement) (hir.Statement, error) {
 	// 分析循环体
-	whileStmt, err := a.analyseStmt(statement.Statement)
+	doStmt, err := a.analyseLoopBody(statement.Statement)
+	if err != nil {
+		return nil, err
+	}
+
+	// 分析条件表达式
+	condExp, err := a.analyseExp(statement.Condition)
 	if err != nil {
 		return nil, err
 	}
 
-	return hir.NewLoopStatement(*condExp, whileStmt), nil
+	return hir.NewDoWhileStatement(doStmt, condExp), nil
+}
+
+// analyseLoopBody 对循环体进行语义分析，循环体中可以使用break、continue语句
+func (a *Analyser) analyseLoopBody(statement ast.Statement) (*hir.Statement, error) {
+	a.loops++
+	defer func() { a.loops-- }()
+	return a.analyseStmt(statement)
+}
+
+// analyseSwitchStmt 对switch语句进行语义分析
+// 被匹配的值只能为整数或字符，case常量的类型必须与之相同且不能重复，分支中可以使用break语句
+func (a *Analyser) analyseSwitchStmt(statement ast.SwitchStatement) (hir.Statement, error) {
+	// 分析被匹配的表达式
+	exp, err := a.analyseExp(statement.Exp)
+	if err != nil {
+		return nil, err
+	}
+	t := a.typeOfExp(exp)
+	if t != hir.TInteger && t != hir.TChar {
+		return nil, errors.New(fmt.Sprintf("switch expects an int or char value, but got %s in method %s", a.typeName(t), a.methodIn.GetMethodName()))
+	}
+
+	a.switches++
+	defer func() { a.switches-- }()
+
+	values := make(map[int64]bool)
+	hasDefault := false
+	cases := make([]hir.SwitchCase, 0, len(statement.Cases))
+	for _, switchCase := range statement.Cases {
+		// case常量
+		var value int64
+		if switchCase.Value == nil {
+			if hasDefault {
+				return nil, errors.New(fmt.Sprintf("default is duplicated in switch in method %s", a.methodIn.GetMethodName()))
+			}
+			hasDefault = true
+		} else {
+			caseType := hir.Type(hir.TInteger)
+			if switchCase.Value.Type == lexer.CHAR_LITERAL {
+				// 空字符字面量''的值为0
+				ch, _ := switchCase.Value.Literal.(rune)
+				value, caseType = int64(ch), hir.TChar
+			} else {
+				value = switchCase.Value.Literal.(int64)
+			}
+			if caseType != t {
+				return nil, errors.New(fmt.Sprintf("case %s does not match switch value of type %s in method %s", switchCase.Value.Describe(), a.typeName(t), a.methodIn.GetMethodName()))
+			}
+			if values[value] {
+				return nil, errors.New(fmt.Sprintf("case %s is duplicated in switch in method %s", switchCase.Value.Describe(), a.methodIn.GetMethodName()))
+			}
+			values[value] = true
+		}
+
+		// 分析分支中的语句
+		var body *hir.Statement
+		statements := switchCase.Statements
+		resBlock, err := a.analyseBlock(ast.Block{Statements: &statements})
+		if err != nil {
+			return nil, err
+		}
+		if resBlock != nil {
+			body = &resBlock
+		}
+		cases = append(cases, hir.NewSwitchCase(value, switchCase.Value == nil, body))
+	}
+
+	return hir.NewSwitchStatement(exp, t, cases), nil
 }
 
 // analyseCallStmt 对调用语句进行语义分析
 func (a *Analyser) analyseCallStmt(statement ast.CallStatement) (hir.Statement, error) {
+	_, resExps, err := a.analyseCall(statement.ID, statement.ActParamList)
+	if err != nil {
+		return nil, err
+	}
+
+	return hir.NewCallStatement(statement.ID.Literal.(string), resExps), nil
+}
+
+// analyseCall 对方法调用进行语义分析，检查实参的个数及类型
+func (a *Analyser) analyseCall(id ast.ID, actParamList ast.ActParamList) (*hir.Method, []hir.Exp, error) {
 	// 检查方法是否存在
-	if !a.methods.HasSymbol(statement.ID.Literal.(string)) {
-		return nil, errors.New(fmt.Sprintf("method %s is not defined", statement.ID.Literal.(string)))
+	if !a.methods.HasSymbol(id.Literal.(string)) {
+		return nil, nil, errors.New(fmt.Sprintf("method %s is not defined", id.Literal.(string)))
 	}
 
 	// 获取方法、参数列表
-	targetMethod, _ := a.methods.GetSymbol(statement.ID.Literal.(string))
+	targetMethod, _ := a.methods.GetSymbol(id.Literal.(string))
 	methodParams := targetMethod.Params
 
 	// 获取实参列表
-	actParams, _ := statement.ActParamList.Integrate()
+	actParams, _ := actParamList.Integrate()
 
 	// 不能调用main方法
 	if targetMethod.Name == "main" {
-		return nil, errors.New("main method is not callable")
+		return nil, nil, errors.New("main method is not callable")
 	}
 
 	// 分析实参列表
@@ -274,28 +613,39 @@ func (a *Analyser) analyseCallStmt(statement ast.CallStatement) (hir.Statement,
 		// 分析表达式
 		resExp, err := a.analyseExp(exp)
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
-		resExps = append(resExps, *resExp)
+		resExps = append(resExps, resExp)
 	}
 
 	// 实参与形参个数不匹配
-	if (methodParams == nil && len(actParams) != 0) || (methodParams != nil && len(methodParams) != 0 && len(actParams) == 0) || len(actParams) != len(methodParams) {
-		return nil, errors.New(fmt.Sprintf("method %s is called with wrong number of parameters", statement.ID.Literal.(string)))
+	if len(actParams) != len(methodParams) {
+		return nil, nil, errors.New(fmt.Sprintf("method %s is called with wrong number of parameters: expected %d, got %d", id.Literal.(string), len(methodParams), len(actParams)))
+	}
+
+	// 实参与形参类型不匹配
+	for i, param := range methodParams {
+		if actType := a.typeOfExp(resExps[i]); !assignable(param.Type, actType) {
+			return nil, nil, errors.New(fmt.Sprintf("method %s expects %s for parameter %s, but got %s", id.Literal.(string), a.typeName(param.Type), param.ID, a.typeName(actType)))
+		}
 	}
 
 	// 被调用，不再是未使用方法
-	a.unusedMethods.RemoveSymbol(statement.ID.Literal.(string))
+	a.unusedMethods.RemoveSymbol(id.Literal.(string))
 
-	return hir.NewCallStatement(statement.ID.Literal.(string), resExps), nil
+	return &targetMethod, resExps, nil
 }
 
 // analyseAssignmentStmt 对赋值语句进行语义分析
 func (a *Analyser) analyseAssignmentStmt(statement ast.AssignmentStatement) (hir.Statement, error) {
 	// 作用域中是否存在变量
-	if !a.scope.HasSymbol(statement.ID.Literal.(string)) {
+	if !a.hasVar(statement.ID.Literal.(string)) {
 		return nil, errors.New(fmt.Sprintf("variable %s is not defined in method %s", statement.ID.Literal.(string), a.methodIn.GetMethodName()))
 	}
+	// 常量只读
+	if global, ok := a.globals.GetSymbol(statement.ID.Literal.(string)); ok && global.Const {
+		return nil, errors.New(fmt.Sprintf("constant %s cannot be assigned in method %s", global.ID, a.methodIn.GetMethodName()))
+	}
 
 	// 分析表达式
 	resExp, err := a.analyseExp(statement.Exp)
@@ -303,10 +653,42 @@ func (a *Analyser) analyseAssignmentStmt(statement ast.AssignmentStatement) (hir
 		return nil, err
 	}
 
+	// 数组元素赋值
+	if statement.Index != nil {
+		index, err := a.analyseIndex(statement.ID, *statement.Index)
+		if err != nil {
+			return nil, err
+		}
+		if expType := a.typeOfExp(resExp); !assignable(index.Type.Elem(), expType) {
+			return nil, errors.New(fmt.Sprintf("element of %s is %s, but assigned with %s in method %s", index.Array, a.typeName(index.Type.Elem()), a.typeName(expType), a.methodIn.GetMethodName()))
+		}
+		return hir.NewIndexAssignStatement(index, resExp), nil
+	}
+	// 结构体字段赋值
+	if len(statement.Selectors) != 0 {
+		path, t, err := a.analyseField(statement.ID, statement.Selectors)
+		if err != nil {
+			return nil, err
+		}
+		if expType := a.typeOfExp(resExp); !assignable(t, expType) {
+			return nil, errors.New(fmt.Sprintf("field %s is %s, but assigned with %s in method %s", path, a.typeName(t), a.typeName(expType), a.methodIn.GetMethodName()))
+		}
+		return hir.NewAssignStatement(path, resExp), nil
+	}
+	// 数组只能按元素赋值
+	t := a.typeOfExp(hir.ID(statement.ID.Literal.(string)))
+	if t.IsArray() {
+		return nil, errors.New(fmt.Sprintf("array %s cannot be assigned as a whole in method %s", statement.ID.Literal.(string), a.methodIn.GetMethodName()))
+	}
+	// 值的类型须能赋给变量，结构体只能赋值为同一结构体类型的值
+	if expType := a.typeOfExp(resExp); !assignable(t, expType) {
+		return nil, errors.New(fmt.Sprintf("variable %s is %s, but assigned with %s in method %s", statement.ID.Literal.(string), a.typeName(t), a.typeName(expType), a.methodIn.GetMethodName()))
+	}
+
 	// 被赋值，不再是未使用变量
 	a.unusedVars.RemoveSymbol(statement.ID.Literal.(string))
 
-	return hir.NewAssignStatement(statement.ID.Literal.(string), *resExp), nil
+	return hir.NewAssignStatement(statement.ID.Literal.(string), resExp), nil
 }
 
 // analyseReturnStmt 对返回语句进行语义分析
@@ -321,219 +703,256 @@ func (a *Analyser) analyseReturnStmt(statement ast.ReturnStatement) (hir.Stateme
 	if err != nil {
 		return nil, err
 	}
+	// 数组、结构体不能作为返回值
+	t := a.typeOfExp(resExp)
+	if t.IsArray() || t.IsStruct() {
+		return nil, errors.New(fmt.Sprintf("value of type %s cannot be returned in method %s", a.typeName(t), a.methodIn.GetMethodName()))
+	}
+	// 返回值的类型须能赋给方法的返回值类型，无返回值的方法不能返回值
+	method, _ := a.methods.GetSymbol(a.methodIn.GetMethodName())
+	if method.ReturnType == hir.TVoid {
+		return nil, errors.New(fmt.Sprintf("method %s returns void, but returns a value of type %s", a.methodIn.GetMethodName(), a.typeName(t)))
+	}
+	if !assignable(hir.Type(method.ReturnType), t) {
+		return nil, errors.New(fmt.Sprintf("method %s returns %s, but returns a value of type %s", a.methodIn.GetMethodName(), method.ReturnType, a.typeName(t)))
+	}
 
-	return hir.NewReturnStatement(*resExp), nil
+	return hir.NewReturnStatement(resExp), nil
 }
 
 // analyseBreakStmt 对break语句进行语义分析
 func (a *Analyser) analyseBreakStmt(statement ast.BreakStatement) (hir.Statement, error) {
+	// 只能在循环或switch语句中使用
+	if a.loops == 0 && a.switches == 0 {
+		return nil, errors.New(fmt.Sprintf("break statement is not within a loop or switch in method %s", a.methodIn.GetMethodName()))
+	}
 	return hir.NewBreakStatement(), nil
 }
 
 // analyseContinueStmt 对continue语句进行语义分析
 func (a *Analyser) analyseContinueStmt(statement ast.ContinueStatement) (hir.Statement, error) {
+	// 只能在循环中使用
+	if a.loops == 0 {
+		return nil, errors.New(fmt.Sprintf("continue statement is not within a loop in method %s", a.methodIn.GetMethodName()))
+	}
 	return hir.NewContinueStatement(), nil
 }
 
-// analyseExpStmt 对表达式语句进行语义分析
+// analyseReadStmt 对输入语句进行语义分析，读取的变量必须已声明且不是常量
+func (a *Analyser) analyseReadStmt(statement ast.ReadStatement) (hir.Statement, error) {
+	ids, _ := statement.Integrate()
+	vars := make([]hir.TypeIDPair, 0)
+	for _, id := range ids {
+		name := id.Literal.(string)
+		// 方法名不能作为变量
+		if a.methods.HasSymbol(name) {
+			return nil, errors.New(fmt.Sprintf("%s is a method, but used as a variable", name))
+		}
+		// 作用域中是否存在变量
+		if !a.hasVar(name) {
+			return nil, errors.New(fmt.Sprintf("variable %s is not defined in method %s", name, a.methodIn.GetMethodName()))
+		}
+		// 常量只读
+		if global, ok := a.globals.GetSymbol(name); ok && global.Const {
+			return nil, errors.New(fmt.Sprintf("constant %s cannot be read in method %s", name, a.methodIn.GetMethodName()))
+		}
+		// 数组只能按元素读取，结构体只能按字段读取
+		if t := a.typeOfExp(hir.ID(name)); t.IsArray() {
+			return nil, errors.New(fmt.Sprintf("array %s cannot be read as a whole in method %s", name, a.methodIn.GetMethodName()))
+		} else if t.IsStruct() {
+			return nil, errors.New(fmt.Sprintf("struct %s cannot be read as a whole in method %s", name, a.methodIn.GetMethodName()))
+		}
+
+		// 被赋值，不再是未使用变量
+		a.unusedVars.RemoveSymbol(name)
+		vars = append(vars, *hir.NewTypeIDPair(a.typeOfExp(hir.ID(name)), name))
+	}
+
+	return hir.NewReadStatement(vars), nil
+}
+
+// analyseWriteStmt 对输出语句进行语义分析
+func (a *Analyser) analyseWriteStmt(statement ast.WriteStatement) (hir.Statement, error) {
+	exps, _ := statement.ActParamList.Integrate()
+	resExps := make([]hir.Exp, 0)
+	types := make([]hir.Type, 0)
+	for _, exp := range exps {
+		// 分析表达式
+		resExp, err := a.analyseExp(exp)
+		if err != nil {
+			return nil, err
+		}
+		// 只能输出数值、字符及字符串
+		t := a.typeOfExp(resExp)
+		if t == hir.TErr || t == hir.TVoid || t.IsArray() || t.IsStruct() {
+			return nil, errors.New(fmt.Sprintf("value of type %s cannot be written in method %s", a.typeName(t), a.methodIn.GetMethodName()))
+		}
+		resExps = append(resExps, resExp)
+		types = append(types, t)
+	}
+
+	return hir.NewWriteStatement(resExps, types), nil
+}
+
+// analyseLocalVarDecl 对变量声明语句进行语义分析
+// 变量从左到右依次进入作用域，初始值中只能使用之前声明的变量
 func (a *Analyser) analyseLocalVarDecl(declaration ast.LocalVariableDeclaration) (hir.Statement, error) {
 	// 分析变量声明 type-ID对
 	decls, _ := declaration.Integrate()
 	declTypeIDArray := make([]hir.TypeIDPair, 0)
+	inits := make([]hir.Exp, 0)
 
 	for _, decl := range decls.Seq {
 		// 变量重复声明
 		if a.scope.HasSymbol(decl.ID.Literal.(string)) {
 			return nil, errors.New(fmt.Sprintf("variable %s is duplicated in method %s", decl.ID.Literal.(string), a.methodIn.GetMethodName()))
 		}
+		// 局部变量不能遮蔽全局变量
+		if a.globals.HasSymbol(decl.ID.Literal.(string)) {
+			return nil, errors.New(fmt.Sprintf("variable %s shadows a global in method %s", decl.ID.Literal.(string), a.methodIn.GetMethodName()))
+		}
 
-		// 添加到作用域
-		a.scope.AddSymbol(decl.ID.Literal.(string), decl.Type)
-		// 添加到未使用变量
-		a.unusedVars.AddSymbol(decl.ID.Literal.(string), hir.ID(decl.ID.Literal.(string)))
+		if err := a.checkArrayDecl(decl); err != nil {
+			return nil, err
+		}
 
 		// 转换为HIR
-		declHIR := hir.AstTypeIDPair(decl).ToHIR()
-		declTypeIDArray = append(declTypeIDArray, *declHIR)
-	}
-
-	return hir.NewLocalVariableDeclaration(declTypeIDArray), nil
-}
-
-// analyseConditionalExp 对条件表达式进行语义分析
-func (a *Analyser) analyseConditionalExp(exp ast.ConditionalExp) (*hir.ConditionalExp, error) {
-	// 分析左右关系表达式
-	relationExps := exp.Integrate()
-
-	resRelationExps := make([]hir.RelationExp, 0)
-	for _, relationExp := range relationExps {
-		// 遍历分析左右关系表达式
-		resRelationExp, err := a.analyseRelationExp(relationExp)
+		declHIR, err := a.resolveType(decl)
 		if err != nil {
 			return nil, err
 		}
-		resRelationExps = append(resRelationExps, *resRelationExp)
-	}
 
-	// 只有一个关系表达式（左侧）
-	if len(resRelationExps) == 1 {
-		condExp := hir.NewConditionalExp(&resRelationExps[0], nil)
-		return &condExp, nil
-	}
-	// 有两个关系表达式（左右）
-	condExp := hir.NewConditionalExp(&resRelationExps[0], &resRelationExps[1])
-	return &condExp, nil
-}
-
-// analyseRelationExp 对关系表达式进行语义分析
-func (a *Analyser) analyseRelationExp(exp ast.RelationExp) (*hir.RelationExp, error) {
-	// 分析左右比较表达式
-	compExps := exp.Integrate()
+		// 分析初始值，检查类型
+		var init hir.Exp
+		if decl.Init != nil {
+			resExp, err := a.analyseExp(*decl.Init)
+			if err != nil {
+				return nil, err
+			}
+			if initType := a.typeOfExp(resExp); !assignable(declHIR.Type, initType) {
+				return nil, errors.New(fmt.Sprintf("variable %s is declared as %s, but initialised with %s", declHIR.ID, a.typeName(declHIR.Type), a.typeName(initType)))
+			}
+			init = resExp
+		}
 
-	resCompExps := make([]hir.CompExp, 0)
-	for _, compExp := range compExps {
-		// 遍历分析左右比较表达式
-		resCompExp, err := a.analyseCompExp(compExp)
-		if err != nil {
-			return nil, err
+		// 添加到作用域
+		a.scope.AddSymbol(decl.ID.Literal.(string), declHIR.Type)
+		// 没有初始值时添加到未使用变量，有初始值时与赋值相同，不再是未使用变量
+		if init == nil {
+			a.unusedVars.AddSymbol(decl.ID.Literal.(string), hir.ID(decl.ID.Literal.(string)))
 		}
-		resCompExps = append(resCompExps, *resCompExp)
-	}
 
-	// 只有一个比较表达式（左侧）
-	if len(resCompExps) == 1 {
-		relationExp := hir.NewRelationExp(&resCompExps[0], nil)
-		return &relationExp, nil
+		declTypeIDArray = append(declTypeIDArray, *declHIR)
+		inits = append(inits, init)
 	}
-	// 有两个比较表达式（左右）
-	relationExp := hir.NewRelationExp(&resCompExps[0], &resCompExps[1])
-	return &relationExp, nil
-}
 
-// analyseCompExp 对比较表达式进行语义分析
-func (a *Analyser) analyseCompExp(exp ast.CompExp) (*hir.CompExp, error) {
-	// 分析左右算术表达式
-	exps := exp.Integrate()
+	return hir.NewLocalVariableDeclaration(declTypeIDArray, inits), nil
+}
 
-	resExps := make([]hir.Exp, 0)
-	for _, expElem := range exps {
-		// 遍历分析左右算术表达式
-		resExp, err := a.analyseExp(expElem)
+// analyseExp 对表达式进行语义分析
+func (a *Analyser) analyseExp(exp ast.Exp) (hir.Exp, error) {
+	// 按照表达式类型进行分析
+	switch exp.Exp.(type) {
+	case ast.BinaryExp:
+		// Exp BinaryOp Exp
+		binaryExp := exp.Exp.(ast.BinaryExp)
+		op, ok := ast.BinaryOp[binaryExp.Op.Type]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("unknown binary operator %s", binaryExp.Op.Literal))
+		}
+		lExp, err := a.analyseExp(*binaryExp.LExp)
 		if err != nil {
 			return nil, err
 		}
-		resExps = append(resExps, *resExp)
-	}
-
-	// 只有一个算术表达式（左侧）
-	if len(resExps) == 1 {
-		compExp := hir.NewCompExp(&resExps[0], ast.EMPTY, nil)
-		return &compExp, nil
-	}
-	// 有两个算术表达式（左右）
-	// 按照比较运算符类型构造比较表达式
-	switch exp.CmpOp.Type {
-	case lexer.LESS:
-		compExp := hir.NewCompExp(&resExps[0], ast.LESS, &resExps[1])
-		return &compExp, nil
-	case lexer.LESSEQUAL:
-		compExp := hir.NewCompExp(&resExps[0], ast.LESSEQUAL, &resExps[1])
-		return &compExp, nil
-	case lexer.GREATER:
-		compExp := hir.NewCompExp(&resExps[0], ast.GREATER, &resExps[1])
-		return &compExp, nil
-	case lexer.GREATEREQUAL:
-		compExp := hir.NewCompExp(&resExps[0], ast.GREATEREQUAL, &resExps[1])
-		return &compExp, nil
-	case lexer.EQUAL:
-		compExp := hir.NewCompExp(&resExps[0], ast.EQUAL, &resExps[1])
-		return &compExp, nil
-	case lexer.DIAMOND:
-		compExp := hir.NewCompExp(&resExps[0], ast.DIAMOND, &resExps[1])
-		return &compExp, nil
-	default:
-		// 未知比较运算符
-		return nil, errors.New(fmt.Sprintf("unknown CmpOp %s", exp.CmpOp.Literal))
-	}
-}
-
-// analyseExp 对算术表达式进行语义分析
-func (a *Analyser) analyseExp(exp ast.Exp) (*hir.Exp, error) {
-	// 分析左右项
-	terms := exp.Integrate()
-
-	resTerms := make([]hir.Term, 0)
-	for _, term := range terms {
-		// 遍历分析左右项
-		resTerm, err := a.analyseTerm(term)
+		rExp, err := a.analyseExp(*binaryExp.RExp)
 		if err != nil {
 			return nil, err
 		}
-		resTerms = append(resTerms, *resTerm)
-	}
-
-	// 只有一个项（左侧）
-	if len(resTerms) == 1 {
-		exp := hir.NewExp(&resTerms[0], ast.EMPTY, nil)
-		return &exp, nil
-	}
-
-	// 有两个项（左右）
-	// 按照加减运算符类型构造算术表达式
-	switch exp.ExpRest.PlusOrMinus.Type {
-	case lexer.PLUS:
-		exp := hir.NewExp(&resTerms[0], ast.PLUS, &resTerms[1])
-		return &exp, nil
-	case lexer.MINUS:
-		exp := hir.NewExp(&resTerms[0], ast.MINUS, &resTerms[1])
-		return &exp, nil
-	default:
-		return nil, errors.New(fmt.Sprintf("unknown PlusOrMinus %s", exp.ExpRest.PlusOrMinus.Literal))
-	}
-}
-
-// analyseTerm 对项进行语义分析
-func (a *Analyser) analyseTerm(term ast.Term) (*hir.Term, error) {
-	// 分析左右因子
-	factors := term.Integrate()
-
-	resFactors := make([]hir.Factor, 0)
-	for _, factor := range factors {
-		// 遍历分析左右因子
-		resFactor, err := a.analyseFactor(factor)
+		// 检查操作数类型
+		lType, rType := a.typeOfExp(lExp), a.typeOfExp(rExp)
+		switch op {
+		case ast.MOD:
+			// 取模运算只能用于整数
+			if lType != hir.TInteger || rType != hir.TInteger {
+				return nil, errors.New(fmt.Sprintf("operator %% expects int operands, but got %s and %s", a.typeName(lType), a.typeName(rType)))
+			}
+		case ast.PLUS:
+			// 字符串之间的+为字符串拼接
+			if lType == hir.TString && rType == hir.TString {
+				op = ast.CONCAT
+			} else if !isNumeric(lType) || !isNumeric(rType) {
+				return nil, errors.New(fmt.Sprintf("operator + expects numeric or string operands, but got %s and %s", a.typeName(lType), a.typeName(rType)))
+			}
+		case ast.LESS, ast.LESSEQUAL, ast.GREATER, ast.GREATEREQUAL, ast.EQUAL, ast.DIAMOND:
+			// 比较运算
+			if !isComparable(lType, rType) {
+				return nil, errors.New(fmt.Sprintf("operator %v cannot compare %s and %s", binaryExp.Op.Literal, a.typeName(lType), a.typeName(rType)))
+			}
+		default:
+			// 其他算术运算及逻辑运算只能用于数值
+			if !isNumeric(lType) || !isNumeric(rType) {
+				return nil, errors.New(fmt.Sprintf("operator %v expects numeric operands, but got %s and %s", binaryExp.Op.Literal, a.typeName(lType), a.typeName(rType)))
+			}
+		}
+		return hir.NewBinaryExp(op, lExp, rExp), nil
+	case ast.UnaryExp:
+		// UnaryOp Exp
+		unaryExp := exp.Exp.(ast.UnaryExp)
+		op, ok := ast.UnaryOp[unaryExp.Op.Type]
+		if !ok {
+			return nil, errors.New(fmt.Sprintf("unknown unary operator %s", unaryExp.Op.Literal))
+		}
+		operand, err := a.analyseExp(*unaryExp.Exp)
 		if err != nil {
 			return nil, err
 		}
-		resFactors = append(resFactors, resFactor)
-	}
-
-	// 只有一个因子（左侧）
-	if len(resFactors) == 1 {
-		resTerm := hir.NewTerm(&resFactors[0], ast.EMPTY, nil)
-		return &resTerm, nil
-	}
-
-	// 有两个因子（左右）
-	// 按照乘除运算符类型构造项
-	switch term.TermRest.MulOrDiv.Type {
-	case lexer.TIMES:
-		term := hir.NewTerm(&resFactors[0], ast.TIMES, &resFactors[1])
-		return &term, nil
-	case lexer.DIVIDE:
-		term := hir.NewTerm(&resFactors[0], ast.DIVIDE, &resFactors[1])
-		return &term, nil
+		// 负号及not只能用于数值
+		if t := a.typeOfExp(operand); !isNumeric(t) {
+			return nil, errors.New(fmt.Sprintf("operator %v expects a numeric operand, but got %s", unaryExp.Op.Literal, a.typeName(t)))
+		}
+		return hir.NewUnaryExp(op, operand), nil
+	case ast.Factor:
+		// Factor
+		return a.analyseFactor(exp.Exp.(ast.Factor))
 	default:
-		return nil, errors.New(fmt.Sprintf("unknown MulOrDiv %s", term.TermRest.MulOrDiv.Literal))
+		return nil, errors.New(fmt.Sprintf("unknown Exp %T", exp.Exp))
 	}
 }
 
 // analyseFactor 对因子进行语义分析
-func (a *Analyser) analyseFactor(factor ast.Factor) (hir.Factor, error) {
+func (a *Analyser) analyseFactor(factor ast.Factor) (hir.Exp, error) {
 	// 按照因子类型进行分析
 	switch factor.Factor.(type) {
 	case ast.FactorTuple:
 		// (Exp)
 		return a.analyseExp(*factor.Factor.(ast.FactorTuple).Exp)
+	case ast.CallTuple:
+		// ID(ActParamList)
+		call := factor.Factor.(ast.CallTuple)
+		targetMethod, resExps, err := a.analyseCall(call.ID, call.ActParamList)
+		if err != nil {
+			return nil, err
+		}
+		// 没有返回值的方法不能作为表达式
+		if targetMethod.ReturnType == hir.TVoid {
+			return nil, errors.New(fmt.Sprintf("method %s returns void, but used as a value", targetMethod.Name))
+		}
+		return hir.NewCallExp(targetMethod.Name, resExps), nil
+	case ast.IndexTuple:
+		// ID[Exp]
+		element := factor.Factor.(ast.IndexTuple)
+		return a.analyseIndex(element.ID, element.Index)
+	case ast.FieldTuple:
+		// ID.ID
+		field := factor.Factor.(ast.FieldTuple)
+		path, t, err := a.analyseField(field.ID, field.Selectors)
+		if err != nil {
+			return nil, err
+		}
+		if t.IsStruct() {
+			return hir.NewStructExp(path, t), nil
+		}
+		return hir.ID(path), nil
 	case lexer.Token:
 		// ID| INTC | DECI
 		if factor.Factor.(lexer.Token).Type == lexer.IDENTIFIER {
@@ -543,18 +962,28 @@ func (a *Analyser) analyseFactor(factor ast.Factor) (hir.Factor, error) {
 			}
 
 			// 如果是未定义的变量，报错
-			if !a.scope.HasSymbol(factor.Factor.(lexer.Token).Literal.(string)) {
-				return nil, errors.New(fmt.Sprintf("variable %s is not defined in method %s", factor.Factor.(lexer.Token).Literal.(string), a.methodIn.GetMethodName()))
+			if !a.hasVar(factor.Factor.(lexer.Token).Literal.(string)) {
+				return nil, errors.New(fmt.Sprintf("variable %s is not defined in %s", factor.Factor.(lexer.Token).Literal.(string), a.scopeName()))
 			} else {
 				// 使用了变量，从未使用变量列表中删除
 				a.unusedVars.RemoveSymbol(factor.Factor.(lexer.Token).Literal.(string))
 			}
 			ID := hir.ID(factor.Factor.(lexer.Token).Literal.(string))
+			// 整个结构体变量
+			if t := a.typeOfExp(ID); t.IsStruct() {
+				return hir.NewStructExp(string(ID), t), nil
+			}
 			return ID, nil
 		} else if factor.Factor.(lexer.Token).Type == lexer.INTEGER_LITERAL {
 			return hir.NewInteger(factor.Factor.(lexer.Token).Literal.(int64)), nil
 		} else if factor.Factor.(lexer.Token).Type == lexer.DECIMAL_LITERAL {
 			return hir.NewFloat(factor.Factor.(lexer.Token).Literal.(float64)), nil
+		} else if factor.Factor.(lexer.Token).Type == lexer.STRING_LITERAL {
+			return hir.NewString(factor.Factor.(lexer.Token).Literal.(string)), nil
+		} else if factor.Factor.(lexer.Token).Type == lexer.CHAR_LITERAL {
+			// 空字符字面量''的值为0
+			ch, _ := factor.Factor.(lexer.Token).Literal.(rune)
+			return hir.NewChar(ch), nil
 		} else {
 			return nil, errors.New(fmt.Sprintf("unknown factor %s", factor.Factor.(lexer.Token).Literal))
 		}
@@ -562,3 +991,97 @@ func (a *Analyser) analyseFactor(factor ast.Factor) (hir.Factor, error) {
 		return nil, errors.New(fmt.Sprintf("unknown factor %s", factor.Factor))
 	}
 }
+
+// checkArrayDecl 检查数组声明，数组的长度必须为正数，且数组不能有初始值
+func (a *Analyser) checkArrayDecl(decl ast.TypeIDPair) error {
+	if decl.Dim == nil {
+		return nil
+	}
+	if length, _ := decl.Dim.Len.Literal.(int64); length <= 0 {
+		return errors.New(fmt.Sprintf("array %s must have a positive length, but got %v in %s", decl.ID.Literal.(string), decl.Dim.Len.Literal, a.scopeName()))
+	}
+	if decl.Init != nil {
+		return errors.New(fmt.Sprintf("array %s cannot be initialised in %s", decl.ID.Literal.(string), a.scopeName()))
+	}
+	return nil
+}
+
+// analyseIndex 对数组元素进行语义分析，下标必须为整数，常量下标必须在数组范围内
+func (a *Analyser) analyseIndex(id ast.ID, index ast.Index) (*hir.IndexExp, error) {
+	name := id.Literal.(string)
+	// 方法名不能作为数组
+	if a.methods.HasSymbol(name) {
+		return nil, errors.New(fmt.Sprintf("%s is a method, but used as an array", name))
+	}
+	// 作用域中是否存在变量
+	if !a.hasVar(name) {
+		return nil, errors.New(fmt.Sprintf("variable %s is not defined in %s", name, a.scopeName()))
+	}
+	t := a.typeOfExp(hir.ID(name))
+	if !t.IsArray() {
+		return nil, errors.New(fmt.Sprintf("variable %s of type %s is not an array in %s", name, a.typeName(t), a.scopeName()))
+	}
+
+	// 分析下标表达式
+	resExp, err := a.analyseExp(index.Exp)
+	if err != nil {
+		return nil, err
+	}
+	if indexType := a.typeOfExp(resExp); indexType != hir.TInteger {
+		return nil, errors.New(fmt.Sprintf("index of array %s must be int, but got %s in %s", name, a.typeName(indexType), a.scopeName()))
+	}
+	// 常量下标在编译时检查是否越界
+	if val, ok := constIndex(resExp); ok && (val < 0 || val >= int64(t.Len())) {
+		return nil, errors.New(fmt.Sprintf("index %d is out of range for array %s of type %s in %s", val, name, a.typeName(t), a.scopeName()))
+	}
+
+	// 使用了数组，从未使用变量列表中删除
+	a.unusedVars.RemoveSymbol(name)
+
+	return hir.NewIndexExp(name, resExp, t), nil
+}
+
+// constIndex 获取常量下标的值，下标为整数字面量或其相反数时ok为true
+func constIndex(exp hir.Exp) (int64, bool) {
+	switch e := exp.(type) {
+	case *hir.Integer:
+		return e.Val, true
+	case *hir.UnaryExp:
+		if val, ok := constIndex(e.Exp); ok && e.Op == ast.NEG {
+			return -val, true
+		}
+	}
+	return 0, false
+}
+
+// analyseField 对结构体字段进行语义分析，返回字段路径（如p.q.x）及字段类型
+func (a *Analyser) analyseField(id ast.ID, selectors []ast.Selector) (string, hir.Type, error) {
+	name := id.Literal.(string)
+	// 方法名不能作为结构体
+	if a.methods.HasSymbol(name) {
+		return "", hir.TErr, errors.New(fmt.Sprintf("%s is a method, but used as a struct", name))
+	}
+	// 作用域中是否存在变量
+	if !a.hasVar(name) {
+		return "", hir.TErr, errors.New(fmt.Sprintf("variable %s is not defined in %s", name, a.scopeName()))
+	}
+
+	// 逐个字段查找类型
+	path, t := name, a.typeOfExp(hir.ID(name))
+	for _, selector := range selectors {
+		if !t.IsStruct() {
+			return "", hir.TErr, errors.New(fmt.Sprintf("variable %s of type %s is not a struct in %s", path, a.typeName(t), a.scopeName()))
+		}
+		s := a.structs[t.StructIndex()]
+		field, ok := s.GetField(selector.Field.Literal.(string))
+		if !ok {
+			return "", hir.TErr, errors.New(fmt.Sprintf("struct %s has no field %s in %s", s.Name, selector.Field.Literal.(string), a.scopeName()))
+		}
+		path, t = path+"."+string(field.ID), field.Type
+	}
+
+	// 使用了结构体，从未使用变量列表中删除
+	a.unusedVars.RemoveSymbol(name)
+
+	return path, t, nil
+}
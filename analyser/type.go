@@ -0,0 +1,102 @@
+package analyser
+
+import (
+	"CompilerInGo/hir"
+	"CompilerInGo/parser/ast"
+	"strings"
+)
+
+// typeOfExp 推导表达式的类型
+func (a *Analyser) typeOfExp(exp hir.Exp) hir.Type {
+	switch exp.(type) {
+	case *hir.BinaryExp:
+		binaryExp := exp.(*hir.BinaryExp)
+		switch binaryExp.Op {
+		case ast.PLUS, ast.MINUS, ast.TIMES, ast.DIVIDE, ast.MOD:
+			return arithmeticType(a.typeOfExp(binaryExp.LExp), a.typeOfExp(binaryExp.RExp))
+		case ast.CONCAT:
+			return hir.TString
+		default:
+			// 比较运算及逻辑运算的结果为整数0或1
+			return hir.TInteger
+		}
+	case *hir.UnaryExp:
+		unaryExp := exp.(*hir.UnaryExp)
+		if unaryExp.Op == ast.NOT {
+			return hir.TInteger
+		}
+		// 负号不改变数值类型
+		if t := a.typeOfExp(unaryExp.Exp); isNumeric(t) {
+			return t
+		}
+		return hir.TErr
+	case hir.ID:
+		return a.varType(string(exp.(hir.ID)))
+	case *hir.StructExp:
+		return exp.(*hir.StructExp).Type
+	case *hir.IndexExp:
+		// 数组的元素类型
+		return exp.(*hir.IndexExp).Type.Elem()
+	case *hir.Integer:
+		return hir.TInteger
+	case *hir.Float:
+		return hir.TFloat
+	case *hir.Char:
+		return hir.TChar
+	case *hir.String:
+		return hir.TString
+	case *hir.CallExp:
+		// 方法的返回值类型
+		method, _ := a.methods.GetSymbol(exp.(*hir.CallExp).Method)
+		return hir.Type(method.ReturnType)
+	default:
+		return hir.TErr
+	}
+}
+
+// varType 变量的声明类型，name为结构体字段路径时为字段的类型
+func (a *Analyser) varType(name string) hir.Type {
+	path := strings.Split(name, ".")
+	// 局部变量不会遮蔽全局变量
+	var t hir.Type
+	if global, ok := a.globals.GetSymbol(path[0]); ok {
+		t = global.Type
+	} else {
+		t, _ = a.scope.GetSymbol(path[0])
+	}
+	for _, field := range path[1:] {
+		if !t.IsStruct() {
+			return hir.TErr
+		}
+		pair, _ := a.structs[t.StructIndex()].GetField(field)
+		t = pair.Type
+	}
+	return t
+}
+
+// arithmeticType 算术运算结果的类型，整数与小数运算的结果为小数
+func arithmeticType(l, r hir.Type) hir.Type {
+	switch {
+	case l == r:
+		return l
+	case isNumeric(l) && isNumeric(r):
+		return hir.TFloat
+	default:
+		return hir.TErr
+	}
+}
+
+// assignable 判断类型为from的值能否赋给类型为to的变量，整数与小数之间可以隐式转换
+func assignable(to, from hir.Type) bool {
+	return to == from || (isNumeric(to) && isNumeric(from))
+}
+
+// isComparable 判断类型为l、r的值能否比较，数值之间可以比较，字符、字符串只能与相同类型比较
+func isComparable(l, r hir.Type) bool {
+	return (isNumeric(l) && isNumeric(r)) || (l == r && (l == hir.TChar || l == hir.TString))
+}
+
+// isNumeric 判断是否为数值类型
+func isNumeric(t hir.Type) bool {
+	return t == hir.TInteger || t == hir.TFloat
+}
@@ -0,0 +1,128 @@
+package doc
+
+import (
+	"CompilerInGo/hir"
+	"fmt"
+	"html"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// sortedMethods 按方法名排序的方法列表
+func sortedMethods(program *hir.Program) []hir.Method {
+	methods := make([]hir.Method, len(program.Methods))
+	copy(methods, program.Methods)
+	sort.Slice(methods, func(i, j int) bool {
+		return methods[i].Name < methods[j].Name
+	})
+	return methods
+}
+
+// Signature 获取方法签名，形如 int add(int a, int b)，结构体参数的类型为结构体名
+func Signature(program *hir.Program, method hir.Method) string {
+	params := make([]string, 0, len(method.Params))
+	for _, param := range method.Params {
+		params = append(params, fmt.Sprintf("%s %s", program.TypeName(param.Type), param.ID))
+	}
+	return fmt.Sprintf("%s %s(%s)", method.ReturnType, method.Name, strings.Join(params, ", "))
+}
+
+// Markdown 生成Markdown格式的方法参考文档
+func Markdown(program *hir.Program, title string) string {
+	var str strings.Builder
+	methods := sortedMethods(program)
+
+	// 标题及目录
+	str.WriteString(fmt.Sprintf("# %s\n\n", title))
+	str.WriteString("## Methods\n\n")
+	for _, method := range methods {
+		str.WriteString(fmt.Sprintf("- [%s](#%s)\n", method.Name, strings.ToLower(method.Name)))
+	}
+
+	for _, method := range methods {
+		// 方法签名
+		str.WriteString(fmt.Sprintf("\n## %s\n\n", method.Name))
+		str.WriteString(fmt.Sprintf("```\n%s\n```\n\n", Signature(program, method)))
+
+		// 文档注释
+		if method.Doc != "" {
+			str.WriteString(method.Doc + "\n\n")
+		}
+
+		// 参数表
+		if len(method.Params) > 0 {
+			str.WriteString("**Parameters**\n\n")
+			str.WriteString("| Name | Type |\n| --- | --- |\n")
+			for _, param := range method.Params {
+				str.WriteString(fmt.Sprintf("| `%s` | `%s` |\n", param.ID, program.TypeName(param.Type)))
+			}
+			str.WriteString("\n")
+		}
+
+		// 返回值类型
+		str.WriteString(fmt.Sprintf("**Returns** `%s`\n", method.ReturnType))
+	}
+
+	return str.String()
+}
+
+// HTML 生成HTML格式的方法参考文档
+func HTML(program *hir.Program, title string) string {
+	var str strings.Builder
+	methods := sortedMethods(program)
+
+	// 页面头部
+	str.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	str.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	str.WriteString("<style>.doc { white-space: pre-line; }</style>\n")
+	str.WriteString("</head>\n<body>\n")
+
+	// 标题及目录
+	str.WriteString(fmt.Sprintf("<h1>%s</h1>\n", html.EscapeString(title)))
+	str.WriteString("<h2>Methods</h2>\n<ul>\n")
+	for _, method := range methods {
+		name := html.EscapeString(method.Name)
+		str.WriteString(fmt.Sprintf("<li><a href=\"#%s\">%s</a></li>\n", name, name))
+	}
+	str.WriteString("</ul>\n")
+
+	for _, method := range methods {
+		// 方法签名
+		str.WriteString(fmt.Sprintf("<h2 id=\"%s\">%s</h2>\n", html.EscapeString(method.Name), html.EscapeString(method.Name)))
+		str.WriteString(fmt.Sprintf("<pre><code>%s</code></pre>\n", html.EscapeString(Signature(program, method))))
+
+		// 文档注释
+		if method.Doc != "" {
+			str.WriteString(fmt.Sprintf("<p class=\"doc\">%s</p>\n", html.EscapeString(method.Doc)))
+		}
+
+		// 参数表
+		if len(method.Params) > 0 {
+			str.WriteString("<h3>Parameters</h3>\n<table>\n<tr><th>Name</th><th>Type</th></tr>\n")
+			for _, param := range method.Params {
+				str.WriteString(fmt.Sprintf("<tr><td><code>%s</code></td><td><code>%s</code></td></tr>\n", html.EscapeString(string(param.ID)), html.EscapeString(program.TypeName(param.Type))))
+			}
+			str.WriteString("</table>\n")
+		}
+
+		// 返回值类型
+		str.WriteString(fmt.Sprintf("<p><strong>Returns</strong> <code>%s</code></p>\n", method.ReturnType))
+	}
+
+	str.WriteString("</body>\n</html>\n")
+
+	return str.String()
+}
+
+// Write 在dir目录下写入Markdown及HTML格式的方法参考文档，文件名为name.md及name.html
+func Write(program *hir.Program, name string, dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name+".md"), []byte(Markdown(program, name)), 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+".html"), []byte(HTML(program, name)), 0o644)
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/doc"
+	"CompilerInGo/lexer"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"flag"
+	"github.com/kpango/glg"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// runDoc doc子命令，根据方法的文档注释生成Markdown及HTML格式的参考文档
+func runDoc(args []string) {
+	// 解析命令行参数
+	flags := flag.NewFlagSet("doc", flag.ExitOnError)
+	filepathFlag := flags.String("f", "./test.program", "input source program (\"-\" for stdin)")
+	output := flags.String("o", "./docs", "output directory")
+	mode := flags.String("m", "INFO", "logger mode (DEBUG, INFO, CLOSE)")
+	_ = flags.Parse(args)
+
+	// 初始化logger
+	utils.InitLogger(*mode)
+
+	// 词法分析
+	var lex *lexer.Lexer
+	name := "stdin"
+	if *filepathFlag == "-" {
+		lex = lexer.NewLexerFromReader("<stdin>", os.Stdin)
+	} else {
+		lex = lexer.NewLexer(*filepathFlag)
+		name = strings.TrimSuffix(filepath.Base(*filepathFlag), filepath.Ext(*filepathFlag))
+	}
+	tokens := lex.Tokenize()
+	if lex.HasErrors() {
+		lex.ReportErrors()
+		glg.Fatal("Lexing finished with ", len(lex.Errors), " errors")
+	}
+
+	// 语法分析
+	pser := parser.NewParserFromTokens(tokens.Pool)
+	pser.SetSource(lex.Name, lex.Lines())
+	program, err := pser.Parse()
+	if pser.HasErrors() {
+		pser.ReportErrors()
+		glg.Fatal("Parsing finished with ", len(pser.Errors), " errors")
+	}
+	if err != nil {
+		glg.Fatal(err)
+	}
+
+	// 语义分析，文档注释随方法进入HIR
+	hirProgram, errs := analyser.NewAnalyser().Analyse(program)
+	if errs != 0 {
+		glg.Fatal("Analysing finished with ", errs, " errors")
+	}
+
+	// 写入文档
+	if err := doc.Write(hirProgram, name, *output); err != nil {
+		glg.Fatal(err)
+	}
+	_ = glg.Infof("Documentation written to %s", filepath.Join(*output, name+".{md,html}"))
+}
@@ -45,8 +45,14 @@ func (resultType *AstResultType) ToHIR() ResultType {
 	}
 }
 
+// ToHIR 转换为HIR中的类型-标识符对，声明了长度时为数组类型
 func (typeIDPair AstTypeIDPair) ToHIR() *TypeIDPair {
-	return NewTypeIDPair(AstType(typeIDPair.Type).ToHIR(), typeIDPair.ID.Literal.(string))
+	t := AstType(typeIDPair.Type).ToHIR()
+	if typeIDPair.Dim != nil {
+		length, _ := typeIDPair.Dim.Len.Literal.(int64)
+		t = NewArrayType(t, int(length))
+	}
+	return NewTypeIDPair(t, typeIDPair.ID.Literal.(string))
 }
 
 func (paramList *AstParamList) ToHIR() []*TypeIDPair {
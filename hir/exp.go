@@ -1,117 +1,89 @@
 package hir
 
-import (
-	"CompilerInGo/parser/ast"
-)
-
 // 将ast中的表达式转换为hir中的表达式类型
 
-type ConditionalExp struct {
-	LExp RelationExp
-	Op   int
-	RExp RelationExp
+// Exp 表达式，可以是*BinaryExp, *UnaryExp, *CallExp, *IndexExp, *StructExp, ID, *Integer, *Float, *Char, *String
+// 结构体字段为以.分隔的路径，如ID("p.x")
+type Exp interface {
+	exp()
 }
 
-type RelationExp struct {
-	LExp CompExp
+// BinaryExp 二元表达式，Op为ast中的运算符
+// 算术运算及比较运算的结果为数值，and、or短路求值
+type BinaryExp struct {
 	Op   int
-	RExp CompExp
-}
-
-type CompExp struct {
 	LExp Exp
-	Op   int
 	RExp Exp
 }
 
-type Exp struct {
-	LTerm Term
-	Op    int
-	RTerm Term
+// UnaryExp 一元表达式，Op为ast.NEG或ast.NOT
+type UnaryExp struct {
+	Op  int
+	Exp Exp
 }
 
-type Term struct {
-	LFactor Factor
-	Op      int
-	RFactor Factor
+// CallExp 方法调用表达式，值为方法的返回值
+type CallExp struct {
+	Method   string
+	ActParam []Exp
 }
 
-type Factor interface {
-	factor()
+// IndexExp 数组元素，Type为数组的类型，生成中间代码时用于下标越界检查
+type IndexExp struct {
+	Array ID
+	Index Exp
+	Type  Type
 }
 
-func NewConditionalExp(lExp *RelationExp, rExp *RelationExp) ConditionalExp {
-	if rExp == nil {
-		return ConditionalExp{
-			LExp: *lExp,
-			Op:   ast.EMPTY,
-		}
-	}
+// StructExp 整个结构体变量的值，Var为变量名或字段路径，用于结构体的赋值及传参
+type StructExp struct {
+	Var  ID
+	Type Type
+}
 
-	return ConditionalExp{
-		LExp: *lExp,
-		Op:   ast.OR,
-		RExp: *rExp,
+func NewBinaryExp(op int, lExp, rExp Exp) *BinaryExp {
+	return &BinaryExp{
+		Op:   op,
+		LExp: lExp,
+		RExp: rExp,
 	}
 }
 
-func NewRelationExp(lExp *CompExp, rExp *CompExp) RelationExp {
-	if rExp == nil {
-		return RelationExp{
-			LExp: *lExp,
-			Op:   ast.EMPTY,
-		}
+func NewUnaryExp(op int, exp Exp) *UnaryExp {
+	return &UnaryExp{
+		Op:  op,
+		Exp: exp,
 	}
+}
 
-	return RelationExp{
-		LExp: *lExp,
-		Op:   ast.AND,
-		RExp: *rExp,
+func NewCallExp(method string, actParam []Exp) *CallExp {
+	return &CallExp{
+		Method:   method,
+		ActParam: actParam,
 	}
 }
 
-func NewCompExp(lExp *Exp, op int, rExp *Exp) CompExp {
-	if op == ast.EMPTY || rExp == nil {
-		return CompExp{
-			LExp: *lExp,
-			Op:   ast.EMPTY,
-		}
+func NewIndexExp(array string, index Exp, t Type) *IndexExp {
+	return &IndexExp{
+		Array: ID(array),
+		Index: index,
+		Type:  t,
 	}
+}
 
-	return CompExp{
-		LExp: *lExp,
-		Op:   op,
-		RExp: *rExp,
+func NewStructExp(v string, t Type) *StructExp {
+	return &StructExp{
+		Var:  ID(v),
+		Type: t,
 	}
 }
 
-func NewExp(lTerm *Term, op int, rTerm *Term) Exp {
-	if op == ast.EMPTY || rTerm == nil {
-		return Exp{
-			LTerm: *lTerm,
-			Op:    ast.EMPTY,
-		}
-	}
+func (b BinaryExp) exp() {}
 
-	return Exp{
-		LTerm: *lTerm,
-		Op:    op,
-		RTerm: *rTerm,
-	}
-}
+func (u UnaryExp) exp() {}
 
-func NewTerm(lFactor *Factor, op int, rFactor *Factor) Term {
-	if op == ast.EMPTY || rFactor == nil {
-		return Term{
-			LFactor: *lFactor,
-			Op:      ast.EMPTY,
-		}
-	}
-	return Term{
-		LFactor: *lFactor,
-		Op:      op,
-		RFactor: *rFactor,
-	}
-}
+func (c CallExp) exp() {}
+
+func (i IndexExp) exp() {}
 
-func (e Exp) factor() {}
+func (s StructExp) exp() {}
@@ -1,24 +1,61 @@
 package hir
 
-// Program AST树的HIR表示，由多个Method组成
+// Program AST树的HIR表示，由结构体、全局变量、常量及多个Method组成
 type Program struct {
+	Structs []Struct // 按声明顺序排列，下标即结构体类型中记录的下标
+	Globals []Global // 按声明顺序排列
 	Methods []Method
 }
 
+// Struct 结构体，Fields按声明顺序排列
+type Struct struct {
+	Name   string
+	Type   Type
+	Fields []TypeIDPair
+}
+
+// Global 全局变量或常量，Init为nil时没有初始值
+type Global struct {
+	Type  Type
+	ID    ID
+	Const bool
+	Init  Exp
+}
+
 // Method HIR中的方法，由返回类型，方法名，参数列表和方法体组成
 type Method struct {
 	ReturnType ResultType
 	Name       string
 	Params     []*TypeIDPair
 	Body       *Statement
+	Doc        string // 文档注释
 }
 
-func NewProgram(methods []Method) *Program {
+func NewProgram(structs []Struct, globals []Global, methods []Method) *Program {
 	return &Program{
+		Structs: structs,
+		Globals: globals,
 		Methods: methods,
 	}
 }
 
+func NewGlobal(t Type, id string, isConst bool, init Exp) *Global {
+	return &Global{
+		Type:  t,
+		ID:    ID(id),
+		Const: isConst,
+		Init:  init,
+	}
+}
+
+func NewStruct(name string, t Type, fields []TypeIDPair) *Struct {
+	return &Struct{
+		Name:   name,
+		Type:   t,
+		Fields: fields,
+	}
+}
+
 func NewMethod(t ResultType, name string, params []*TypeIDPair, body *Statement) *Method {
 	return &Method{
 		ReturnType: t,
@@ -28,6 +65,21 @@ func NewMethod(t ResultType, name string, params []*TypeIDPair, body *Statement)
 	}
 }
 
+// GetField 根据字段名获取结构体的字段
+func (s Struct) GetField(name string) (TypeIDPair, bool) {
+	for _, field := range s.Fields {
+		if string(field.ID) == name {
+			return field, true
+		}
+	}
+	return TypeIDPair{}, false
+}
+
+// TypeName 获取类型在源程序中的名称，结构体类型为结构体名
+func (p Program) TypeName(t Type) string {
+	return TypeName(t, p.Structs)
+}
+
 // GetMethod 根据方法名获取方法
 func (p Program) GetMethod(name string) *Method {
 	for _, method := range p.Methods {
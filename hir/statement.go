@@ -7,23 +7,55 @@ type Statement interface {
 }
 
 type ConditionalStatement struct {
-	Condition ConditionalExp
+	Condition Exp
 	IfBody    *Statement
 	ElseBody  *Statement
 }
 
 type LoopStatement struct {
-	Condition ConditionalExp
+	Condition Exp
 	Body      *Statement
 }
 
+// ForStatement for循环，Init、Step为nil时没有初始化、步进语句，Condition为nil时总是继续循环
+type ForStatement struct {
+	Init      *Statement
+	Condition Exp
+	Step      *Statement
+	Body      *Statement
+}
+
+// DoWhileStatement do-while循环，先执行循环体再判断条件
+type DoWhileStatement struct {
+	Body      *Statement
+	Condition Exp
+}
+
+// SwitchStatement switch语句，Type为被匹配的值的类型，Cases按源程序顺序排列
+// 从匹配的分支开始依次执行之后的全部分支，直到break
+type SwitchStatement struct {
+	Exp   Exp
+	Type  Type
+	Cases []SwitchCase
+}
+
+// SwitchCase switch语句的分支，Value为case常量的值，字符常量为其编码，Default为true时为default分支
+// Body为nil时分支中没有语句
+type SwitchCase struct {
+	Value   int64
+	Default bool
+	Body    *Statement
+}
+
 type CallStatement struct {
 	Method   string
 	ActParam []Exp
 }
 
+// AssignStatement 赋值语句，Index不为nil时为数组元素赋值
 type AssignStatement struct {
 	Target string
+	Index  *IndexExp
 	Exp    Exp
 }
 
@@ -35,8 +67,21 @@ type BreakStatement struct{}
 
 type ContinueStatement struct{}
 
+// ReadStatement 输入语句，按变量的类型依次读取
+type ReadStatement struct {
+	Vars []TypeIDPair
+}
+
+// WriteStatement 输出语句，依次输出表达式的值并换行，Types[i]为Exps[i]的类型
+type WriteStatement struct {
+	Exps  []Exp
+	Types []Type
+}
+
+// LocalVariableDeclaration 局部变量声明，Inits[i]为第i个变量的初始值，没有初始值时为nil
 type LocalVariableDeclaration struct {
 	TypeIDPair []TypeIDPair
+	Inits      []Exp
 }
 
 type Block struct {
@@ -45,7 +90,7 @@ type Block struct {
 
 func (c ConditionalStatement) stmt() {}
 
-func NewConditionalStatement(condition ConditionalExp, ifBody, elseBody *Statement) ConditionalStatement {
+func NewConditionalStatement(condition Exp, ifBody, elseBody *Statement) ConditionalStatement {
 	return ConditionalStatement{
 		Condition: condition,
 		IfBody:    ifBody,
@@ -55,13 +100,51 @@ func NewConditionalStatement(condition ConditionalExp, ifBody, elseBody *Stateme
 
 func (l LoopStatement) stmt() {}
 
-func NewLoopStatement(condition ConditionalExp, body *Statement) LoopStatement {
+func NewLoopStatement(condition Exp, body *Statement) LoopStatement {
 	return LoopStatement{
 		Condition: condition,
 		Body:      body,
 	}
 }
 
+func (f ForStatement) stmt() {}
+
+func NewForStatement(init *Statement, condition Exp, step, body *Statement) ForStatement {
+	return ForStatement{
+		Init:      init,
+		Condition: condition,
+		Step:      step,
+		Body:      body,
+	}
+}
+
+func (d DoWhileStatement) stmt() {}
+
+func NewDoWhileStatement(body *Statement, condition Exp) DoWhileStatement {
+	return DoWhileStatement{
+		Body:      body,
+		Condition: condition,
+	}
+}
+
+func (s SwitchStatement) stmt() {}
+
+func NewSwitchStatement(exp Exp, t Type, cases []SwitchCase) SwitchStatement {
+	return SwitchStatement{
+		Exp:   exp,
+		Type:  t,
+		Cases: cases,
+	}
+}
+
+func NewSwitchCase(value int64, isDefault bool, body *Statement) SwitchCase {
+	return SwitchCase{
+		Value:   value,
+		Default: isDefault,
+		Body:    body,
+	}
+}
+
 func (c CallStatement) stmt() {}
 
 func NewCallStatement(method string, actParam []Exp) CallStatement {
@@ -80,6 +163,15 @@ func NewAssignStatement(target string, exp Exp) AssignStatement {
 	}
 }
 
+// NewIndexAssignStatement 创建数组元素赋值语句
+func NewIndexAssignStatement(index *IndexExp, exp Exp) AssignStatement {
+	return AssignStatement{
+		Target: string(index.Array),
+		Index:  index,
+		Exp:    exp,
+	}
+}
+
 func (r ReturnStatement) stmt() {}
 
 func NewReturnStatement(exp Exp) ReturnStatement {
@@ -100,11 +192,29 @@ func NewContinueStatement() ContinueStatement {
 	return ContinueStatement{}
 }
 
+func (r ReadStatement) stmt() {}
+
+func NewReadStatement(vars []TypeIDPair) ReadStatement {
+	return ReadStatement{
+		Vars: vars,
+	}
+}
+
+func (w WriteStatement) stmt() {}
+
+func NewWriteStatement(exps []Exp, types []Type) WriteStatement {
+	return WriteStatement{
+		Exps:  exps,
+		Types: types,
+	}
+}
+
 func (l LocalVariableDeclaration) stmt() {}
 
-func NewLocalVariableDeclaration(typeIDPair []TypeIDPair) LocalVariableDeclaration {
+func NewLocalVariableDeclaration(typeIDPair []TypeIDPair, inits []Exp) LocalVariableDeclaration {
 	return LocalVariableDeclaration{
 		TypeIDPair: typeIDPair,
+		Inits:      inits,
 	}
 }
 
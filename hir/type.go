@@ -21,6 +21,84 @@ const (
 	TVoid
 )
 
+// TypeString 类型对应的源程序关键字，输出时使用
+var TypeString = map[int]string{
+	TErr:     "error",
+	TInteger: "int",
+	TFloat:   "float",
+	TChar:    "char",
+	TString:  "string",
+	TVoid:    "void",
+}
+
+// 数组类型在Type中的编码：低8位为元素类型，arrayBit标记数组，arrayLenShift之上的位为数组长度
+// 结构体类型在Type中的编码：structBit标记结构体，arrayLenShift之上的位为结构体在Program.Structs中的下标
+const (
+	arrayBit      = 1 << 8
+	structBit     = 1 << 9
+	arrayLenShift = 16
+)
+
+// NewArrayType 创建元素类型为elem、长度为length的数组类型
+func NewArrayType(elem Type, length int) Type {
+	return Type(arrayBit | length<<arrayLenShift | int(elem))
+}
+
+// IsArray 判断是否为数组类型
+func (t Type) IsArray() bool {
+	return int(t)&arrayBit != 0
+}
+
+// Elem 获取数组的元素类型，不是数组时为类型本身
+func (t Type) Elem() Type {
+	return t & (arrayBit - 1)
+}
+
+// Len 获取数组的长度，不是数组时为0
+func (t Type) Len() int {
+	return int(t) >> arrayLenShift
+}
+
+// NewStructType 创建下标为index的结构体类型
+func NewStructType(index int) Type {
+	return Type(structBit | index<<arrayLenShift)
+}
+
+// IsStruct 判断是否为结构体类型
+func (t Type) IsStruct() bool {
+	return int(t)&structBit != 0
+}
+
+// StructIndex 获取结构体在Program.Structs中的下标
+func (t Type) StructIndex() int {
+	return int(t) >> arrayLenShift
+}
+
+// String 获取类型的字符串表示，数组类型如int[10]
+// 结构体类型的名称需要结构体表，见TypeName
+func (t Type) String() string {
+	if t.IsArray() {
+		return fmt.Sprintf("%s[%d]", t.Elem(), t.Len())
+	}
+	if t.IsStruct() {
+		return "struct"
+	}
+	return TypeString[int(t)]
+}
+
+// TypeName 获取类型在源程序中的名称，结构体类型为结构体名
+func TypeName(t Type, structs []Struct) string {
+	if t.IsStruct() && t.StructIndex() < len(structs) {
+		return structs[t.StructIndex()].Name
+	}
+	return t.String()
+}
+
+// String 获取返回值类型的字符串表示
+func (t ResultType) String() string {
+	return TypeString[int(t)]
+}
+
 type ID string
 
 type TypeIDPair struct {
@@ -52,7 +130,7 @@ func NewInteger(val int64) *Integer {
 	return &Integer{Val: val}
 }
 
-func (i ID) factor() {}
+func (i ID) exp() {}
 
 func (i Integer) GetVal() int64 {
 	return i.Val
@@ -60,7 +138,7 @@ func (i Integer) GetVal() int64 {
 
 func (i Integer) lit() {}
 
-func (i Integer) factor() {}
+func (i Integer) exp() {}
 
 func NewFloat(val float64) *Float {
 	return &Float{Val: val}
@@ -72,7 +150,7 @@ func (f Float) GetVal() float64 {
 
 func (f Float) lit() {}
 
-func (f Float) factor() {}
+func (f Float) exp() {}
 
 func NewChar(val rune) *Char {
 	return &Char{Val: val}
@@ -84,6 +162,8 @@ func (c Char) GetVal() rune {
 
 func (c Char) lit() {}
 
+func (c Char) exp() {}
+
 func NewString(val string) *String {
 	return &String{Val: val}
 }
@@ -94,6 +174,8 @@ func (s String) GetVal() string {
 
 func (s String) lit() {}
 
+func (s String) exp() {}
+
 func VarToStr(id int) string {
 	return fmt.Sprintf("_T%d", id)
 }
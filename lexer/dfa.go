@@ -0,0 +1,575 @@
+package lexer
+
+import (
+	"CompilerInGo/utils"
+	"fmt"
+	"sort"
+	"unicode"
+	"unicode/utf8"
+)
+
+// 内置字符类在非ASCII字符签名中对应的位
+const (
+	digitClass      = 1 << iota // unicode.IsDigit
+	letterClass                 // unicode.IsLetter
+	identStartClass             // Unicode XID_Start
+	identPartClass              // Unicode XID_Continue
+
+	signatureCount = 1 << iota // 非ASCII字符签名的数量
+)
+
+// builtinClass 内置字符类
+type builtinClass struct {
+	match func(r rune) bool // 判断字符是否属于该字符类
+	bit   uint8             // 非ASCII字符签名中对应的位，为0表示只含ASCII字符
+}
+
+// builtinClasses 内置字符类，规则中以{name}引用
+var builtinClasses = map[string]builtinClass{
+	"digit":      {match: unicode.IsDigit, bit: digitClass},
+	"letter":     {match: unicode.IsLetter, bit: letterClass},
+	"identStart": {match: isIdentifierStart, bit: identStartClass},
+	"identPart":  {match: isIdentifierPart, bit: identPartClass},
+	"hex": {match: func(r rune) bool {
+		return unicode.Is(unicode.ASCII_Hex_Digit, r)
+	}},
+}
+
+// signature 获取非ASCII字符的签名，即其所属的内置字符类
+// 签名相同的非ASCII字符属于相同的字符集，因而可以共用一个等价类
+func signature(r rune) uint8 {
+	var sig uint8
+	if unicode.IsDigit(r) {
+		sig |= digitClass
+	}
+	if unicode.IsLetter(r) {
+		sig |= letterClass
+	}
+	if isIdentifierStart(r) {
+		sig |= identStartClass
+	}
+	if isIdentifierPart(r) {
+		sig |= identPartClass
+	}
+	return sig
+}
+
+// charSet 字符集
+type charSet struct {
+	ascii   [utf8.RuneSelf]bool // 包含的ASCII字符
+	unicode uint8               // 包含的非ASCII字符，为内置字符类签名位的并集
+	negate  bool                // 是否取补集
+}
+
+// contains 判断字符是否属于字符集，非ASCII字符按签名sig判断
+func (s *charSet) contains(r rune, sig uint8) bool {
+	var in bool
+	if r < utf8.RuneSelf {
+		in = s.ascii[r]
+	} else {
+		in = s.unicode&sig != 0
+	}
+	return in != s.negate
+}
+
+// addClass 向字符集中加入内置字符类
+func (s *charSet) addClass(class builtinClass) {
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		if class.match(r) {
+			s.ascii[r] = true
+		}
+	}
+	s.unicode |= class.bit
+}
+
+// nfaState NFA状态，至多有一条字符边
+type nfaState struct {
+	eps    []int // 空边的目标状态
+	set    int   // 字符边的字符集下标，-1表示没有字符边
+	next   int   // 字符边的目标状态
+	accept int   // 接受的规则下标，-1表示不接受
+}
+
+// nfa 由全部规则构造的NFA
+type nfa struct {
+	states []nfaState
+	sets   []charSet
+}
+
+// fragment NFA片段，只有一个开始状态与一个结束状态
+type fragment struct {
+	start int
+	end   int
+}
+
+// newState 创建一个NFA状态
+func (n *nfa) newState() int {
+	n.states = append(n.states, nfaState{set: -1, accept: -1})
+	return len(n.states) - 1
+}
+
+// epsilon 添加一条空边
+func (n *nfa) epsilon(from int, to int) {
+	n.states[from].eps = append(n.states[from].eps, to)
+}
+
+// charFragment 创建匹配一个字符集中字符的片段
+func (n *nfa) charFragment(set charSet) fragment {
+	n.sets = append(n.sets, set)
+	f := fragment{start: n.newState(), end: n.newState()}
+	n.states[f.start].set = len(n.sets) - 1
+	n.states[f.start].next = f.end
+	return f
+}
+
+// emptyFragment 创建匹配空串的片段
+func (n *nfa) emptyFragment() fragment {
+	f := fragment{start: n.newState(), end: n.newState()}
+	n.epsilon(f.start, f.end)
+	return f
+}
+
+// concat 连接两个片段
+func (n *nfa) concat(a fragment, b fragment) fragment {
+	n.epsilon(a.end, b.start)
+	return fragment{start: a.start, end: b.end}
+}
+
+// alternate 选择两个片段之一
+func (n *nfa) alternate(a fragment, b fragment) fragment {
+	f := fragment{start: n.newState(), end: n.newState()}
+	n.epsilon(f.start, a.start)
+	n.epsilon(f.start, b.start)
+	n.epsilon(a.end, f.end)
+	n.epsilon(b.end, f.end)
+	return f
+}
+
+// repeat 重复片段，op为*、+或?
+func (n *nfa) repeat(a fragment, op rune) fragment {
+	f := fragment{start: n.newState(), end: n.newState()}
+	n.epsilon(f.start, a.start)
+	n.epsilon(a.end, f.end)
+	if op != '+' {
+		// 可以不匹配
+		n.epsilon(f.start, f.end)
+	}
+	if op != '?' {
+		// 可以重复匹配
+		n.epsilon(a.end, a.start)
+	}
+	return f
+}
+
+// regexParser 规则正则表达式的递归下降解析器，解析的同时构造NFA片段
+type regexParser struct {
+	pattern   []rune            // 正则表达式
+	pos       int               // 当前位置
+	defs      map[string]string // 命名的子表达式
+	expanding []string          // 正在展开的定义，用于检查循环引用
+	nfa       *nfa
+}
+
+// errorf 创建带有位置的正则表达式错误
+func (p *regexParser) errorf(format string, args ...any) error {
+	return utils.NewErrorf("pattern %q at %d: %s", string(p.pattern), p.pos, fmt.Sprintf(format, args...))
+}
+
+// peek 查看当前字符，结束时返回0
+func (p *regexParser) peek() rune {
+	if p.pos >= len(p.pattern) {
+		return 0
+	}
+	return p.pattern[p.pos]
+}
+
+// parse 解析整个正则表达式
+func (p *regexParser) parse() (fragment, error) {
+	f, err := p.parseAlternate()
+	if err != nil {
+		return fragment{}, err
+	}
+	if p.pos < len(p.pattern) {
+		return fragment{}, p.errorf("unexpected %q", p.peek())
+	}
+	return f, nil
+}
+
+// parseAlternate 解析选择 x|y
+func (p *regexParser) parseAlternate() (fragment, error) {
+	f, err := p.parseConcat()
+	if err != nil {
+		return fragment{}, err
+	}
+	for p.peek() == '|' {
+		p.pos++
+		g, err := p.parseConcat()
+		if err != nil {
+			return fragment{}, err
+		}
+		f = p.nfa.alternate(f, g)
+	}
+	return f, nil
+}
+
+// parseConcat 解析连接 xy
+func (p *regexParser) parseConcat() (fragment, error) {
+	f := p.nfa.emptyFragment()
+	for p.pos < len(p.pattern) && p.peek() != '|' && p.peek() != ')' {
+		g, err := p.parseRepeat()
+		if err != nil {
+			return fragment{}, err
+		}
+		f = p.nfa.concat(f, g)
+	}
+	return f, nil
+}
+
+// parseRepeat 解析重复 x* x+ x?
+func (p *regexParser) parseRepeat() (fragment, error) {
+	f, err := p.parseAtom()
+	if err != nil {
+		return fragment{}, err
+	}
+	for op := p.peek(); op == '*' || op == '+' || op == '?'; op = p.peek() {
+		p.pos++
+		f = p.nfa.repeat(f, op)
+	}
+	return f, nil
+}
+
+// parseAtom 解析分组、字符集、引用或单个字符
+func (p *regexParser) parseAtom() (fragment, error) {
+	switch ch := p.peek(); ch {
+	case '(':
+		p.pos++
+		f, err := p.parseAlternate()
+		if err != nil {
+			return fragment{}, err
+		}
+		if p.peek() != ')' {
+			return fragment{}, p.errorf("missing )")
+		}
+		p.pos++
+		return f, nil
+	case '[':
+		set, err := p.parseSet()
+		if err != nil {
+			return fragment{}, err
+		}
+		return p.nfa.charFragment(set), nil
+	case '{':
+		return p.parseReference()
+	case '.':
+		p.pos++
+		return p.nfa.charFragment(charSet{negate: true}), nil
+	case '*', '+', '?':
+		return fragment{}, p.errorf("missing operand of %q", ch)
+	default:
+		ch, err := p.parseChar()
+		if err != nil {
+			return fragment{}, err
+		}
+		var set charSet
+		set.ascii[ch] = true
+		return p.nfa.charFragment(set), nil
+	}
+}
+
+// parseChar 解析一个字符或转义字符，只允许ASCII字符
+func (p *regexParser) parseChar() (rune, error) {
+	ch := p.peek()
+	if p.pos >= len(p.pattern) {
+		return 0, p.errorf("unexpected end of pattern")
+	}
+	p.pos++
+	if ch == '\\' {
+		if p.pos >= len(p.pattern) {
+			return 0, p.errorf("trailing \\")
+		}
+		ch = p.peek()
+		p.pos++
+		switch ch {
+		case 'n':
+			ch = '\n'
+		case 'r':
+			ch = '\r'
+		case 't':
+			ch = '\t'
+		}
+	}
+	if ch >= utf8.RuneSelf {
+		return 0, p.errorf("non-ASCII character %q, use a builtin class instead", ch)
+	}
+	return ch, nil
+}
+
+// parseName 解析{name}中的名字
+func (p *regexParser) parseName() (string, error) {
+	p.pos++
+	start := p.pos
+	for p.pos < len(p.pattern) && p.peek() != '}' {
+		p.pos++
+	}
+	if p.pos >= len(p.pattern) {
+		return "", p.errorf("missing }")
+	}
+	name := string(p.pattern[start:p.pos])
+	p.pos++
+	return name, nil
+}
+
+// parseReference 解析{name}，引用内置字符类或命名的子表达式
+func (p *regexParser) parseReference() (fragment, error) {
+	name, err := p.parseName()
+	if err != nil {
+		return fragment{}, err
+	}
+	if class, ok := builtinClasses[name]; ok {
+		var set charSet
+		set.addClass(class)
+		return p.nfa.charFragment(set), nil
+	}
+
+	def, ok := p.defs[name]
+	if !ok {
+		return fragment{}, p.errorf("undefined name {%s}", name)
+	}
+	for _, expanding := range p.expanding {
+		if expanding == name {
+			return fragment{}, p.errorf("recursive definition {%s}", name)
+		}
+	}
+
+	// 展开定义，每次引用都构造新的片段
+	sub := &regexParser{pattern: []rune(def), defs: p.defs, expanding: append(p.expanding, name), nfa: p.nfa}
+	return sub.parse()
+}
+
+// parseSet 解析字符集 [abc] [a-z] [^abc] [A--B]
+func (p *regexParser) parseSet() (charSet, error) {
+	var set charSet
+	p.pos++
+	if p.peek() == '^' {
+		set.negate = true
+		p.pos++
+	}
+
+	// 去掉的字符
+	subtract := false
+	for p.peek() != ']' {
+		if p.pos >= len(p.pattern) {
+			return charSet{}, p.errorf("missing ]")
+		}
+		if p.peek() == '-' && p.pos+1 < len(p.pattern) && p.pattern[p.pos+1] == '-' {
+			// 之后的字符从字符集中去掉
+			p.pos += 2
+			subtract = true
+			continue
+		}
+		if p.peek() == '{' {
+			if subtract {
+				return charSet{}, p.errorf("only ASCII characters can be subtracted")
+			}
+			name, err := p.parseName()
+			if err != nil {
+				return charSet{}, err
+			}
+			class, ok := builtinClasses[name]
+			if !ok {
+				return charSet{}, p.errorf("undefined class {%s}", name)
+			}
+			set.addClass(class)
+			continue
+		}
+
+		// 单个字符或字符范围
+		lo, err := p.parseChar()
+		if err != nil {
+			return charSet{}, err
+		}
+		hi := lo
+		if p.peek() == '-' && p.pos+1 < len(p.pattern) && p.pattern[p.pos+1] != ']' && p.pattern[p.pos+1] != '-' {
+			p.pos++
+			if hi, err = p.parseChar(); err != nil {
+				return charSet{}, err
+			}
+			if hi < lo {
+				return charSet{}, p.errorf("invalid range %q-%q", lo, hi)
+			}
+		}
+		for r := lo; r <= hi; r++ {
+			set.ascii[r] = !subtract
+		}
+	}
+	p.pos++
+
+	return set, nil
+}
+
+// DFA 由词法规范编译得到的确定有限自动机
+// 字符按其所属的字符集划分为等价类，状态转移表以等价类为列
+type DFA struct {
+	Rules   []Rule                // 词法规则
+	classes int                   // 等价类数量
+	ascii   [utf8.RuneSelf]int32  // ASCII字符对应的等价类
+	unicode [signatureCount]int32 // 非ASCII字符按签名对应的等价类
+	trans   []int32               // 状态转移表，下标为状态*等价类数量+等价类，-1表示无法转移
+	accept  []int32               // 状态接受的规则下标，-1表示不接受
+}
+
+// CompileSpec 将词法规范编译为DFA
+// rules: 按优先级排列的词法规则
+// defs: 规则中以{name}引用的子表达式
+func CompileSpec(rules []Rule, defs map[string]string) (*DFA, error) {
+	// 构造NFA，开始状态通过空边连接各规则的片段
+	n := &nfa{}
+	start := n.newState()
+	for i, rule := range rules {
+		p := &regexParser{pattern: []rune(rule.Pattern), defs: defs, nfa: n}
+		f, err := p.parse()
+		if err != nil {
+			return nil, utils.NewErrorf("rule %d: %s", i, err.Error())
+		}
+		n.epsilon(start, f.start)
+		n.states[f.end].accept = i
+	}
+
+	dfa := &DFA{Rules: rules}
+
+	// 划分等价类：属于的字符集完全相同的字符属于同一等价类
+	// members[class][set] 表示等价类中的字符是否属于字符集
+	members := make([][]bool, 0)
+	classes := make(map[string]int32)
+	classOf := func(r rune, sig uint8) int32 {
+		member := make([]bool, len(n.sets))
+		key := make([]byte, len(n.sets))
+		for i := range n.sets {
+			if n.sets[i].contains(r, sig) {
+				member[i] = true
+				key[i] = 1
+			}
+		}
+		if class, ok := classes[string(key)]; ok {
+			return class
+		}
+		members = append(members, member)
+		classes[string(key)] = int32(len(members) - 1)
+		return int32(len(members) - 1)
+	}
+	for r := rune(0); r < utf8.RuneSelf; r++ {
+		dfa.ascii[r] = classOf(r, 0)
+	}
+	for sig := 0; sig < signatureCount; sig++ {
+		dfa.unicode[sig] = classOf(utf8.RuneSelf, uint8(sig))
+	}
+	dfa.classes = len(members)
+
+	// 子集构造
+	states := make(map[string]int32)
+	queue := make([][]int, 0)
+	addState := func(set []int) int32 {
+		key := fmt.Sprint(set)
+		if state, ok := states[key]; ok {
+			return state
+		}
+		state := int32(len(queue))
+		states[key] = state
+		queue = append(queue, set)
+
+		// 接受优先级最高的规则
+		accept := int32(-1)
+		for _, s := range set {
+			if a := n.states[s].accept; a >= 0 && (accept < 0 || int32(a) < accept) {
+				accept = int32(a)
+			}
+		}
+		dfa.accept = append(dfa.accept, accept)
+		return state
+	}
+
+	addState(n.closure([]int{start}))
+	for i := 0; i < len(queue); i++ {
+		for class := 0; class < dfa.classes; class++ {
+			next := make([]int, 0)
+			for _, s := range queue[i] {
+				if state := n.states[s]; state.set >= 0 && members[class][state.set] {
+					next = append(next, state.next)
+				}
+			}
+			if len(next) == 0 {
+				dfa.trans = append(dfa.trans, -1)
+				continue
+			}
+			dfa.trans = append(dfa.trans, addState(n.closure(next)))
+		}
+	}
+
+	return dfa, nil
+}
+
+// closure 获取状态集合的空边闭包，结果按状态编号排序
+func (n *nfa) closure(set []int) []int {
+	visited := make(map[int]bool)
+	stack := append([]int{}, set...)
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if visited[s] {
+			continue
+		}
+		visited[s] = true
+		stack = append(stack, n.states[s].eps...)
+	}
+
+	result := make([]int, 0, len(visited))
+	for s := range visited {
+		result = append(result, s)
+	}
+	sort.Ints(result)
+	return result
+}
+
+// States 获取DFA的状态数量
+func (d *DFA) States() int {
+	return len(d.accept)
+}
+
+// Classes 获取字符等价类的数量
+func (d *DFA) Classes() int {
+	return d.classes
+}
+
+// class 获取字符所属的等价类
+func (d *DFA) class(r rune) int32 {
+	if r < utf8.RuneSelf {
+		return d.ascii[r]
+	}
+	return d.unicode[signature(r)]
+}
+
+// Match 从src的字节偏移start开始进行最长匹配
+// 返回匹配的规则下标及匹配结束的字节偏移（不含），没有规则匹配时规则下标为-1
+func (d *DFA) Match(src []byte, start int) (int, int) {
+	rule, end := -1, start
+	state := int32(0)
+	for i := start; i < len(src); {
+		// 读取字符，ASCII字符快速判断
+		ch, size := rune(src[i]), 1
+		if ch >= utf8.RuneSelf {
+			ch, size = utf8.DecodeRune(src[i:])
+		}
+
+		state = d.trans[int(state)*d.classes+int(d.class(ch))]
+		if state < 0 {
+			break
+		}
+		i += size
+
+		// 记录最后一次接受的位置
+		if accept := d.accept[state]; accept >= 0 {
+			rule, end = int(accept), i
+		}
+	}
+	return rule, end
+}
@@ -2,54 +2,125 @@ package lexer
 
 import (
 	"CompilerInGo/utils"
+	"fmt"
 	"github.com/kpango/glg"
 	"strings"
 )
 
-func IfTokenError(token Token, err error) Token {
-	// Token解析是否出错
-	if err != nil {
-		_ = glg.Fail("Error while scanning Token: ", err)
+type ErrorKind uint
 
-		// 获取lexer中状态
-		lex := Lex
+// ErrorKind 词法错误类型
+const (
+	UNTERMINATED_STRING  ErrorKind = iota //0 未结束的字符串
+	UNTERMINATED_CHAR                     //1 未结束的字符
+	UNTERMINATED_COMMENT                  //2 未结束的多行注释
+	INVALID_NUMBER                        //3 非法的数字字面量
+	INVALID_CHAR                          //4 非法的字符字面量
+	ILLEGAL_CHARACTER                     //5 无法识别的字符
+	INVALID_ESCAPE                        //6 非法的转义序列
+)
 
-		// 获取文件出错行内容
-		errorLine := utils.GetLine(lex.File, lex.Pos)
+// ErrorKindString 词法错误类型对应的字符串，输出时使用
+var ErrorKindString = map[ErrorKind]string{
+	UNTERMINATED_STRING:  "unterminated string",
+	UNTERMINATED_CHAR:    "unterminated char",
+	UNTERMINATED_COMMENT: "unterminated comment",
+	INVALID_NUMBER:       "invalid number",
+	INVALID_CHAR:         "invalid char",
+	ILLEGAL_CHARACTER:    "illegal character",
+	INVALID_ESCAPE:       "invalid escape",
+}
 
-		// 显示错误信息
-		_ = glg.Failf("Position: Line %d, Column %d", lex.Pos.Row, lex.Pos.Col)
-		_ = glg.Fail(errorLine)
+// LexError 词法错误
+type LexError struct {
+	Kind    ErrorKind          // 错误类型
+	Pos     utils.PositionPair // 错误位置
+	Message string             // 错误信息
+}
 
-		// 构造错误位置指示器
-		var str strings.Builder
-		for i := 0; i < int(lex.Pos.Col-1); i++ {
-			str.WriteRune('-')
-		}
-		str.WriteRune('^')
+// newLexError 创建词法错误
+func newLexError(kind ErrorKind, pos utils.PositionPair, format string, args ...any) *LexError {
+	return &LexError{
+		Kind:    kind,
+		Pos:     pos,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
 
-		// 显示错误位置指示器
-		_ = glg.Fail(str.String())
+// Error 获取词法错误的字符串表示
+func (e *LexError) Error() string {
+	return fmt.Sprintf("%s: %s, at %d:%d to %d:%d", ErrorKindString[e.Kind], e.Message, e.Pos.Begin.Row, e.Pos.Begin.Col, e.Pos.End.Row, e.Pos.End.Col)
+}
 
-		return SkipUntilValid()
+// LexErrors 词法分析中出现的全部词法错误
+type LexErrors []LexError
+
+// Error 获取全部词法错误的字符串表示，每行一个错误
+func (e LexErrors) Error() string {
+	str := make([]string, 0, len(e))
+	for _, err := range e {
+		str = append(str, err.Error())
+	}
+	return strings.Join(str, "\n")
+}
+
+// IfTokenError 检查Token是否出错，若出错则记录错误并跳过到下一个合法Token
+func (l *Lexer) IfTokenError(token Token, err error) Token {
+	// Token解析是否出错
+	if err != nil {
+		l.addError(err)
+		return l.SkipUntilValid()
 	}
 
 	// 没有错误则返回Token
 	return token
 }
 
-func SkipUntilValid() Token {
-	// 获取lexer中状态
-	lex := Lex
-
+// SkipUntilValid 继续扫描直到扫描出合法Token，期间的错误均被记录
+// 出错的scan函数已经读过出错部分，因此可以直接从当前位置继续扫描
+func (l *Lexer) SkipUntilValid() Token {
 	for {
-		ch := lex.NextRune()
-		if ch == 0 {
-			return NewToken("EOF_LITERAL", utils.PositionPair{Begin: lex.Pos, End: lex.Pos}, EOF_LITERAL)
-		}
-		token, err := lex.ScanToken()
+		token, err := l.ScanToken()
 		if err == nil {
 			return token
 		}
+		l.addError(err)
+	}
+}
+
+// addError 记录词法错误
+func (l *Lexer) addError(err error) {
+	if lexErr, ok := err.(*LexError); ok {
+		l.Errors = append(l.Errors, *lexErr)
+		return
+	}
+	// 非LexError的错误，位置记为当前位置
+	l.Errors = append(l.Errors, *newLexError(ILLEGAL_CHARACTER, utils.PositionPair{Begin: l.Pos, End: l.Pos}, "%s", err.Error()))
+}
+
+// HasErrors 判断扫描过程中是否出现词法错误
+func (l *Lexer) HasErrors() bool {
+	return len(l.Errors) > 0
+}
+
+// ReportErrors 输出全部词法错误，包括错误所在行及位置指示器
+func (l *Lexer) ReportErrors() {
+	reportErrors(l.Name, l.Lines(), l.Errors)
+}
+
+// reportErrors 输出文件name中的词法错误errs
+func reportErrors(name string, lines *utils.LineTable, errs []LexError) {
+	for _, err := range errs {
+		_ = glg.Fail("Error while scanning Token: ", err.Error())
+
+		// 获取文件出错行内容
+		errorLine := utils.GetLine(lines, err.Pos.Begin)
+
+		// 显示错误信息
+		_ = glg.Failf("Position: %s, Line %d, Column %d", name, err.Pos.Begin.Row, err.Pos.Begin.Col)
+		_ = glg.Fail(errorLine)
+
+		// 显示错误位置指示器
+		_ = glg.Fail(utils.Indicator(errorLine, err.Pos))
 	}
 }
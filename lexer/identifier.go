@@ -0,0 +1,43 @@
+package lexer
+
+import "unicode"
+
+// xidExclusion 属于ID_Start/ID_Continue但在NFKC规范化下不封闭，因而不属于XID_Start/XID_Continue的字符
+var xidExclusion = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x037A, Hi: 0x037A, Stride: 1},
+		{Lo: 0x309B, Hi: 0x309C, Stride: 1},
+		{Lo: 0xFC5E, Hi: 0xFC63, Stride: 1},
+		{Lo: 0xFDFA, Hi: 0xFDFB, Stride: 1},
+		{Lo: 0xFE70, Hi: 0xFE7E, Stride: 2},
+	},
+}
+
+// xidStartExclusion 属于XID_Continue但不属于XID_Start的字符（除xidExclusion外）
+var xidStartExclusion = &unicode.RangeTable{
+	R16: []unicode.Range16{
+		{Lo: 0x0E33, Hi: 0x0E33, Stride: 1},
+		{Lo: 0x0EB3, Hi: 0x0EB3, Stride: 1},
+		{Lo: 0xFF9E, Hi: 0xFF9F, Stride: 1},
+	},
+}
+
+// isIdentifierStart 判断字符是否可以作为标识符开头，即是否属于Unicode XID_Start
+func isIdentifierStart(r rune) bool {
+	if r < 0x80 {
+		// ASCII快速判断
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space, xidExclusion, xidStartExclusion)
+}
+
+// isIdentifierPart 判断字符是否可以作为标识符的后续字符，即是否属于Unicode XID_Continue
+func isIdentifierPart(r rune) bool {
+	if r < 0x80 {
+		// ASCII快速判断
+		return 'a' <= r && r <= 'z' || 'A' <= r && r <= 'Z' || '0' <= r && r <= '9' || r == '_'
+	}
+	return unicode.In(r, unicode.L, unicode.Nl, unicode.Other_ID_Start, unicode.Mn, unicode.Mc, unicode.Nd, unicode.Pc, unicode.Other_ID_Continue) &&
+		!unicode.In(r, unicode.Pattern_Syntax, unicode.Pattern_White_Space, xidExclusion)
+}
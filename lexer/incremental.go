@@ -0,0 +1,209 @@
+package lexer
+
+import (
+	"CompilerInGo/utils"
+	"bytes"
+	"sort"
+)
+
+// Edit 对源程序的一次编辑，将字节范围[Start, End)替换为Text
+type Edit struct {
+	Start int    // 被替换范围的开始字节偏移
+	End   int    // 被替换范围的结束字节偏移（不含）
+	Text  string // 替换后的文本
+}
+
+// Relex 增量词法分析
+// 在已扫描完成的Token池上应用一次编辑，只重新扫描受影响的部分：
+// 从编辑范围之前最后一个不受影响的Token开始扫描，直到新Token与编辑范围之后的原Token在同一位置开始，
+// 此后的原Token及词法错误只平移位置，不再扫描
+// 编辑打开或闭合多行注释、字符串时，扫描会一直进行到重新对齐或EOF为止
+func (l *Lexer) Relex(edit Edit) (*TokenPool, error) {
+	old := l.Tokens.Pool
+
+	// 检查Token池是否已扫描完成
+	if len(old) == 0 || old[len(old)-1].Category != EOF {
+		return nil, utils.NewError("relex requires a token pool scanned until EOF")
+	}
+	// 检查编辑范围
+	if edit.Start < 0 || edit.Start > edit.End || edit.End > len(l.File) {
+		return nil, utils.NewErrorf("edit range [%d, %d) is out of file range [0, %d)", edit.Start, edit.End, len(l.File))
+	}
+
+	// 编辑前后文件长度的变化
+	delta := len(edit.Text) - (edit.End - edit.Start)
+
+	// 应用编辑
+	file := make([]byte, 0, len(l.File)+delta)
+	file = append(file, l.File[:edit.Start]...)
+	file = append(file, edit.Text...)
+	file = append(file, l.File[edit.End:]...)
+
+	// 第一个可能受影响的Token：结束位置不在编辑开始之前
+	// Token的扫描结果只取决于自身及其后一个字符，因此之前的Token不受影响
+	first := 0
+	for first < len(old) && old[first].fullEnd() < edit.Start {
+		first++
+	}
+
+	// 从前一个Token开始重新扫描，保证扫描起点位于编辑范围之前
+	begin := 0
+	if first > 0 {
+		first--
+		begin = old[first].fullOffset()
+	}
+
+	// 保留扫描起点之前的词法错误
+	oldErrors := l.Errors
+	l.Errors = make([]LexError, 0, len(oldErrors))
+	for _, err := range oldErrors {
+		if errorOffset(err) < begin {
+			l.Errors = append(l.Errors, err)
+		}
+	}
+
+	// 恢复词法分析器在扫描起点的状态
+	l.File = file
+	l.lines = nil
+	if begin > 0 {
+		l.Pos = old[first].fullBegin()
+		l.size = int(l.Pos.FilePos) - begin
+		l.reader = bytes.NewReader(file[l.Pos.FilePos:])
+	} else {
+		l.Pos = utils.Position{Row: 1}
+		l.size = 0
+		l.reader = bytes.NewReader(file)
+		l.NextRune() // 读取第一个字符
+	}
+
+	// 扫描起点之前的Token保持不变
+	tokens := make([]Token, first, len(old)+1)
+	copy(tokens, old[:first])
+
+	for {
+		token := l.IfTokenError(l.ScanToken())
+
+		// 新Token位于编辑范围之后，且与某个原Token开始于同一位置，则此后的扫描结果与原Token相同
+		if start := token.fullOffset(); start >= edit.Start+len(edit.Text) {
+			rest := old[first:]
+			j := sort.Search(len(rest), func(i int) bool {
+				return rest[i].fullOffset() >= start-delta
+			})
+			if j < len(rest) && rest[j].fullOffset() == start-delta {
+				// 平移剩余原Token及词法错误的位置
+				shift := newPositionShift(rest[j].fullBegin(), token.fullBegin(), delta)
+				for _, t := range rest[j:] {
+					tokens = append(tokens, shift.token(t))
+				}
+				// 对齐的Token连同其trivia已重新扫描，其中的词法错误已记录，只平移之后的错误
+				for _, err := range oldErrors {
+					if errorOffset(err) >= rest[j].fullEnd() {
+						err.Pos = shift.pair(err.Pos)
+						l.Errors = append(l.Errors, err)
+					}
+				}
+
+				// 词法分析器状态设置为EOF
+				l.Pos = tokens[len(tokens)-1].Pos.Begin
+				l.size = 0
+				l.reader = bytes.NewReader(nil)
+				break
+			}
+		}
+
+		tokens = append(tokens, token)
+		if token.Category == EOF {
+			break
+		}
+	}
+
+	l.Tokens.Pool = tokens
+	return l.Tokens, nil
+}
+
+// fullOffset 获取Token包含前导trivia在内的开始字节偏移
+func (t *Token) fullOffset() int {
+	offset := t.Offset
+	for _, trivia := range t.LeadingTrivia {
+		offset -= len(trivia.Text)
+	}
+	return offset
+}
+
+// fullEnd 获取Token包含后随trivia在内的结束字节偏移（不含）
+func (t *Token) fullEnd() int {
+	end := t.Offset + len(t.Raw)
+	for _, trivia := range t.TrailingTrivia {
+		end += len(trivia.Text)
+	}
+	return end
+}
+
+// fullBegin 获取Token包含前导trivia在内的开始位置
+func (t *Token) fullBegin() utils.Position {
+	if len(t.LeadingTrivia) > 0 {
+		return t.LeadingTrivia[0].Pos.Begin
+	}
+	return t.Pos.Begin
+}
+
+// errorOffset 获取词法错误开始位置的字节偏移
+func errorOffset(err LexError) int {
+	return int(err.Pos.Begin.Offset())
+}
+
+// positionShift 编辑范围之后的位置平移
+// 字节偏移整体平移，行号按对齐位置的行号变化平移，只有与对齐位置同一行的列号需要平移
+type positionShift struct {
+	row      uint // 原对齐位置所在行
+	offset   int  // 字节偏移变化
+	rowDelta int  // 行号变化
+	colDelta int  // 对齐位置所在行的列号变化
+}
+
+// newPositionShift 根据原对齐位置from与新对齐位置to创建位置平移
+func newPositionShift(from utils.Position, to utils.Position, delta int) positionShift {
+	return positionShift{
+		row:      from.Row,
+		offset:   delta,
+		rowDelta: int(to.Row) - int(from.Row),
+		colDelta: int(to.Col) - int(from.Col),
+	}
+}
+
+// position 平移一个位置
+func (s positionShift) position(pos utils.Position) utils.Position {
+	pos.FilePos = uint(int(pos.FilePos) + s.offset)
+	if pos.Row == s.row {
+		pos.Col = uint(int(pos.Col) + s.colDelta)
+	}
+	pos.Row = uint(int(pos.Row) + s.rowDelta)
+	return pos
+}
+
+// pair 平移一个位置对
+func (s positionShift) pair(pos utils.PositionPair) utils.PositionPair {
+	return utils.PositionPair{Begin: s.position(pos.Begin), End: s.position(pos.End)}
+}
+
+// token 平移Token及其trivia的位置
+func (s positionShift) token(t Token) Token {
+	t.Offset += s.offset
+	t.Pos = s.pair(t.Pos)
+	t.LeadingTrivia = s.trivia(t.LeadingTrivia)
+	t.TrailingTrivia = s.trivia(t.TrailingTrivia)
+	return t
+}
+
+// trivia 平移trivia的位置
+func (s positionShift) trivia(trivia []Trivia) []Trivia {
+	if trivia == nil {
+		return nil
+	}
+	shifted := make([]Trivia, len(trivia))
+	for i, t := range trivia {
+		t.Pos = s.pair(t.Pos)
+		shifted[i] = t
+	}
+	return shifted
+}
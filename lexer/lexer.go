@@ -2,6 +2,8 @@ package lexer
 
 import (
 	"CompilerInGo/utils"
+	"bufio"
+	"bytes"
 	"errors"
 	"github.com/kpango/glg"
 	"io"
@@ -11,25 +13,40 @@ import (
 )
 
 type Lexer struct {
-	reader utils.Reader   // 读取器
-	Pos    utils.Position // 当前位置
-	File   []byte         // 文件内容
+	reader   utils.Reader     // 读取器
+	size     int              // 当前字符的字节数
+	Name     string           // 源文件名
+	Pos      utils.Position   // 当前位置
+	File     []byte           // 已读取的文件内容，用于输出错误所在行及获取Token原始文本
+	Tokens   *TokenPool       // 当前词法分析器的Token池
+	Errors   []LexError       // 扫描过程中出现的词法错误
+	Lossless bool             // 无损模式，空白与注释作为trivia附加到Token上
+	lines    *utils.LineTable // 已读取文件内容的行偏移表，按需创建
 }
 
-var Lex *Lexer      // 全局Lex变量
-var Pool *TokenPool // 全局TokenPool变量
-
-// NewLexer 创建一个新的词法分析器
+// NewLexer 创建一个新的词法分析器，读取指定文件
 func NewLexer(file string) *Lexer {
+	// 读取文件并检查读取状态
+	content := utils.MustValue(os.ReadFile(file))
+	return NewLexerFromReader(file, bytes.NewReader(content))
+}
+
+// NewLexerFromReader 创建一个从io.Reader读取源程序的词法分析器
+// name: 源文件名，用于错误信息
+// r: 源程序读取器，按需读取，不要求一次读入全部内容
+func NewLexerFromReader(name string, r io.Reader) *Lexer {
 	lexer := &Lexer{
-		Pos:  utils.Position{Row: 1},             // 设置初始位置
-		File: utils.MustValue(os.ReadFile(file)), // 读取文件并检查读取状态
+		Name:   name,
+		Pos:    utils.Position{Row: 1}, // 设置初始位置
+		File:   make([]byte, 0),
+		Tokens: NewTokenPool(),
+		Errors: make([]LexError, 0),
 	}
 
-	lexer.reader = strings.NewReader(string(lexer.File)) // 设置读取器
-	lexer.NextRune()                                     // 读取第一个字符
+	// 设置读取器，读取的原始字节同时记录到File中
+	lexer.reader = bufio.NewReader(io.TeeReader(r, fileRecorder{lexer}))
+	lexer.NextRune() // 读取第一个字符
 
-	Lex = lexer // 设置全局Lex变量
 	return lexer
 }
 
@@ -39,19 +56,30 @@ func (l *Lexer) NextRune() rune {
 
 	if errors.Is(err, io.EOF) { // EOF 文件末尾
 		l.Pos.Ch = 0
+		l.Pos.Size = 0
+		l.size = 0
 		return 0
 	} else if err != nil { // 读错误
 		glg.Fatalln(err)
 	}
 
 	// 更新位置信息
+	prev := l.Pos.Ch
 	l.Pos.Ch = ch
+	l.size = size
+	l.Pos.Size = uint(size)
 	l.Pos.FilePos += uint(size)
 
-	// 更新行列信息
-	if ch == '\n' {
+	// 更新行列信息，\n、\r\n或单独的\r均为一个换行
+	if ch == '\r' || (ch == '\n' && prev != '\r') {
 		l.Pos.Row++
 		l.Pos.Col = 0
+	} else if ch == '\n' {
+		// \r\n中的\n，行号已在\r处更新
+		l.Pos.Col = 0
+	} else if ch == utils.BOMRune && l.Pos.FilePos == uint(size) {
+		// 文件开头的BOM不占列
+		l.Pos.Col = 0
 	} else {
 		l.Pos.Col++
 	}
@@ -75,15 +103,15 @@ func (l *Lexer) peek() rune {
 
 // unread 回退一个字符
 func (l *Lexer) unread() {
-	// 使用Seek从当前位置回退一个字符，且MustValue检查错误
-	utils.MustValue(l.reader.Seek(-1, io.SeekCurrent))
+	// 回退上一次ReadRune读取的字符，且MustValue检查错误
+	utils.MustValue(0, l.reader.UnreadRune())
 }
 
-// skipBlank 跳过空白字符
+// skipBlank 跳过空白字符及文件开头的BOM
 func (l *Lexer) skipBlank() {
 	for {
 		ch := l.Pos.Ch
-		if ch != '\t' && ch != '\r' && ch != '\n' {
+		if ch != '\t' && ch != '\r' && ch != '\n' && !l.atBOM() {
 			break
 		}
 
@@ -92,6 +120,11 @@ func (l *Lexer) skipBlank() {
 	}
 }
 
+// atBOM 判断当前字符是否为文件开头的BOM
+func (l *Lexer) atBOM() bool {
+	return l.Pos.Ch == utils.BOMRune && l.offset() == 0
+}
+
 // scanString 扫描字符串
 func (l *Lexer) scanString() (Token, error) {
 	tokenPos := utils.PositionPair{Begin: l.Pos} // 记录开始位置
@@ -105,12 +138,18 @@ func (l *Lexer) scanString() (Token, error) {
 			break
 		} else if ch == 0 { // EOF
 			// 未结束字符串
-			return Token{}, errors.New("unterminated string literal")
+			l.NextRune()
+			return Token{}, newLexError(UNTERMINATED_STRING, utils.PositionPair{Begin: tokenPos.Begin, End: l.Pos}, "string literal is not terminated before EOF")
 		}
 
 		// 字符串未结束 继续读取
 		l.NextRune()
-		str.WriteRune(l.Pos.Ch)
+		if ch == '\\' {
+			// 转义序列
+			str.WriteRune(l.scanEscape())
+		} else {
+			str.WriteRune(ch)
+		}
 
 		// 更新结束位置
 		tokenPos.End = l.Pos
@@ -135,22 +174,35 @@ func (l *Lexer) scanChar() (Token, error) {
 	ch := l.NextRune()
 	if ch == 0 {
 		// 未结束字符
-		return Token{}, errors.New("unterminated char literal")
+		return Token{}, newLexError(UNTERMINATED_CHAR, utils.PositionPair{Begin: tokenPos.Begin, End: l.Pos}, "char literal is not terminated before EOF")
 	}
 	if ch == '\'' {
-		// 空字符，读取指针后移
+		// 空字符，记录结束位置，读取指针后移
+		tokenPos.End = l.Pos
 		l.NextRune()
 		return NewToken("", tokenPos, CHAR_LITERAL), nil
 	}
+	if ch == '\\' {
+		// 转义序列
+		ch = l.scanEscape()
+	}
 
 	if endCh := l.NextRune(); endCh != '\'' {
 		// 判断是否单字符
-		if endCh == 0 || endCh == '\n' {
+		if endCh == 0 || endCh == '\n' || endCh == '\r' {
 			// 未结束字符
-			return Token{}, errors.New("unterminated char literal")
+			return Token{}, newLexError(UNTERMINATED_CHAR, utils.PositionPair{Begin: tokenPos.Begin, End: l.Pos}, "char literal is not terminated before end of line")
+		}
+		// 非单字符，非法，跳过到结束单引号或行尾
+		for l.Pos.Ch != '\'' && l.Pos.Ch != '\n' && l.Pos.Ch != '\r' && l.Pos.Ch != 0 {
+			l.NextRune()
+		}
+		err := newLexError(INVALID_CHAR, utils.PositionPair{Begin: tokenPos.Begin, End: l.Pos}, "char literal must contain exactly one character")
+		if l.Pos.Ch == '\'' {
+			// 读取指针后移到结束单引号之后
+			l.NextRune()
 		}
-		// 非单字符，非法
-		return Token{}, errors.New("invalid char literal")
+		return Token{}, err
 	}
 
 	// 记录结束位置
@@ -161,6 +213,50 @@ func (l *Lexer) scanChar() (Token, error) {
 	return NewToken(ch, tokenPos, CHAR_LITERAL), nil
 }
 
+// scanEscape 扫描一个转义序列，当前字符为反斜杠，结束时当前字符为转义序列的最后一个字符
+// 返回还原后的字符，非法的转义序列记录错误并还原为utf8.RuneError，以便继续扫描所在的字面量
+func (l *Lexer) scanEscape() rune {
+	// 记录开始位置
+	tokenPos := utils.PositionPair{Begin: l.Pos, End: l.Pos}
+
+	// 使用strings.Builder拼接转义序列
+	var str strings.Builder
+	str.WriteRune(l.Pos.Ch)
+
+	// 读取转义字符，换行与EOF不属于转义序列
+	if ch := l.peek(); ch != 0 && ch != '\n' && ch != '\r' {
+		str.WriteRune(l.NextRune())
+
+		switch ch {
+		case 'x':
+			// \xHH 读取至多两位十六进制数字
+			for i := 0; i < 2 && unicode.Is(unicode.ASCII_Hex_Digit, l.peek()); i++ {
+				str.WriteRune(l.NextRune())
+			}
+		case 'u':
+			// \u{XXXX} 读取花括号及其中的十六进制数字
+			if l.peek() == '{' {
+				str.WriteRune(l.NextRune())
+				for unicode.Is(unicode.ASCII_Hex_Digit, l.peek()) {
+					str.WriteRune(l.NextRune())
+				}
+				if l.peek() == '}' {
+					str.WriteRune(l.NextRune())
+				}
+			}
+		}
+	}
+	// 更新结束位置
+	tokenPos.End = l.Pos
+
+	// 还原转义序列
+	r, _, err := utils.DecodeEscape(str.String())
+	if err != nil {
+		l.addError(newLexError(INVALID_ESCAPE, tokenPos, "%s %s", err.Error(), str.String()))
+	}
+	return r
+}
+
 // scanIdentifier 扫描标识符
 func (l *Lexer) scanIdentifier() (Token, error) {
 	// 记录开始位置
@@ -169,8 +265,8 @@ func (l *Lexer) scanIdentifier() (Token, error) {
 	// 使用strings.Builder拼接字符串
 	var str strings.Builder
 
-	// 读取标识符 由$ 字母 数字组成 开头在ScanToken中已判断
-	for l.Pos.Ch == '$' || unicode.IsLetter(l.Pos.Ch) || unicode.IsDigit(l.Pos.Ch) {
+	// 读取标识符 由$及Unicode XID_Continue字符组成 开头在ScanToken中已判断
+	for l.Pos.Ch == '$' || isIdentifierPart(l.Pos.Ch) {
 		// 写入字符
 		str.WriteRune(l.Pos.Ch)
 		// 更新结束位置
@@ -183,14 +279,17 @@ func (l *Lexer) scanIdentifier() (Token, error) {
 }
 
 // scanNumber 扫描数字
+// 支持十进制整数、小数、指数形式，0x/0o/0b前缀的整数，以及数字间的_分隔符
 func (l *Lexer) scanNumber() (Token, error) {
 	// 记录开始位置
 	tokenPos := utils.PositionPair{Begin: l.Pos}
 
 	// 使用strings.Builder拼接字符串
 	var str strings.Builder
-	// 记录小数点数量
-	var pointCount int
+	// 是否为小数
+	isDecimal := false
+	// 是否带有进制前缀
+	hasPrefix := false
 
 	if l.Pos.Ch == '-' {
 		// 如果为负数，写入字符
@@ -201,32 +300,66 @@ func (l *Lexer) scanNumber() (Token, error) {
 		l.NextRune()
 	}
 
-	// 如果为数字或小数点，继续读取
-	for unicode.IsDigit(l.Pos.Ch) || l.Pos.Ch == '.' {
-		// 如果为小数点，记录数量
-		if l.Pos.Ch == '.' {
-			pointCount++
-			if pointCount > 1 {
-				// 如果小数点数量大于1，非法
-				return IfTokenError(Token{}, errors.New("invalid number literal")), nil
-			}
+	if l.Pos.Ch == '0' {
+		// 判断是否有进制前缀
+		switch l.peek() {
+		case 'x', 'X', 'o', 'O', 'b', 'B':
+			hasPrefix = true
 		}
+	}
+
+	// 数字、字母、_、小数点均视为数字字面量的一部分，由ParseInt/ParseFloat检查合法性
+	for unicode.IsDigit(l.Pos.Ch) || unicode.IsLetter(l.Pos.Ch) || l.Pos.Ch == '_' || l.Pos.Ch == '.' {
+		ch := l.Pos.Ch
 
 		// 写入字符
-		str.WriteRune(l.Pos.Ch)
+		str.WriteRune(ch)
 		// 更新结束位置
 		tokenPos.End = l.Pos
 		// 读取下一个字符
 		l.NextRune()
+
+		if hasPrefix {
+			continue
+		}
+
+		if ch == '.' {
+			// 小数点
+			isDecimal = true
+		} else if ch == 'e' || ch == 'E' {
+			// 指数部分
+			isDecimal = true
+			if l.Pos.Ch == '+' || l.Pos.Ch == '-' {
+				// 写入指数符号
+				str.WriteRune(l.Pos.Ch)
+				tokenPos.End = l.Pos
+				l.NextRune()
+			}
+		}
 	}
 
-	if pointCount == 0 {
+	return numberToken(str.String(), tokenPos, isDecimal)
+}
+
+// numberToken 将数字字面量str解析为整数或小数Token
+func numberToken(str string, tokenPos utils.PositionPair, isDecimal bool) (Token, error) {
+	if !isDecimal {
 		// 如果为整数
-		num := utils.MustValue(utils.ParseInt(str.String()))
+		num, err := utils.ParseInt(str)
+		if errors.Is(err, utils.ErrRange) {
+			return Token{}, newLexError(INVALID_NUMBER, tokenPos, "integer literal %s overflows int64", str)
+		} else if err != nil {
+			return Token{}, newLexError(INVALID_NUMBER, tokenPos, "invalid number literal %s", str)
+		}
 		return NewToken(num, tokenPos, INTEGER_LITERAL), nil
 	} else {
 		// 如果为小数
-		num := utils.MustValue(utils.ParseFloat(str.String()))
+		num, err := utils.ParseFloat(str)
+		if errors.Is(err, utils.ErrRange) {
+			return Token{}, newLexError(INVALID_NUMBER, tokenPos, "float literal %s overflows float64", str)
+		} else if err != nil {
+			return Token{}, newLexError(INVALID_NUMBER, tokenPos, "invalid number literal %s", str)
+		}
 		return NewToken(num, tokenPos, DECIMAL_LITERAL), nil
 	}
 }
@@ -249,11 +382,13 @@ func (l *Lexer) scanComment() (Token, error) {
 		// 单行注释
 		l.NextRune()
 		commentType = SINGLELINE_COMMENT_LITERAL
+		// 更新结束位置，空注释结束于第二个/
+		tokenPos.End = l.Pos
 
 		for {
 			ch := l.NextRune()
 			// 扫描到换行或EOF结束
-			if ch == 0 || ch == '\n' {
+			if ch == 0 || ch == '\n' || ch == '\r' {
 				break
 			}
 
@@ -272,7 +407,7 @@ func (l *Lexer) scanComment() (Token, error) {
 			ch := l.NextRune()
 			// 未配对的注释
 			if ch == 0 {
-				return Token{}, errors.New("unterminated comment")
+				return Token{}, newLexError(UNTERMINATED_COMMENT, utils.PositionPair{Begin: tokenPos.Begin, End: l.Pos}, "multi-line comment is not terminated before EOF")
 			}
 
 			str.WriteRune(ch)
@@ -346,6 +481,8 @@ func (l *Lexer) scanOperator() (Token, error) {
 		return NewToken("*", tokenPos, TIMES), nil
 	case "/":
 		return NewToken("/", tokenPos, DIVIDE), nil
+	case "%":
+		return NewToken("%", tokenPos, MOD), nil
 	}
 
 	// 未匹配到操作符
@@ -381,16 +518,42 @@ func (l *Lexer) scanDelim() (Token, error) {
 		return NewToken(" ", tokenPos, SPACE), nil
 	case ',':
 		return NewToken(",", tokenPos, COMMA), nil
+	case '[':
+		return NewToken("[", tokenPos, LBRACKET), nil
+	case ']':
+		return NewToken("]", tokenPos, RBRACKET), nil
+	case '.':
+		return NewToken(".", tokenPos, DOT), nil
+	case ':':
+		return NewToken(":", tokenPos, COLON), nil
 	}
 
 	// 未匹配到分隔符
 	return Token{}, nil
 }
 
+// ScanToken 扫描一个Token
 func (l *Lexer) ScanToken() (Token, error) {
+	// 无损模式
+	if l.Lossless {
+		return l.scanLossless()
+	}
+
 	// 跳过 '\t' '\r' '\n'
 	l.skipBlank()
 
+	// 记录Token的原始文本
+	start := l.offset()
+	token, err := l.scanToken()
+	if err == nil {
+		token.Raw = l.rawFrom(start)
+		token.Offset = start
+	}
+	return token, err
+}
+
+// scanToken 从当前字符开始扫描一个Token
+func (l *Lexer) scanToken() (Token, error) {
 	if l.Pos.Ch == 0 {
 		// 扫描到EOF
 		_ = glg.Debug("Scan Completed")
@@ -407,14 +570,14 @@ func (l *Lexer) ScanToken() (Token, error) {
 	} else if (l.Pos.Ch == '/' && l.peek() == '/') || (l.Pos.Ch == '/' && l.peek() == '*') {
 		// 扫描注释
 		return l.scanComment()
-	} else if unicode.IsLetter(l.Pos.Ch) || l.Pos.Ch == '$' {
+	} else if isIdentifierStart(l.Pos.Ch) || l.Pos.Ch == '$' {
 		// 扫描标识符或关键字
 		if l.Pos.Ch == '$' {
 			// $开头 只能为标识符
 			return l.scanIdentifier()
 		}
 		// 按照标识符扫描
-		token := IfTokenError(l.scanIdentifier())
+		token, _ := l.scanIdentifier()
 		// 判断标识符内容是否为关键字
 		if IsKeyword(token.Literal.(string)) {
 			// 是关键字
@@ -436,7 +599,54 @@ func (l *Lexer) ScanToken() (Token, error) {
 		// 扫描操作符
 		return l.scanOperator()
 	} else {
-		// 无法识别的字符
-		return IfTokenError(Token{}, errors.New("unrecognized token")), nil
+		// 无法识别的字符，跳过该字符
+		begin := l.Pos
+		l.NextRune()
+		return Token{}, newLexError(ILLEGAL_CHARACTER, utils.PositionPair{Begin: begin, End: begin}, "unrecognized character %q", begin.Ch)
+	}
+}
+
+// Tokenize 扫描全部Token直到EOF，存入当前词法分析器的Token池并返回
+func (l *Lexer) Tokenize() *TokenPool {
+	// 读取第一个Token
+	// IfTokenError 检查Token是否出错，若出错则记录错误并跳过
+	token := l.IfTokenError(l.ScanToken())
+	l.Tokens.PushBack(token)
+	// 若未读到EOF则继续读取
+	for l.Tokens.Last().Category != EOF {
+		token := l.IfTokenError(l.ScanToken())
+		l.Tokens.PushBack(token)
+	}
+
+	return l.Tokens
+}
+
+// fileRecorder 将读取器读出的原始字节记录到Lexer.File中
+type fileRecorder struct {
+	l *Lexer
+}
+
+// Write 实现io.Writer
+func (w fileRecorder) Write(p []byte) (int, error) {
+	w.l.File = append(w.l.File, p...)
+	return len(p), nil
+}
+
+// offset 获取当前字符在文件中的字节偏移
+func (l *Lexer) offset() int {
+	return int(l.Pos.FilePos) - l.size
+}
+
+// rawFrom 获取从字节偏移start到当前字符（不含）之间的原始文本
+func (l *Lexer) rawFrom(start int) string {
+	return string(l.File[start:l.offset()])
+}
+
+// Lines 获取已读取文件内容的行偏移表，用于将字节偏移转换为行列号及获取行内容
+func (l *Lexer) Lines() *utils.LineTable {
+	// 文件内容变化后重新创建
+	if l.lines == nil || l.lines.Len() != len(l.File) {
+		l.lines = utils.NewLineTable(l.File)
 	}
+	return l.lines
 }
@@ -0,0 +1,118 @@
+package lexer
+
+type RuleKind uint
+
+// RuleKind 词法规则类型
+const (
+	TOKEN_RULE RuleKind = iota //0 匹配的文本生成Token
+	SKIP_RULE                  //1 匹配的文本被跳过，不生成Token
+	ERROR_RULE                 //2 匹配的文本产生词法错误
+)
+
+// Rule 词法规则
+type Rule struct {
+	Pattern string    // 正则表达式
+	Kind    RuleKind  // 规则类型
+	Type    TokenType // 生成的Token类型，仅TOKEN_RULE
+	Error   ErrorKind // 产生的错误类型，仅ERROR_RULE
+}
+
+// 词法规范
+// 规则的正则表达式支持以下语法：
+//   - 字符及转义字符 \n \r \t，其他字符前的\表示字符本身，如 \* \{ \\
+//   - . 任意字符（含换行）
+//   - [abc] [a-z] [^abc] 字符集，[A--B] 表示从字符集A中去掉ASCII字符B
+//   - {name} 内置字符类或Definitions中的定义，在字符集中只能引用内置字符类
+//   - xy 连接，x|y 选择，x* x+ x? 重复，(x) 分组
+//
+// 内置字符类：digit（unicode.IsDigit）、letter（unicode.IsLetter）、
+// identStart（Unicode XID_Start）、identPart（Unicode XID_Continue）、hex（ASCII十六进制数字）
+// 非ASCII字符只能通过内置字符类及取补集的字符集匹配
+//
+// 扫描时取最长匹配，长度相同时取Spec中靠前的规则
+// 标识符规则匹配的文本若在关键字表Keywords中，则生成对应的关键字Token
+// 新增运算符、分隔符只需在Spec中增加规则，新增关键字只需在TokenTypeString及setCategory中增加类型
+
+// Definitions 命名的子表达式，在规则中以{name}引用
+var Definitions = map[string]string{
+	"word":     `[{digit}{letter}_.]`,                                              // 数字字面量的组成字符
+	"exponent": `[eE][+\-]`,                                                        // 带符号的指数
+	"escape":   `\\([^\r\nxu]|x{hex}?{hex}?|u(\{{hex}*\}?)?)`,                      // 转义序列，合法性由动作检查
+	"charBody": `[^'\\]|{escape}`,                                                  // 字符字面量的内容
+	"string":   `"([^"\\]|\\.)*`,                                                   // 未结束的字符串
+	"comment":  `/\*([^*]|\*+[^*/])*`,                                              // 未结束的多行注释
+	"decimal":  `[{digit}--0]({word}|{exponent})*`,                                 // 不以0开头的十进制数字
+	"zero":     `0(([{digit}{letter}_.--xXoObB]|{exponent})({word}|{exponent})*)?`, // 以0开头且不带进制前缀的数字
+}
+
+// Spec 词法规范，按优先级排列
+var Spec = []Rule{
+	// 空白，空格作为分隔符
+	{Pattern: `[\t\r\n]+`, Kind: SKIP_RULE},
+
+	// 分隔符
+	{Pattern: `\{`, Type: LBRACE},
+	{Pattern: `\}`, Type: RBRACE},
+	{Pattern: `\(`, Type: LPAREN},
+	{Pattern: `\)`, Type: RPAREN},
+	{Pattern: `;`, Type: SEMICOLON},
+	{Pattern: ` `, Type: SPACE},
+	{Pattern: `,`, Type: COMMA},
+	{Pattern: `\[`, Type: LBRACKET},
+	{Pattern: `\]`, Type: RBRACKET},
+	{Pattern: `\.`, Type: DOT},
+	{Pattern: `:`, Type: COLON},
+
+	// 运算符
+	{Pattern: `==`, Type: EQUAL},
+	{Pattern: `=`, Type: ASSIGN},
+	{Pattern: `<`, Type: LESS},
+	{Pattern: `<=`, Type: LESSEQUAL},
+	{Pattern: `>`, Type: GREATER},
+	{Pattern: `>=`, Type: GREATEREQUAL},
+	{Pattern: `<>`, Type: DIAMOND},
+	{Pattern: `\+`, Type: PLUS},
+	{Pattern: `-`, Type: MINUS},
+	{Pattern: `\*`, Type: TIMES},
+	{Pattern: `/`, Type: DIVIDE},
+	{Pattern: `%`, Type: MOD},
+
+	// 字符串，未结束的字符串一直匹配到EOF
+	{Pattern: `{string}"`, Type: STRING_LITERAL},
+	{Pattern: `{string}\\?`, Kind: ERROR_RULE, Error: UNTERMINATED_STRING},
+
+	// 字符，未结束的字符在行尾或EOF前结束，多个字符跳过到结束单引号或行尾
+	{Pattern: `'({charBody})?'`, Type: CHAR_LITERAL},
+	{Pattern: `'({charBody}|\\)?`, Kind: ERROR_RULE, Error: UNTERMINATED_CHAR},
+	{Pattern: `'({charBody})[^'\r\n]+'?`, Kind: ERROR_RULE, Error: INVALID_CHAR},
+
+	// 注释，单行注释包括行尾的换行
+	{Pattern: `//[^\r\n]*[\r\n]?`, Type: SINGLELINE_COMMENT_LITERAL},
+	{Pattern: `{comment}\*+/`, Type: MULTILINE_COMMENT_LITERAL},
+	{Pattern: `{comment}\**`, Kind: ERROR_RULE, Error: UNTERMINATED_COMMENT},
+
+	// 标识符及关键字
+	{Pattern: `({identStart}|\$)({identPart}|\$)*`, Type: IDENTIFIER},
+
+	// 数字，负号紧跟数字时属于数字，整数与小数由动作区分
+	{Pattern: `-?(0[xXoObB]{word}*|{decimal}|{zero})`, Type: INTEGER_LITERAL},
+
+	// 无法识别的字符
+	{Pattern: `.`, Kind: ERROR_RULE, Error: ILLEGAL_CHARACTER},
+}
+
+// Keywords 关键字表，由TokenTypeString中类别为关键字的类型生成
+var Keywords = keywordTable()
+
+// keywordTable 创建关键字表
+func keywordTable() map[string]TokenType {
+	keywords := make(map[string]TokenType)
+	for k, v := range TokenTypeString {
+		token := Token{Type: k}
+		token.setCategory()
+		if token.Category == KEYWORD {
+			keywords[v] = k
+		}
+	}
+	return keywords
+}
@@ -0,0 +1,438 @@
+package lexer
+
+import (
+	"CompilerInGo/utils"
+	"bytes"
+	"github.com/kpango/glg"
+	"os"
+	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
+)
+
+// TableLexer 表驱动的词法分析器
+// 由词法规范Spec编译得到的DFA以最长匹配确定Token的范围及所属规则，
+// 再由规则对应的动作生成Token或词法错误，扫描结果与Lexer的非无损模式完全一致
+type TableLexer struct {
+	dfa    *DFA             // 词法规范编译得到的DFA
+	offset int              // 下一个字符的字节偏移
+	Name   string           // 源文件名
+	Pos    utils.Position   // 最后读取的字符的位置
+	File   []byte           // 文件内容
+	Tokens *TokenPool       // 当前词法分析器的Token池
+	Errors []LexError       // 扫描过程中出现的词法错误
+	lines  *utils.LineTable // 文件内容的行偏移表，按需创建
+}
+
+var (
+	specDFA     *DFA      // 词法规范Spec编译得到的DFA
+	specDFAOnce sync.Once // 保证Spec只编译一次
+)
+
+// SpecDFA 获取词法规范Spec编译得到的DFA，第一次调用时编译
+func SpecDFA() *DFA {
+	specDFAOnce.Do(func() {
+		specDFA = utils.MustValue(CompileSpec(Spec, Definitions))
+	})
+	return specDFA
+}
+
+// NewTableLexer 创建一个新的表驱动词法分析器，读取指定文件
+func NewTableLexer(file string) *TableLexer {
+	// 读取文件并检查读取状态
+	content := utils.MustValue(os.ReadFile(file))
+	return NewTableLexerFromBytes(file, content)
+}
+
+// NewTableLexerFromBytes 创建一个扫描src的表驱动词法分析器
+// name: 源文件名，用于错误信息
+// src: 源程序内容，最长匹配需要回退，因此一次读入全部内容
+func NewTableLexerFromBytes(name string, src []byte) *TableLexer {
+	return &TableLexer{
+		dfa:    SpecDFA(),
+		Name:   name,
+		Pos:    utils.Position{Row: 1}, // 设置初始位置
+		File:   src,
+		Tokens: NewTokenPool(),
+		Errors: make([]LexError, 0),
+	}
+}
+
+// next 读取下一个字符，返回其位置，行列号的计算与Lexer.NextRune相同
+func (l *TableLexer) next() utils.Position {
+	ch, size := rune(l.File[l.offset]), 1
+	if ch >= utf8.RuneSelf {
+		ch, size = utf8.DecodeRune(l.File[l.offset:])
+	}
+
+	// 更新位置信息
+	prev := l.Pos.Ch
+	l.Pos.Ch = ch
+	l.Pos.Size = uint(size)
+	l.Pos.FilePos += uint(size)
+	l.offset += size
+
+	// 更新行列信息，\n、\r\n或单独的\r均为一个换行
+	if ch == '\r' || (ch == '\n' && prev != '\r') {
+		l.Pos.Row++
+		l.Pos.Col = 0
+	} else if ch == '\n' {
+		// \r\n中的\n，行号已在\r处更新
+		l.Pos.Col = 0
+	} else if ch == utils.BOMRune && l.offset == size {
+		// 文件开头的BOM不占列
+		l.Pos.Col = 0
+	} else {
+		l.Pos.Col++
+	}
+
+	return l.Pos
+}
+
+// peek 查看下一个字符，到达end时返回0
+func (l *TableLexer) peek(end int) rune {
+	if l.offset >= end {
+		return 0
+	}
+	ch := rune(l.File[l.offset])
+	if ch >= utf8.RuneSelf {
+		ch, _ = utf8.DecodeRune(l.File[l.offset:])
+	}
+	return ch
+}
+
+// following 获取下一个字符的位置但不读取，EOF时为EOF的位置
+func (l *TableLexer) following() utils.Position {
+	if l.offset >= len(l.File) {
+		return l.eof()
+	}
+	pos, offset := l.Pos, l.offset
+	following := l.next()
+	l.Pos, l.offset = pos, offset
+	return following
+}
+
+// eof 获取EOF的位置，即最后一个字符的行列号及文件末尾的字节偏移
+func (l *TableLexer) eof() utils.Position {
+	pos := l.Pos
+	pos.Ch = 0
+	pos.Size = 0
+	return pos
+}
+
+// span 读取到字节偏移end为止的全部字符，返回第一个与最后一个字符的位置
+func (l *TableLexer) span(end int) utils.PositionPair {
+	tokenPos := utils.PositionPair{Begin: l.next()}
+	tokenPos.End = tokenPos.Begin
+	for l.offset < end {
+		tokenPos.End = l.next()
+	}
+	return tokenPos
+}
+
+// ScanToken 扫描一个Token
+func (l *TableLexer) ScanToken() (Token, error) {
+	for {
+		if l.offset == 0 && bytes.HasPrefix(l.File, []byte(utils.BOM)) {
+			// 跳过文件开头的BOM
+			l.next()
+			continue
+		}
+
+		if l.offset >= len(l.File) {
+			// 扫描到EOF
+			_ = glg.Debug("Scan Completed")
+			pos := l.eof()
+			token := NewToken("EOF_LITERAL", utils.PositionPair{Begin: pos, End: pos}, EOF_LITERAL)
+			token.Offset = l.offset
+			return token, nil
+		}
+
+		// 最长匹配
+		start := l.offset
+		rule, end := l.dfa.Match(l.File, start)
+		if rule < 0 {
+			// 没有规则匹配，跳过该字符
+			begin := l.next()
+			return Token{}, newLexError(ILLEGAL_CHARACTER, utils.PositionPair{Begin: begin, End: begin}, "unrecognized character %q", begin.Ch)
+		}
+
+		switch r := l.dfa.Rules[rule]; r.Kind {
+		case SKIP_RULE:
+			// 跳过匹配的文本
+			for l.offset < end {
+				l.next()
+			}
+		case ERROR_RULE:
+			return Token{}, l.scanError(r.Error, end)
+		default:
+			token, err := l.scanToken(r.Type, end)
+			if err == nil {
+				token.Raw = string(l.File[start:end])
+				token.Offset = start
+			}
+			return token, err
+		}
+	}
+}
+
+// scanToken 执行TOKEN_RULE的动作，由到end为止的匹配文本生成Token
+func (l *TableLexer) scanToken(tokenType TokenType, end int) (Token, error) {
+	switch tokenType {
+	case STRING_LITERAL:
+		return l.scanString(end)
+	case CHAR_LITERAL:
+		return l.scanChar(end)
+	case INTEGER_LITERAL, DECIMAL_LITERAL:
+		return l.scanNumber(end)
+	case SINGLELINE_COMMENT_LITERAL, MULTILINE_COMMENT_LITERAL:
+		return l.scanComment(tokenType, end)
+	}
+
+	// 分隔符、运算符及标识符，字面量为匹配的文本
+	str := string(l.File[l.offset:end])
+	token := NewToken(str, l.span(end), tokenType)
+	if token.Category == OPERA {
+		// 与Lexer一致，双字符运算符的结束位置为第一个字符
+		token.Pos.End = token.Pos.Begin
+	}
+	if tokenType == IDENTIFIER {
+		if keyword, ok := Keywords[str]; ok {
+			// 是关键字
+			token.Type = keyword
+			token.Category = KEYWORD
+		}
+	}
+	return token, nil
+}
+
+// scanString 由匹配的字符串生成Token，转义序列的错误被记录
+func (l *TableLexer) scanString(end int) (Token, error) {
+	// 记录开始位置
+	tokenPos := utils.PositionPair{Begin: l.next()}
+
+	var str strings.Builder
+	// 读取到结束双引号之前
+	for l.offset < end-1 {
+		if pos := l.next(); pos.Ch == '\\' {
+			// 转义序列
+			str.WriteRune(l.scanEscape(end - 1))
+		} else {
+			str.WriteRune(pos.Ch)
+		}
+	}
+
+	// 读取结束双引号，记录结束位置
+	tokenPos.End = l.next()
+
+	return NewToken(str.String(), tokenPos, STRING_LITERAL), nil
+}
+
+// scanChar 由匹配的字符生成Token，转义序列的错误被记录
+func (l *TableLexer) scanChar(end int) (Token, error) {
+	// 记录开始位置
+	tokenPos := utils.PositionPair{Begin: l.next()}
+
+	ch := l.next().Ch
+	if l.offset == end {
+		// 空字符
+		tokenPos.End = l.Pos
+		return NewToken("", tokenPos, CHAR_LITERAL), nil
+	}
+	if ch == '\\' {
+		// 转义序列
+		ch = l.scanEscape(end - 1)
+	}
+
+	// 读取结束单引号，记录结束位置
+	tokenPos.End = l.next()
+
+	return NewToken(ch, tokenPos, CHAR_LITERAL), nil
+}
+
+// scanEscape 读取一个转义序列，最后读取的字符为反斜杠，转义序列不超过字节偏移end
+// 返回还原后的字符，非法的转义序列记录错误并还原为utf8.RuneError
+func (l *TableLexer) scanEscape(end int) rune {
+	// 记录开始位置
+	tokenPos := utils.PositionPair{Begin: l.Pos, End: l.Pos}
+
+	var str strings.Builder
+	str.WriteRune(l.Pos.Ch)
+
+	// 读取转义字符，换行与EOF不属于转义序列
+	if ch := l.peek(end); ch != 0 && ch != '\n' && ch != '\r' {
+		str.WriteRune(l.next().Ch)
+
+		switch ch {
+		case 'x':
+			// \xHH 读取至多两位十六进制数字
+			for i := 0; i < 2 && unicode.Is(unicode.ASCII_Hex_Digit, l.peek(end)); i++ {
+				str.WriteRune(l.next().Ch)
+			}
+		case 'u':
+			// \u{XXXX} 读取花括号及其中的十六进制数字
+			if l.peek(end) == '{' {
+				str.WriteRune(l.next().Ch)
+				for unicode.Is(unicode.ASCII_Hex_Digit, l.peek(end)) {
+					str.WriteRune(l.next().Ch)
+				}
+				if l.peek(end) == '}' {
+					str.WriteRune(l.next().Ch)
+				}
+			}
+		}
+	}
+	// 更新结束位置
+	tokenPos.End = l.Pos
+
+	// 还原转义序列
+	r, _, err := utils.DecodeEscape(str.String())
+	if err != nil {
+		l.addError(newLexError(INVALID_ESCAPE, tokenPos, "%s %s", err.Error(), str.String()))
+	}
+	return r
+}
+
+// scanNumber 由匹配的数字生成整数或小数Token
+func (l *TableLexer) scanNumber(end int) (Token, error) {
+	str := string(l.File[l.offset:end])
+	tokenPos := l.span(end)
+
+	// 带有进制前缀的为整数，否则含有小数点或指数的为小数
+	digits := strings.TrimPrefix(str, "-")
+	hasPrefix := len(digits) > 1 && digits[0] == '0' && strings.IndexByte("xXoObB", digits[1]) >= 0
+	isDecimal := !hasPrefix && strings.ContainsAny(digits, ".eE")
+
+	return numberToken(str, tokenPos, isDecimal)
+}
+
+// scanComment 由匹配的注释生成Token，单行注释的匹配文本包括行尾的换行
+func (l *TableLexer) scanComment(commentType TokenType, end int) (Token, error) {
+	// 记录开始位置，空注释结束于第二个字符
+	tokenPos := utils.PositionPair{Begin: l.next()}
+	tokenPos.End = l.next()
+
+	var str strings.Builder
+	if commentType == SINGLELINE_COMMENT_LITERAL {
+		for l.offset < end {
+			pos := l.next()
+			if pos.Ch == '\n' || pos.Ch == '\r' {
+				// 换行不属于注释
+				break
+			}
+			str.WriteRune(pos.Ch)
+			tokenPos.End = pos
+		}
+	} else {
+		// 读取到结束的*/之前
+		for l.offset < end-2 {
+			str.WriteRune(l.next().Ch)
+		}
+		l.next()
+		tokenPos.End = l.next()
+	}
+
+	return NewToken(str.String(), tokenPos, commentType), nil
+}
+
+// scanError 执行ERROR_RULE的动作，读取到end为止的匹配文本并返回词法错误
+func (l *TableLexer) scanError(kind ErrorKind, end int) error {
+	switch kind {
+	case UNTERMINATED_STRING:
+		// 未结束的字符串匹配到EOF，其中的转义序列仍需检查
+		begin := l.next()
+		for l.offset < end {
+			if l.next().Ch == '\\' {
+				l.scanEscape(end)
+			}
+		}
+		return newLexError(UNTERMINATED_STRING, utils.PositionPair{Begin: begin, End: l.eof()}, "string literal is not terminated before EOF")
+	case UNTERMINATED_CHAR, INVALID_CHAR:
+		return l.scanCharError(kind, end)
+	case UNTERMINATED_COMMENT:
+		// 未结束的注释匹配到EOF
+		begin := l.next()
+		for l.offset < end {
+			l.next()
+		}
+		return newLexError(UNTERMINATED_COMMENT, utils.PositionPair{Begin: begin, End: l.eof()}, "multi-line comment is not terminated before EOF")
+	}
+
+	tokenPos := l.span(end)
+	if kind == ILLEGAL_CHARACTER {
+		return newLexError(ILLEGAL_CHARACTER, tokenPos, "unrecognized character %q", tokenPos.Begin.Ch)
+	}
+	return newLexError(kind, tokenPos, "%s", ErrorKindString[kind])
+}
+
+// scanCharError 由匹配的未结束或非法字符生成词法错误，其中的转义序列仍需检查
+func (l *TableLexer) scanCharError(kind ErrorKind, end int) error {
+	begin := l.next()
+	if l.offset == end {
+		// 只有开始单引号，即位于EOF
+		return newLexError(UNTERMINATED_CHAR, utils.PositionPair{Begin: begin, End: l.eof()}, "char literal is not terminated before EOF")
+	}
+	if l.next().Ch == '\\' {
+		l.scanEscape(end)
+	}
+
+	if kind == UNTERMINATED_CHAR {
+		// 结束位置为行尾换行或EOF
+		return newLexError(UNTERMINATED_CHAR, utils.PositionPair{Begin: begin, End: l.following()}, "char literal is not terminated before end of line")
+	}
+
+	// 跳过到结束单引号或行尾，结束位置为结束单引号、行尾换行或EOF
+	for l.offset < end {
+		l.next()
+	}
+	tokenPos := utils.PositionPair{Begin: begin, End: l.Pos}
+	if l.Pos.Ch != '\'' {
+		tokenPos.End = l.following()
+	}
+	return newLexError(INVALID_CHAR, tokenPos, "char literal must contain exactly one character")
+}
+
+// Tokenize 扫描全部Token直到EOF，存入当前词法分析器的Token池并返回
+// 出错的Token被跳过，错误被记录
+func (l *TableLexer) Tokenize() *TokenPool {
+	for {
+		token, err := l.ScanToken()
+		if err != nil {
+			l.addError(err)
+			continue
+		}
+		l.Tokens.PushBack(token)
+		if token.Category == EOF {
+			return l.Tokens
+		}
+	}
+}
+
+// addError 记录词法错误
+func (l *TableLexer) addError(err error) {
+	if lexErr, ok := err.(*LexError); ok {
+		l.Errors = append(l.Errors, *lexErr)
+		return
+	}
+	// 非LexError的错误，位置记为当前位置
+	l.Errors = append(l.Errors, *newLexError(ILLEGAL_CHARACTER, utils.PositionPair{Begin: l.Pos, End: l.Pos}, "%s", err.Error()))
+}
+
+// HasErrors 判断扫描过程中是否出现词法错误
+func (l *TableLexer) HasErrors() bool {
+	return len(l.Errors) > 0
+}
+
+// ReportErrors 输出全部词法错误，包括错误所在行及位置指示器
+func (l *TableLexer) ReportErrors() {
+	reportErrors(l.Name, l.Lines(), l.Errors)
+}
+
+// Lines 获取文件内容的行偏移表
+func (l *TableLexer) Lines() *utils.LineTable {
+	if l.lines == nil {
+		l.lines = utils.NewLineTable(l.File)
+	}
+	return l.lines
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/kpango/glg"
+	"strings"
 )
 
 // TokenPool Token池
@@ -16,10 +17,14 @@ type TokenCategory uint
 type TokenType uint
 
 type Token struct {
-	Category TokenCategory      // 分类
-	Type     TokenType          // 类型
-	Literal  any                // 字面量
-	Pos      utils.PositionPair `json:"-"` // 位置
+	Category       TokenCategory      // 分类
+	Type           TokenType          // 类型
+	Literal        any                // 字面量
+	Pos            utils.PositionPair `json:"-"` // 位置
+	Raw            string             `json:"-"` // 源程序中的原始文本
+	Offset         int                `json:"-"` // 原始文本在源程序中的字节偏移
+	LeadingTrivia  []Trivia           `json:"-"` // 前导trivia（仅无损模式）
+	TrailingTrivia []Trivia           `json:"-"` // 后随trivia（仅无损模式）
 }
 
 // TokenCategory
@@ -60,22 +65,33 @@ const (
 	RETURN   //27 return
 	CONTINUE //28 continue
 	BREAK    //29 break
+	NOT      //30 not
+	FOR      //31 for
+	CONST    //32 const
+	STRUCT   //33 struct
+	SWITCH   //34 switch
+	CASE     //35 case
+	DEFAULT  //36 default
 )
 
 // 分隔符
 const (
-	LBRACE    = 31 + iota //30 {
+	LBRACE    = 38 + iota //30 {
 	RBRACE                //31 }
 	LPAREN                //32 (
 	RPAREN                //33 )
 	SEMICOLON             //34 ;
 	SPACE                 //35 空格
 	COMMA                 //36 ,
+	LBRACKET              //37 [
+	RBRACKET              //38 ]
+	DOT                   //39 .
+	COLON                 //40 :
 )
 
 // 运算符
 const (
-	EQUAL        = 38 + iota //37 ==
+	EQUAL        = 49 + iota //37 ==
 	ASSIGN                   //38 =
 	LESS                     //39 <
 	LESSEQUAL                //40 <=
@@ -86,11 +102,12 @@ const (
 	MINUS                    //45 -
 	TIMES                    //46 *
 	DIVIDE                   //47 /
+	MOD                      //48 %
 )
 
 // 字面量
 const (
-	INTEGER_LITERAL            = 49 + iota //48 整数字面量
+	INTEGER_LITERAL            = 61 + iota //48 整数字面量
 	DECIMAL_LITERAL                        //49 小数字面量
 	STRING_LITERAL                         //50 字符串字面量
 	CHAR_LITERAL                           //51 字符字面量
@@ -101,7 +118,7 @@ const (
 
 // 标识符
 const (
-	IDENTIFIER = 56 + iota //55 标识符
+	IDENTIFIER = 68 + iota //55 标识符
 )
 
 // TokenTypeString Token类型对应的字符串，输出时使用
@@ -127,6 +144,13 @@ var TokenTypeString = map[TokenType]string{
 	RETURN:   "return",
 	CONTINUE: "continue",
 	BREAK:    "break",
+	NOT:      "not",
+	FOR:      "for",
+	CONST:    "const",
+	STRUCT:   "struct",
+	SWITCH:   "switch",
+	CASE:     "case",
+	DEFAULT:  "default",
 
 	LBRACE:    "LBRACE {",
 	RBRACE:    "RBRACE }",
@@ -135,6 +159,10 @@ var TokenTypeString = map[TokenType]string{
 	SEMICOLON: "SEMICOLON ;",
 	SPACE:     "SPACE",
 	COMMA:     "COMMA ,",
+	LBRACKET:  "LBRACKET [",
+	RBRACKET:  "RBRACKET ]",
+	DOT:       "DOT .",
+	COLON:     "COLON :",
 
 	EQUAL:        "EQUAL ==",
 	ASSIGN:       "ASSIGN =",
@@ -147,6 +175,7 @@ var TokenTypeString = map[TokenType]string{
 	MINUS:        "MINUS -",
 	TIMES:        "TIMES *",
 	DIVIDE:       "DIVIDE /",
+	MOD:          "MOD %",
 
 	INTEGER_LITERAL:            "INTEGER_LITERAL",
 	DECIMAL_LITERAL:            "DECIMAL_LITERAL",
@@ -175,18 +204,14 @@ var TokenCategoryString = map[TokenCategory]string{
 
 // IsKeyword 判断是否为关键字
 func IsKeyword(s string) bool {
-	switch s {
-	case "void", "var", "int", "float", "string", "char", "begin", "end", "if", "then", "else", "while", "do", "call", "read", "write", "and", "or", "return", "continue", "break":
-		return true
-	default:
-		return false
-	}
+	_, ok := Keywords[s]
+	return ok
 }
 
 // IsDelim 判断是否为分隔符
 func IsDelim(s string) bool {
 	switch s {
-	case "{", "}", "(", ")", ";", " ", ",":
+	case "{", "}", "(", ")", ";", " ", ",", "[", "]", ".", ":":
 		return true
 	default:
 		return false
@@ -196,39 +221,76 @@ func IsDelim(s string) bool {
 // IsOpera 判断是否为运算符
 func IsOpera(s string) bool {
 	switch s {
-	case "==", "=", "<", "<=", ">", ">=", "<>", "+", "-", "*", "/":
+	case "==", "=", "<", "<=", ">", ">=", "<>", "+", "-", "*", "/", "%":
 		return true
 	default:
 		return false
 	}
 }
 
+// TypeName 获取Token类型在错误信息中的名称
+// 关键字、分隔符及运算符为反引号括起的符号，其他类型为类型的描述
+func TypeName(t TokenType) string {
+	switch t {
+	case IDENTIFIER:
+		return "identifier"
+	case INTEGER_LITERAL:
+		return "integer literal"
+	case DECIMAL_LITERAL:
+		return "decimal literal"
+	case STRING_LITERAL:
+		return "string literal"
+	case CHAR_LITERAL:
+		return "char literal"
+	case EOF_LITERAL:
+		return "end of file"
+	case SPACE:
+		return "space"
+	case SINGLELINE_COMMENT_LITERAL, MULTILINE_COMMENT_LITERAL:
+		return "comment"
+	}
+	// TokenTypeString中分隔符及运算符为“名称 符号”，取最后的符号
+	name := strings.Fields(TokenTypeString[t])
+	if len(name) == 0 {
+		return "unknown token"
+	}
+	return "`" + name[len(name)-1] + "`"
+}
+
+// Describe 获取Token在错误信息中的描述
+func (t *Token) Describe() string {
+	switch t.Category {
+	case KEYWORD, DELIM, OPERA, EOF:
+		return TypeName(t.Type)
+	}
+	literal := t.Literal
+	switch l := t.Literal.(type) {
+	case string:
+		literal = utils.EscapeString(l)
+	case rune:
+		literal = utils.EscapeRune(l)
+	}
+	return fmt.Sprintf("%s `%v`", TypeName(t.Type), literal)
+}
+
 // CategoryName 获取Token类别对应的字符串
 func (t *Token) CategoryName() string {
 	return TokenCategoryString[t.Category]
 }
 
 // String 获取Token的字符串表示
+// 字面量中的特殊字符以转义形式输出
 func (t *Token) String() string {
-	switch t.Type {
-	case CHAR_LITERAL:
-		// 字符字面量
-		if t.Literal == "" {
-			// 空字符
-			return fmt.Sprintf("%3d:%3d to %3d:%3d %12s %27s (%v)", t.Pos.Begin.Row, t.Pos.Begin.Col, t.Pos.End.Row, t.Pos.End.Col, t.CategoryName(), TokenTypeString[t.Type], "")
-		}
-		switch t.Literal.(type) {
-		case string:
-			// 字符非空且为字符串（经过转义）
-			return fmt.Sprintf("%3d:%3d to %3d:%3d %12s %27s (%v)", t.Pos.Begin.Row, t.Pos.Begin.Col, t.Pos.End.Row, t.Pos.End.Col, t.CategoryName(), TokenTypeString[t.Type], t.Literal)
-		default:
-			// 字符非空且未经转义
-			return fmt.Sprintf("%3d:%3d to %3d:%3d %12s %27s (%v)", t.Pos.Begin.Row, t.Pos.Begin.Col, t.Pos.End.Row, t.Pos.End.Col, t.CategoryName(), TokenTypeString[t.Type], string(t.Literal.(rune)))
-		}
-	default:
-		// 其他类型
-		return fmt.Sprintf("%3d:%3d to %3d:%3d %12s %27s (%v)", t.Pos.Begin.Row, t.Pos.Begin.Col, t.Pos.End.Row, t.Pos.End.Col, t.CategoryName(), TokenTypeString[t.Type], t.Literal)
+	literal := t.Literal
+	switch l := t.Literal.(type) {
+	case string:
+		// 字符串（含空字符）
+		literal = utils.EscapeString(l)
+	case rune:
+		// 字符
+		literal = utils.EscapeRune(l)
 	}
+	return fmt.Sprintf("%3d:%3d to %3d:%3d %12s %27s (%v)", t.Pos.Begin.Row, t.Pos.Begin.Col, t.Pos.End.Row, t.Pos.End.Col, t.CategoryName(), TokenTypeString[t.Type], literal)
 }
 
 // setType 设置Token的类型
@@ -243,11 +305,11 @@ func (t *Token) setType() {
 // setCategory 设置Token的分类
 func (t *Token) setCategory() {
 	switch t.Type {
-	case VOID, VAR, INT, FLOAT, STRING, CHAR, BEGIN, END, IF, THEN, ELSE, WHILE, DO, CALL, READ, WRITE, AND, OR, CONTINUE, BREAK, RETURN:
+	case VOID, VAR, INT, FLOAT, STRING, CHAR, BEGIN, END, IF, THEN, ELSE, WHILE, DO, FOR, CONST, STRUCT, SWITCH, CASE, DEFAULT, CALL, READ, WRITE, AND, OR, NOT, CONTINUE, BREAK, RETURN:
 		t.Category = KEYWORD
-	case LBRACE, RBRACE, LPAREN, RPAREN, SEMICOLON, SPACE, COMMA:
+	case LBRACE, RBRACE, LPAREN, RPAREN, SEMICOLON, SPACE, COMMA, LBRACKET, RBRACKET, DOT, COLON:
 		t.Category = DELIM
-	case EQUAL, ASSIGN, LESS, LESSEQUAL, GREATER, GREATEREQUAL, DIAMOND, PLUS, MINUS, TIMES, DIVIDE:
+	case EQUAL, ASSIGN, LESS, LESSEQUAL, GREATER, GREATEREQUAL, DIAMOND, PLUS, MINUS, TIMES, DIVIDE, MOD:
 		t.Category = OPERA
 	case INTEGER_LITERAL:
 		t.Category = INTEGER
@@ -267,12 +329,17 @@ func (t *Token) setCategory() {
 }
 
 func (t Token) MarshalJSON() ([]byte, error) {
+	literal := t.Literal
+	if ch, ok := t.Literal.(rune); ok {
+		// 字符以字符串形式输出
+		literal = string(ch)
+	}
 	return json.Marshal(struct {
 		Literal  any
 		Type     string
 		Category string
 	}{
-		Literal:  t.Literal,
+		Literal:  literal,
 		Type:     TokenTypeString[t.Type],
 		Category: TokenCategoryString[t.Category],
 	})
@@ -282,33 +349,13 @@ func (t Token) MarshalJSON() ([]byte, error) {
 func NewToken(literal any, position utils.PositionPair, tokenType TokenType) Token {
 	switch literal.(type) {
 	// 按类型创建
-	case int64, float64:
-		// 整数和小数
-		token := Token{
-			Literal: literal,
-			Pos:     position,
-			Type:    tokenType,
-		}
-		token.setCategory()
-		return token
-	case string:
-		// 字符串
-		token := Token{
-			Literal: literal,
-			Pos:     position,
-			Type:    tokenType,
-		}
-		token.Literal = utils.EscapeString(token.Literal.(string)) // 转义字符串
-		token.setCategory()
-		return token
-	case rune:
-		// 字符
+	case int64, float64, string, rune:
+		// 整数、小数、字符串和字符，字符串和字符的字面量为转义还原后的值
 		token := Token{
 			Literal: literal,
 			Pos:     position,
 			Type:    tokenType,
 		}
-		token.Literal = utils.EscapeRune(token.Literal.(rune)) // 转义字符
 		token.setCategory()
 		return token
 	default:
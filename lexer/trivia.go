@@ -0,0 +1,163 @@
+package lexer
+
+import (
+	"CompilerInGo/utils"
+	"strings"
+)
+
+type TriviaKind uint
+
+// TriviaKind trivia类型
+const (
+	WHITESPACE_TRIVIA         TriviaKind = iota //0 空格与制表符
+	NEWLINE_TRIVIA                              //1 换行（\n、\r\n或\r）
+	SINGLELINE_COMMENT_TRIVIA                   //2 单行注释，不含换行
+	MULTILINE_COMMENT_TRIVIA                    //3 多行注释
+	SKIPPED_TRIVIA                              //4 因词法错误被跳过的文本
+	BOM_TRIVIA                                  //5 文件开头的BOM
+)
+
+// TriviaKindString trivia类型对应的字符串，输出时使用
+var TriviaKindString = map[TriviaKind]string{
+	WHITESPACE_TRIVIA:         "WHITESPACE",
+	NEWLINE_TRIVIA:            "NEWLINE",
+	SINGLELINE_COMMENT_TRIVIA: "SINGLELINE_COMMENT",
+	MULTILINE_COMMENT_TRIVIA:  "MULTILINE_COMMENT",
+	SKIPPED_TRIVIA:            "SKIPPED",
+	BOM_TRIVIA:                "BOM",
+}
+
+// Trivia 不影响语法的源程序片段，附加在Token前后
+type Trivia struct {
+	Kind TriviaKind         // 类型
+	Text string             // 原始文本
+	Pos  utils.PositionPair // 位置
+}
+
+// scanLossless 无损模式下扫描一个Token
+// Token之前的空白、注释以及出错被跳过的文本作为前导trivia，
+// Token之后同一行内的空白、注释以及行尾换行作为后随trivia
+func (l *Lexer) scanLossless() (Token, error) {
+	leading := l.scanTrivia(false)
+
+	for {
+		// 记录Token的原始文本
+		start := l.offset()
+		token, err := l.scanToken()
+		if err == nil {
+			token.Raw = l.rawFrom(start)
+			token.Offset = start
+			token.LeadingTrivia = leading
+			if token.Type != EOF_LITERAL {
+				token.TrailingTrivia = l.scanTrivia(true)
+			}
+			return token, nil
+		}
+
+		// 记录错误，出错的文本作为前导trivia保留
+		l.addError(err)
+		if lexErr, ok := err.(*LexError); ok {
+			leading = append(leading, Trivia{Kind: SKIPPED_TRIVIA, Text: l.rawFrom(start), Pos: lexErr.Pos})
+		} else {
+			leading = append(leading, Trivia{Kind: SKIPPED_TRIVIA, Text: l.rawFrom(start)})
+		}
+		leading = append(leading, l.scanTrivia(false)...)
+	}
+}
+
+// scanTrivia 扫描连续的trivia
+// trailing为true时扫描到行尾换行（含）为止，否则扫描到下一个Token开始为止
+func (l *Lexer) scanTrivia(trailing bool) []Trivia {
+	trivia := make([]Trivia, 0)
+
+	for {
+		// 记录开始位置
+		start := l.offset()
+		pos := utils.PositionPair{Begin: l.Pos}
+		var kind TriviaKind
+
+		switch {
+		case l.atBOM():
+			// 文件开头的BOM
+			kind = BOM_TRIVIA
+			pos.End = l.Pos
+			l.NextRune()
+		case l.Pos.Ch == ' ' || l.Pos.Ch == '\t':
+			// 连续的空格与制表符
+			kind = WHITESPACE_TRIVIA
+			for l.Pos.Ch == ' ' || l.Pos.Ch == '\t' {
+				pos.End = l.Pos
+				l.NextRune()
+			}
+		case l.Pos.Ch == '\r' || l.Pos.Ch == '\n':
+			// 换行，\r\n视为一个换行
+			kind = NEWLINE_TRIVIA
+			pos.End = l.Pos
+			if l.Pos.Ch == '\r' && l.peek() == '\n' {
+				l.NextRune()
+				pos.End = l.Pos
+			}
+			l.NextRune()
+		case l.Pos.Ch == '/' && l.peek() == '/':
+			// 单行注释，不含换行
+			kind = SINGLELINE_COMMENT_TRIVIA
+			for l.Pos.Ch != '\n' && l.Pos.Ch != '\r' && l.Pos.Ch != 0 {
+				pos.End = l.Pos
+				l.NextRune()
+			}
+		case l.Pos.Ch == '/' && l.peek() == '*':
+			// 多行注释
+			kind = MULTILINE_COMMENT_TRIVIA
+			l.NextRune()
+			for {
+				ch := l.NextRune()
+				if ch == 0 {
+					// 未配对的注释
+					l.addError(newLexError(UNTERMINATED_COMMENT, utils.PositionPair{Begin: pos.Begin, End: l.Pos}, "multi-line comment is not terminated before EOF"))
+					pos.End = l.Pos
+					break
+				}
+				if ch == '*' && l.peek() == '/' {
+					// 读取指针后移到/
+					l.NextRune()
+					pos.End = l.Pos
+					l.NextRune()
+					break
+				}
+			}
+		default:
+			// 不是trivia
+			return trivia
+		}
+
+		trivia = append(trivia, Trivia{Kind: kind, Text: l.rawFrom(start), Pos: pos})
+
+		// 后随trivia在换行处结束
+		if trailing && kind == NEWLINE_TRIVIA {
+			return trivia
+		}
+	}
+}
+
+// FullText 获取Token包含前导及后随trivia在内的完整原始文本
+func (t *Token) FullText() string {
+	var str strings.Builder
+	for _, trivia := range t.LeadingTrivia {
+		str.WriteString(trivia.Text)
+	}
+	str.WriteString(t.Raw)
+	for _, trivia := range t.TrailingTrivia {
+		str.WriteString(trivia.Text)
+	}
+	return str.String()
+}
+
+// SourceText 按顺序拼接Token池中全部Token的完整原始文本
+// 无损模式下扫描得到的Token池，其结果与源程序完全一致
+func (pl *TokenPool) SourceText() string {
+	var str strings.Builder
+	for _, token := range pl.Pool {
+		str.WriteString(token.FullText())
+	}
+	return str.String()
+}
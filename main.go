@@ -10,6 +10,7 @@ import (
 	"encoding/json"
 	"flag"
 	"github.com/kpango/glg"
+	"io"
 	"os"
 	"runtime"
 	"runtime/pprof"
@@ -17,9 +18,17 @@ import (
 )
 
 func main() {
+	// doc子命令，生成方法参考文档
+	if len(os.Args) > 1 && os.Args[1] == "doc" {
+		runDoc(os.Args[2:])
+		return
+	}
+
 	// 解析命令行参数
-	filepath := flag.String("f", "./test.program", "input source program")
+	filepath := flag.String("f", "./test.program", "input source program (\"-\" for stdin)")
 	mode := flag.String("m", "DEBUG", "logger mode (DEBUG, INFO, CLOSE)")
+	stream := flag.Bool("stream", false, "pipeline lexer and parser without buffering all tokens")
+	table := flag.Bool("table", false, "scan with the table-driven lexer generated from the token specification")
 	flag.Parse()
 
 	// 设置CPU Profiling
@@ -43,56 +52,105 @@ func main() {
 
 	// ------------------- Lexer -------------------
 
+	if *table && *stream {
+		glg.Fatal("-table cannot be used with -stream")
+	}
+
 	// 初始化lexer
-	lex := lexer.NewLexer(*filepath)
+	var lex *lexer.Lexer
+	var tableLex *lexer.TableLexer
+	switch {
+	case *table && *filepath == "-":
+		// 表驱动扫描需要一次读入全部内容
+		tableLex = lexer.NewTableLexerFromBytes("<stdin>", utils.MustValue(io.ReadAll(os.Stdin)))
+	case *table:
+		tableLex = lexer.NewTableLexer(*filepath)
+	case *filepath == "-":
+		// 从标准输入读取
+		lex = lexer.NewLexerFromReader("<stdin>", os.Stdin)
+	default:
+		lex = lexer.NewLexer(*filepath)
+	}
 	_ = glg.Info("Lexer initialized")
 
-	// 初始化token池
-	lexer.Pool = lexer.NewTokenPool()
+	var pser *parser.Parser
+	if *stream {
+		// 流式模式，词法分析与语法分析流水线执行
+		pser = parser.NewParserFromLexer(lex)
+	} else {
+		// Lexer计时开始
+		startTime := time.Now()
+
+		// 扫描全部Token，作为Parser的输入
+		// errors 词法错误数量，report 输出全部词法错误
+		var pool *lexer.TokenPool
+		var errors int
+		var report func()
+		if tableLex != nil {
+			// 表驱动扫描
+			pool = tableLex.Tokenize()
+			errors, report = len(tableLex.Errors), tableLex.ReportErrors
+		} else {
+			pool = lex.Tokenize()
+			errors, report = len(lex.Errors), lex.ReportErrors
+		}
 
-	// Lexer计时开始
-	startTime := time.Now()
+		// Lexer计时结束
+		elapsedTime := time.Since(startTime)
 
-	// 读取第一个Token
-	// IfTokenError 检查Token是否出错，若出错则输出错误信息并退出程序
-	token := lexer.IfTokenError(lex.ScanToken())
-	lexer.Pool.PushBack(token)
-	// 若未读到EOF则继续读取
-	for lexer.Pool.Last().Category != lexer.EOF {
-		token := lexer.IfTokenError(lex.ScanToken())
-		lexer.Pool.PushBack(token)
-	}
+		// 输出Token池
+		_ = glg.Info("Token Pool:")
+		_ = glg.Infof("%3s:%3s to %3s:%3s %12s %27s (%v)", "Row", "Col", "Row", "Col", "Category", "Type", "Literal")
 
-	// Lexer计时结束
-	elapsedTime := time.Since(startTime)
+		for _, token := range pool.Pool {
+			_ = glg.Info(token.String())
+		}
+		// 显示Lexer运行时间
+		_ = glg.Info("Lexing finished in ", elapsedTime)
 
-	// 输出Token池
-	_ = glg.Info("Token Pool:")
-	_ = glg.Infof("%3s:%3s to %3s:%3s %12s %27s (%v)", "Row", "Col", "Row", "Col", "Category", "Type", "Literal")
+		// 输出全部词法错误并以失败状态退出
+		if errors > 0 {
+			report()
+			glg.Fatal("Lexing finished with ", errors, " errors")
+		}
 
-	for _, token := range lexer.Pool.Pool {
-		_ = glg.Info(token.String())
+		pser = parser.NewParserFromTokens(pool.Pool)
+		// 输出语法错误时显示出错行
+		if tableLex != nil {
+			pser.SetSource(tableLex.Name, tableLex.Lines())
+		} else {
+			pser.SetSource(lex.Name, lex.Lines())
+		}
 	}
-	// 显示Lexer运行时间
-	_ = glg.Info("Lexing finished in ", elapsedTime)
 
 	// ------------------- Parser -------------------
 
 	// 初始化parser
-	pser := parser.NewParser()
 	_ = glg.Info("Parser initialized")
 
 	// Parser计时开始
-	startTime = time.Now()
+	startTime := time.Now()
 
 	// 开始parse
 	program, err := pser.Parse()
+
+	// 流式模式下，词法错误在语法分析结束后输出
+	if *stream && lex.HasErrors() {
+		lex.ReportErrors()
+		glg.Fatal("Lexing finished with ", len(lex.Errors), " errors")
+	}
+
+	// 输出全部语法错误并以失败状态退出
+	if pser.HasErrors() {
+		pser.ReportErrors()
+		glg.Fatal("Parsing finished with ", len(pser.Errors), " errors")
+	}
 	if err != nil {
 		glg.Fatal(err)
 	}
 
 	// Parser计时结束
-	elapsedTime = time.Since(startTime)
+	elapsedTime := time.Since(startTime)
 
 	// 将AST转换为JSON
 	marshaled, _ := json.Marshal(program)
@@ -6,108 +6,160 @@ import (
 	"fmt"
 )
 
-// generateExp 生成算术表达式
+// arithmeticOps 算术运算符对应的中间代码操作符及符号
+var arithmeticOps = map[int]struct {
+	Op     int
+	Symbol string
+}{
+	ast.PLUS:   {Op: PLUS, Symbol: "+"},
+	ast.MINUS:  {Op: MINUS, Symbol: "-"},
+	ast.TIMES:  {Op: TIMES, Symbol: "*"},
+	ast.DIVIDE: {Op: DIVIDE, Symbol: "/"},
+	ast.MOD:    {Op: MOD, Symbol: "%"},
+	ast.CONCAT: {Op: CONCAT, Symbol: "++"},
+}
+
+// generateExp 生成表达式
 func (g *MIRGenerator) generateExp(exp hir.Exp) ([]Statement, int) {
-	// 右项为空
-	if exp.Op == ast.EMPTY {
-		return g.generateTerm(exp.LTerm)
+	// 按照表达式类型生成语句序列
+	switch exp.(type) {
+	case *hir.BinaryExp:
+		// Exp BinaryOp Exp
+		return g.generateBinaryExp(*exp.(*hir.BinaryExp))
+	case *hir.UnaryExp:
+		// UnaryOp Exp
+		return g.generateUnaryExp(*exp.(*hir.UnaryExp))
+	case *hir.CallExp:
+		// ID(ActParamList)
+		return g.generateCallExp(*exp.(*hir.CallExp))
+	case *hir.IndexExp:
+		// ID[Exp]
+		return g.generateIndexExp(*exp.(*hir.IndexExp))
+	case hir.ID:
+		// ID
+		return nil, g.GetVar(string(exp.(hir.ID)))
+	case *hir.Integer:
+		// INTC
+		intVar := g.NewAnonymousVar()
+		return []Statement{*NewStatement(ASSIGN, StrParam(hir.VarToStr(intVar)), IntParam(exp.(*hir.Integer).Val), StrParam(hir.VarToStr(intVar)), fmt.Sprintf("%s = %s", StrParam(hir.VarToStr(intVar)), IntParam(exp.(*hir.Integer).Val).Str()))}, intVar
+	case *hir.Float:
+		// DECI
+		floatVar := g.NewAnonymousVar()
+		return []Statement{*NewStatement(ASSIGN, StrParam(hir.VarToStr(floatVar)), FloatParam(exp.(*hir.Float).Val), StrParam(hir.VarToStr(floatVar)), fmt.Sprintf("%s = %s", StrParam(hir.VarToStr(floatVar)), FloatParam(exp.(*hir.Float).Val).Str()))}, floatVar
+	case *hir.String:
+		// STRC
+		strVar := g.NewAnonymousVar()
+		return []Statement{*NewStatement(ASSIGN, StrParam(hir.VarToStr(strVar)), StringParam(exp.(*hir.String).Val), StrParam(hir.VarToStr(strVar)), fmt.Sprintf("%s = %s", StrParam(hir.VarToStr(strVar)), StringParam(exp.(*hir.String).Val).Str()))}, strVar
+	case *hir.Char:
+		// CHARC
+		charVar := g.NewAnonymousVar()
+		return []Statement{*NewStatement(ASSIGN, StrParam(hir.VarToStr(charVar)), CharParam(exp.(*hir.Char).Val), StrParam(hir.VarToStr(charVar)), fmt.Sprintf("%s = %s", StrParam(hir.VarToStr(charVar)), CharParam(exp.(*hir.Char).Val).Str()))}, charVar
+	default:
+		return nil, 0
 	}
+}
 
-	// 语句序列
-	var stmtSeq []Statement
-	// 左项语句序列，左项结果变量
-	lTermStmtSeq, lTermResultID := g.generateTerm(exp.LTerm)
-	// 右项语句序列，右项结果变量
-	rTermStmtSeq, rTermResultID := g.generateTerm(exp.RTerm)
-	// 将左项语句序列和右项语句序列添加到语句序列中
-	stmtSeq = append(stmtSeq, lTermStmtSeq...)
-	stmtSeq = append(stmtSeq, rTermStmtSeq...)
+// generateBinaryExp 生成二元表达式
+func (g *MIRGenerator) generateBinaryExp(binaryExp hir.BinaryExp) ([]Statement, int) {
+	switch binaryExp.Op {
+	case ast.AND:
+		// 连续的and合并为一个短路求值序列
+		return g.generateRelationalExp(flatten(&binaryExp, ast.AND))
+	case ast.OR:
+		// 连续的or合并为一个短路求值序列
+		return g.generateConditionalExp(flatten(&binaryExp, ast.OR))
+	case ast.LESS, ast.LESSEQUAL, ast.GREATER, ast.GREATEREQUAL, ast.EQUAL, ast.DIAMOND:
+		return g.generateCompExp(binaryExp)
+	}
 
-	// 根据运算符生成语句序列
-	switch exp.Op {
-	case ast.PLUS:
-		// 算术表达式结果变量
-		resultID := g.NewAnonymousVar()
-		// 生成算术表达式语句
-		stmtSeq = append(stmtSeq, *NewStatement(PLUS, StrParam(hir.VarToStr(lTermResultID)), StrParam(hir.VarToStr(rTermResultID)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = %s + %s", hir.VarToStr(resultID), hir.VarToStr(lTermResultID), hir.VarToStr(rTermResultID))))
-		return stmtSeq, resultID
-	case ast.MINUS:
-		// 算术表达式结果变量
-		resultID := g.NewAnonymousVar()
-		// 生成算术表达式语句
-		stmtSeq = append(stmtSeq, *NewStatement(MINUS, StrParam(hir.VarToStr(lTermResultID)), StrParam(hir.VarToStr(rTermResultID)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = %s - %s", hir.VarToStr(resultID), hir.VarToStr(lTermResultID), hir.VarToStr(rTermResultID))))
-		return stmtSeq, resultID
+	arithmeticOp, ok := arithmeticOps[binaryExp.Op]
+	if !ok {
+		return nil, 0
 	}
+	// 左操作数语句序列，左操作数结果变量
+	stmtSeq, lExpResultID := g.generateExp(binaryExp.LExp)
+	// 右操作数语句序列，右操作数结果变量
+	rExpStmtSeq, rExpResultID := g.generateExp(binaryExp.RExp)
+	stmtSeq = append(stmtSeq, rExpStmtSeq...)
+	// 算术表达式结果变量
+	resultID := g.NewAnonymousVar()
+	// 生成算术表达式语句
+	stmtSeq = append(stmtSeq, *NewStatement(arithmeticOp.Op, StrParam(hir.VarToStr(lExpResultID)), StrParam(hir.VarToStr(rExpResultID)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = %s %s %s", hir.VarToStr(resultID), hir.VarToStr(lExpResultID), arithmeticOp.Symbol, hir.VarToStr(rExpResultID))))
 
-	return nil, 0
+	return stmtSeq, resultID
 }
 
-// generateTerm 生成项
-func (g *MIRGenerator) generateTerm(term hir.Term) ([]Statement, int) {
-	// 右因子为空
-	if term.Op == ast.EMPTY {
-		return g.generateFactor(term.LFactor)
+// flatten 将以op连接的二元表达式展开为操作数序列，如 a and (b and c) 展开为 a, b, c
+func flatten(exp hir.Exp, op int) []hir.Exp {
+	binaryExp, ok := exp.(*hir.BinaryExp)
+	if !ok || binaryExp.Op != op {
+		return []hir.Exp{exp}
 	}
+	return append(flatten(binaryExp.LExp, op), flatten(binaryExp.RExp, op)...)
+}
 
-	// 语句序列
-	var stmtSeq []Statement
-	// 左因子语句序列，左因子结果变量
-	lFactorStmtSeq, lFactorResultID := g.generateFactor(term.LFactor)
-	// 右因子语句序列，右因子结果变量
-	rFactorStmtSeq, rFactorResultID := g.generateFactor(term.RFactor)
-	// 将左因子语句序列和右因子语句序列添加到语句序列中
-	stmtSeq = append(stmtSeq, lFactorStmtSeq...)
-	stmtSeq = append(stmtSeq, rFactorStmtSeq...)
-
-	// 根据运算符生成语句序列
-	switch term.Op {
-	case ast.TIMES:
-		// 项结果变量
+// generateUnaryExp 生成一元表达式
+func (g *MIRGenerator) generateUnaryExp(unaryExp hir.UnaryExp) ([]Statement, int) {
+	switch unaryExp.Op {
+	case ast.NOT:
+		return g.generateNotExp(unaryExp.Exp)
+	case ast.NEG:
+		// 操作数语句序列，操作数结果变量
+		stmtSeq, expResultID := g.generateExp(unaryExp.Exp)
+		// 结果变量
 		resultID := g.NewAnonymousVar()
-		// 生成项计算语句
-		stmtSeq = append(stmtSeq, *NewStatement(TIMES, StrParam(hir.VarToStr(lFactorResultID)), StrParam(hir.VarToStr(rFactorResultID)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = %s * %s", hir.VarToStr(resultID), hir.VarToStr(lFactorResultID), hir.VarToStr(rFactorResultID))))
-		return stmtSeq, resultID
-	case ast.DIVIDE:
-		// 项结果变量
-		resultID := g.NewAnonymousVar()
-		// 生成项计算语句
-		stmtSeq = append(stmtSeq, *NewStatement(DIVIDE, StrParam(hir.VarToStr(lFactorResultID)), StrParam(hir.VarToStr(rFactorResultID)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = %s / %s", hir.VarToStr(resultID), hir.VarToStr(lFactorResultID), hir.VarToStr(rFactorResultID))))
+		stmtSeq = append(stmtSeq, *NewStatement(NEG, StrParam(hir.VarToStr(expResultID)), StrParam("_"), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = -%s", hir.VarToStr(resultID), hir.VarToStr(expResultID))))
 		return stmtSeq, resultID
+	default:
+		return nil, 0
 	}
+}
+
+// generateCallExp 生成方法调用表达式
+func (g *MIRGenerator) generateCallExp(call hir.CallExp) ([]Statement, int) {
+	stmtSeq, method := g.generateCall(call.Method, call.ActParam)
 
-	return nil, 0
+	// 方法返回后，将返回值移动到临时变量，避免再次调用同一方法时被覆盖
+	resultID := g.NewAnonymousVar()
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), StrParam(hir.VarToStr(method.ResultVar)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("call %s result: %s = %s", call.Method, hir.VarToStr(resultID), hir.VarToStr(method.ResultVar))))
+
+	return stmtSeq, resultID
 }
 
-// generateFactor 生成因子
-func (g *MIRGenerator) generateFactor(factor hir.Factor) ([]Statement, int) {
-	// 按照因子类型生成语句序列
-	switch factor.(type) {
-	case *hir.Exp:
-		// (Exp)
-		return g.generateExp(*factor.(*hir.Exp))
-	case hir.ID:
-		// ID
-		return nil, g.GetVar(string(factor.(hir.ID)))
-	case *hir.Integer:
-		// INTC
-		intVar := g.NewAnonymousVar()
-		return []Statement{*NewStatement(ASSIGN, StrParam(hir.VarToStr(intVar)), IntParam(factor.(*hir.Integer).Val), StrParam(hir.VarToStr(intVar)), fmt.Sprintf("%s = %s", StrParam(hir.VarToStr(intVar)), IntParam(factor.(*hir.Integer).Val).Str()))}, intVar
-	case *hir.Float:
-		// DECI
-		floatVar := g.NewAnonymousVar()
-		return []Statement{*NewStatement(ASSIGN, StrParam(hir.VarToStr(floatVar)), FloatParam(factor.(*hir.Float).Val), StrParam(hir.VarToStr(floatVar)), fmt.Sprintf("%s = %s", StrParam(hir.VarToStr(floatVar)), FloatParam(factor.(*hir.Float).Val).Str()))}, floatVar
-	default:
-		return nil, 0
-	}
+// generateIndexExp 生成数组元素表达式
+func (g *MIRGenerator) generateIndexExp(indexExp hir.IndexExp) ([]Statement, int) {
+	// 数组变量
+	arrayID := g.GetVar(string(indexExp.Array))
+	// 下标语句序列，下标结果变量
+	stmtSeq, indexResultID := g.generateIndex(indexExp)
+	// 结果变量
+	resultID := g.NewAnonymousVar()
+	stmtSeq = append(stmtSeq, *NewStatement(INDEXLOAD, StrParam(hir.VarToStr(arrayID)), StrParam(hir.VarToStr(indexResultID)), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("%s = %s[%s]", hir.VarToStr(resultID), hir.VarToStr(arrayID), hir.VarToStr(indexResultID))))
+
+	return stmtSeq, resultID
 }
 
-// generateCompExp 生成比较表达式
-func (g *MIRGenerator) generateCompExp(compExp hir.CompExp) ([]Statement, int) {
-	// 右算术表达式为空
-	if compExp.Op == ast.EMPTY {
-		return g.generateExp(compExp.LExp)
+// generateIndex 生成数组下标，下标不是常量时检查是否越界，常量下标已在语义分析时检查
+func (g *MIRGenerator) generateIndex(indexExp hir.IndexExp) ([]Statement, int) {
+	stmtSeq, indexResultID := g.generateExp(indexExp.Index)
+	if _, isConst := indexExp.Index.(*hir.Integer); isConst {
+		return stmtSeq, indexResultID
 	}
 
+	// 1 下标小于0，跳转到 3
+	// 2 下标小于数组长度，跳转到 4
+	// 3 下标越界，停止执行
+	// 4 （取元素或赋值语句）
+	index := hir.VarToStr(indexResultID)
+	stmtSeq = append(stmtSeq, *NewStatement(JLESS, StrParam(index), IntParam(0), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 2)), fmt.Sprintf("if %s < 0: goto here+2", index)))
+	stmtSeq = append(stmtSeq, *NewStatement(JLESS, StrParam(index), IntParam(indexExp.Type.Len()), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 2)), fmt.Sprintf("if %s < %d: goto here+2", index, indexExp.Type.Len())))
+	stmtSeq = append(stmtSeq, *NewStatement(ERROR, StringParam(fmt.Sprintf("index out of range for array %s", indexExp.Array)), StrParam("_"), StrParam("_"), fmt.Sprintf("%s out of range: ERROR", index)))
+
+	return stmtSeq, indexResultID
+}
+
+// generateCompExp 生成比较表达式
+func (g *MIRGenerator) generateCompExp(compExp hir.BinaryExp) ([]Statement, int) {
 	// 语句序列
 	var stmtSeq []Statement
 	// 左算术表达式语句序列，左算术表达式结果变量
@@ -192,86 +244,101 @@ func (g *MIRGenerator) generateCompExp(compExp hir.CompExp) ([]Statement, int) {
 	return stmtSeq, resultID
 }
 
-// generateRelationalExp 生成关系表达式
-func (g *MIRGenerator) generateRelationalExp(relationalExp hir.RelationExp) ([]Statement, int) {
-	// 只有左比较表达式，没有右比较表达式，直接返回左比较表达式
-	if relationalExp.Op == ast.EMPTY {
-		return g.generateCompExp(relationalExp.LExp)
+// generateRelationalExp 生成以and连接的关系表达式，短路求值
+// exps: 以and连接的各操作数
+func (g *MIRGenerator) generateRelationalExp(exps []hir.Exp) ([]Statement, int) {
+	// 只有一个操作数，直接返回该操作数
+	if len(exps) == 1 {
+		return g.generateExp(exps[0])
 	}
 
 	// 语句序列
 	var stmtSeq []Statement
-	// 由于短路运算，需要先计算左表达式
-	lExpStmtSeq, lExpResultID := g.generateCompExp(relationalExp.LExp)
-	stmtSeq = append(stmtSeq, lExpStmtSeq...)
-
 	// 结果变量
 	resultID := g.NewAnonymousVar()
-	// 1 左非0，跳转到 4
-	// 2 结果为0
-	// 3 跳转到右+5
-	// 4 （右表达式判断）
-	stmtSeq = append(stmtSeq, *NewStatement(JNZERO, StrParam(hir.VarToStr(lExpResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 3)), fmt.Sprintf("if %s true: goto here+3", hir.VarToStr(lExpResultID))))
-	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(0), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("if %s false: %s = 0", hir.VarToStr(lExpResultID), hir.VarToStr(resultID))))
-	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 5)), fmt.Sprintf("goto here+len(rExpStmtSeq)+5)")))
+	// 各操作数为0时的跳转语句位置
+	var jmpIdx []int
 
-	// 若左表达式为1，判断右表达式
-	rExpStmtSeq, rExpResultID := g.generateCompExp(relationalExp.RExp)
-	stmtSeq = append(stmtSeq, rExpStmtSeq...)
-
-	// 右+1 右为0,跳转到右+4
-	// 右+2 结果为1
-	// 右+3 跳转到右+5
-	// 右+4 结果为0
-	// 右+5 （判断后语句）
-	stmtSeq = append(stmtSeq, *NewStatement(JZERO, StrParam(hir.VarToStr(rExpResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 3)), fmt.Sprintf("if %s false: goto here+3", hir.VarToStr(rExpResultID))))
-	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(1), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("if %s true: %s = 1", hir.VarToStr(rExpResultID), hir.VarToStr(resultID))))
+	// 短路运算，依次计算各操作数
+	// i+1 第i个操作数为0，跳转到 n+3
+	// n+1 结果为1
+	// n+2 跳转到 n+4
+	// n+3 结果为0
+	// n+4 （判断后语句）
+	for _, exp := range exps {
+		expStmtSeq, expResultID := g.generateExp(exp)
+		stmtSeq = append(stmtSeq, expStmtSeq...)
+		jmpIdx = append(jmpIdx, len(stmtSeq))
+		stmtSeq = append(stmtSeq, *NewStatement(JZERO, StrParam(hir.VarToStr(expResultID)), StrParam("_"), StrParam("_"), fmt.Sprintf("if %s false: goto and false", hir.VarToStr(expResultID))))
+	}
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(1), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("and true: %s = 1", hir.VarToStr(resultID))))
 	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 2)), fmt.Sprintf("goto here+2")))
-	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(0), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("if %s false: %s = 0", hir.VarToStr(rExpResultID), hir.VarToStr(resultID))))
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(0), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("and false: %s = 0", hir.VarToStr(resultID))))
 
-	// 语句序列偏移量
-	offset := len(rExpStmtSeq)
-	stmtSeq[len(lExpStmtSeq)+4].Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", offset+5))
+	// 修正跳转语句的偏移量
+	falseIdx := len(stmtSeq) - 1
+	for _, idx := range jmpIdx {
+		stmtSeq[idx].Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", falseIdx-idx))
+	}
 
 	return stmtSeq, resultID
 }
 
-// generateConditionalExp 生成条件表达式
-func (g *MIRGenerator) generateConditionalExp(conditionalExp hir.ConditionalExp) ([]Statement, int) {
-	// 只有左比较表达式，没有右比较表达式，直接返回左比较表达式
-	if conditionalExp.Op == ast.EMPTY {
-		return g.generateRelationalExp(conditionalExp.LExp)
+// generateConditionalExp 生成以or连接的条件表达式，短路求值
+// exps: 以or连接的各操作数
+func (g *MIRGenerator) generateConditionalExp(exps []hir.Exp) ([]Statement, int) {
+	// 只有一个操作数，直接返回该操作数
+	if len(exps) == 1 {
+		return g.generateExp(exps[0])
 	}
 
 	// 语句序列
 	var stmtSeq []Statement
-	// 由于短路运算，需要先计算左表达式
-	lExpStmtSeq, lExpResultID := g.generateRelationalExp(conditionalExp.LExp)
-	stmtSeq = append(stmtSeq, lExpStmtSeq...)
+	// 结果变量
 	resultID := g.NewAnonymousVar()
-	// 1 左为0，跳转到 4
-	// 2 结果为0
-	// 3 跳转到右+5
-	// 4 （右表达式判断）
-	stmtSeq = append(stmtSeq, *NewStatement(JZERO, StrParam(hir.VarToStr(lExpResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 3)), fmt.Sprintf("if %s false: goto here+3", hir.VarToStr(lExpResultID))))
-	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(1), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("if %s true: %s = 1", hir.VarToStr(lExpResultID), hir.VarToStr(resultID))))
-	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 5)), fmt.Sprintf("goto here+len(rExpStmtSeq)+5)")))
+	// 各操作数非0时的跳转语句位置
+	var jmpIdx []int
 
-	// 若左表达式为0，判断右表达式
-	rExpStmtSeq, rExpResultID := g.generateRelationalExp(conditionalExp.RExp)
-	stmtSeq = append(stmtSeq, rExpStmtSeq...)
-	// 右+1 右为0,跳转到右+4
-	// 右+2 结果为1
-	// 右+3 跳转到右+5
-	// 右+4 结果为0
-	// 右+5 （判断后语句）
-	stmtSeq = append(stmtSeq, *NewStatement(JZERO, StrParam(hir.VarToStr(rExpResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 3)), fmt.Sprintf("if %s false: goto here+3", hir.VarToStr(rExpResultID))))
-	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(1), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("if %s true: %s = 1", hir.VarToStr(rExpResultID), hir.VarToStr(resultID))))
+	// 短路运算，依次计算各操作数
+	// i+1 第i个操作数非0，跳转到 n+3
+	// n+1 结果为0
+	// n+2 跳转到 n+4
+	// n+3 结果为1
+	// n+4 （判断后语句）
+	for _, exp := range exps {
+		expStmtSeq, expResultID := g.generateExp(exp)
+		stmtSeq = append(stmtSeq, expStmtSeq...)
+		jmpIdx = append(jmpIdx, len(stmtSeq))
+		stmtSeq = append(stmtSeq, *NewStatement(JNZERO, StrParam(hir.VarToStr(expResultID)), StrParam("_"), StrParam("_"), fmt.Sprintf("if %s true: goto or true", hir.VarToStr(expResultID))))
+	}
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(0), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("or false: %s = 0", hir.VarToStr(resultID))))
+	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 2)), fmt.Sprintf("goto here+2")))
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(1), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("or true: %s = 1", hir.VarToStr(resultID))))
+
+	// 修正跳转语句的偏移量
+	trueIdx := len(stmtSeq) - 1
+	for _, idx := range jmpIdx {
+		stmtSeq[idx].Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", trueIdx-idx))
+	}
+
+	return stmtSeq, resultID
+}
+
+// generateNotExp 生成逻辑非
+func (g *MIRGenerator) generateNotExp(exp hir.Exp) ([]Statement, int) {
+	// 操作数语句序列，操作数结果变量
+	stmtSeq, expResultID := g.generateExp(exp)
+	// 结果变量
+	resultID := g.NewAnonymousVar()
+	// 1 操作数非0，跳转到 4
+	// 2 结果为1
+	// 3 跳转到 5
+	// 4 结果为0
+	// 5 （判断后语句）
+	stmtSeq = append(stmtSeq, *NewStatement(JNZERO, StrParam(hir.VarToStr(expResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 3)), fmt.Sprintf("if %s true: goto here+3", hir.VarToStr(expResultID))))
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(1), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("not %s: %s = 1", hir.VarToStr(expResultID), hir.VarToStr(resultID))))
 	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", 2)), fmt.Sprintf("goto here+2")))
-	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(0), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("if %s false: %s = 0", hir.VarToStr(rExpResultID), hir.VarToStr(resultID))))
+	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultID)), IntParam(0), StrParam(hir.VarToStr(resultID)), fmt.Sprintf("not %s: %s = 0", hir.VarToStr(expResultID), hir.VarToStr(resultID))))
 
-	// 语句序列偏移量
-	offset := len(rExpStmtSeq)
-	stmtSeq[len(lExpStmtSeq)+4].Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", offset+5))
 	return stmtSeq, resultID
 }
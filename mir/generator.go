@@ -4,7 +4,6 @@ import (
 	"CompilerInGo/hir"
 	"fmt"
 	"github.com/kpango/glg"
-	"strings"
 )
 
 // Context 上下文
@@ -20,7 +19,8 @@ type MethodInfo struct {
 	Name      string // 方法名
 	Pos       int    // 方法在程序中的位置
 	ActParams []int  // 方法实参
-	ReturnVar int    // 方法返回值
+	ReturnVar int    // 方法返回地址
+	ResultVar int    // 方法返回值
 }
 
 // MIRGenerator 中间代码生成器
@@ -28,6 +28,8 @@ type MIRGenerator struct {
 	Program    *Program              // 中间代码
 	HIRProgram *hir.Program          // HIR程序
 	Vars       map[string]int        // 变量表
+	Globals    map[string]int        // 全局变量表，所有方法共享
+	VarNum     int                   // 已分配的变量数量
 	Labels     map[int]int           // 标签表
 	Methods    map[string]MethodInfo // 方法表
 	Context    Context               // 当前上下文
@@ -50,6 +52,7 @@ func NewMIRGenerator() *MIRGenerator {
 	return &MIRGenerator{
 		Program:  NewProgram(),
 		Vars:     make(map[string]int),
+		Globals:  make(map[string]int),
 		Methods:  make(map[string]MethodInfo),
 		Context:  context,
 		CtxStack: ctxStack,
@@ -69,6 +72,9 @@ func (g *MIRGenerator) Generate(program *hir.Program) *Program {
 		glg.Fatal("No main method found")
 	}
 
+	// 全局变量、常量的声明及初始化，在main方法之前执行
+	g.Program.StmtSeq = append(g.Program.StmtSeq, g.generateGlobals(program.Globals)...)
+
 	// 生成main方法
 	g.Program.StmtSeq = append(g.Program.StmtSeq, g.generateStatement(*mainMethod.Body)...)
 
@@ -117,50 +123,35 @@ func (g *MIRGenerator) Generate(program *hir.Program) *Program {
 		}
 	}
 
-	// 处理continue和break
-	for idx, stmt := range g.Program.StmtSeq {
-		comment := stmt.Comment
-		if comment == "_T_CONTINUE" {
-			for i := idx; i < len(g.Program.StmtSeq); i++ {
-				if strings.HasPrefix(g.Program.StmtSeq[i].Comment, "next loop:") {
-					_ = glg.Warn(g.Program.StmtSeq[i].Str())
-					g.Program.StmtSeq[idx].Res = IntParam(g.Program.StmtSeq[i].Res.Int())
-					break
-				}
-				if i == len(g.Program.StmtSeq)-1 {
-					glg.Fatal("No loop end found")
-				}
-			}
-		} else if comment == "_T_BREAK" {
-			for i := idx; i >= 0; i-- {
-				if strings.HasPrefix(g.Program.StmtSeq[i].Comment, "while condition") {
-					g.Program.StmtSeq[idx].Res = IntParam(g.Program.StmtSeq[i].Res.Int())
-					break
-				}
-				if i == 0 {
-					glg.Fatal("No loop context found")
-				}
-			}
-		}
-	}
-
 	return g.Program
 }
 
 // NewVar 生成新的变量
 func (g *MIRGenerator) NewVar(name string) int {
-	g.Vars[name] = len(g.Vars) + 1
-	return g.Vars[name]
+	// 按分配顺序编号，同名变量（如不同方法中的变量）不会得到相同的编号
+	g.VarNum++
+	g.Vars[name] = g.VarNum
+	return g.VarNum
+}
+
+// NewGlobal 生成新的全局变量，所有方法中都可以通过变量名访问
+func (g *MIRGenerator) NewGlobal(name string) int {
+	g.VarNum++
+	g.Globals[name] = g.VarNum
+	return g.VarNum
 }
 
 // NewAnonymousVar 生成匿名变量
 func (g *MIRGenerator) NewAnonymousVar() int {
-	return g.NewVar(fmt.Sprintf("%d", len(g.Vars)+2))
+	return g.NewVar(fmt.Sprintf("%d", g.VarNum+1))
 }
 
-// GetVar 获取变量
+// GetVar 获取变量，当前方法中没有时查找全局变量
 func (g *MIRGenerator) GetVar(name string) int {
-	return g.Vars[name]
+	if id, ok := g.Vars[name]; ok {
+		return id
+	}
+	return g.Globals[name]
 }
 
 // Print 打印中间代码
@@ -2,6 +2,7 @@ package mir
 
 import (
 	"CompilerInGo/hir"
+	"fmt"
 )
 
 // generateMethod 生成方法
@@ -10,26 +11,33 @@ func (g *MIRGenerator) generateMethod(method hir.Method) ([]Statement, []int, in
 	var stmtSeq []Statement
 	// 参数变量
 	var paramIDs []int
-	// 返回值变量
+	// 返回地址变量
 	returnVar := g.NewAnonymousVar()
+	// 返回值变量
+	resultVar := g.NewAnonymousVar()
 
-	// 解析参数
+	// 解析参数，结构体参数按字段展开为多个形参
 	for _, param := range method.Params {
-		// 形参局部变量声明语句
-		stmt := g.NewLocalVariableDeclaration(param.Type, param.ID)
-		stmtSeq = append(stmtSeq, *stmt)
-		// 添加到形参列表
-		paramIDs = append(paramIDs, hir.StrToVar(stmt.Res.Str()))
+		for _, field := range g.scalars(param.Type, param.ID) {
+			// 形参局部变量声明语句
+			stmt := g.NewLocalVariableDeclaration(field.Type, field.ID)
+			stmtSeq = append(stmtSeq, *stmt)
+			// 添加到形参列表
+			paramIDs = append(paramIDs, hir.StrToVar(stmt.Res.Str()))
+		}
 	}
 
 	// 添加到方法列表
 	methodInfo := g.Methods[method.Name]
 	methodInfo.ReturnVar = returnVar
+	methodInfo.ResultVar = resultVar
 	methodInfo.ActParams = paramIDs
 	g.Methods[method.Name] = methodInfo
 
 	// 解析方法体
 	stmtSeq = append(stmtSeq, g.generateStatement(*method.Body)...)
+	// 方法体执行完毕而没有return时，返回调用者
+	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(hir.VarToStr(returnVar)), fmt.Sprintf("method %s end: goto %s", method.Name, hir.VarToStr(returnVar))))
 
 	// 使用注释标注方法开始位置
 	stmtSeq[0].Comment = stmtSeq[0].Comment + " # method: " + method.Name
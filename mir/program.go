@@ -8,14 +8,21 @@ import (
 
 // 操作符
 const (
-	ERROR = iota
+	ERROR = iota // 运行时错误，Arg1为错误信息，执行后停止
 	ASSIGN
 	PLUS
 	MINUS
 	TIMES
 	DIVIDE
+	MOD
+	NEG
+	CONCAT     // 字符串拼接
+	READ       // 输入，Arg1为变量类型，Res为读取到的变量
+	WRITE      // 输出，Arg1为值的类型，Arg2为输出的值
+	INDEXLOAD  // 数组取元素，Arg1为数组，Arg2为下标，Res为取到的值
+	INDEXSTORE // 数组元素赋值，Arg1为值，Arg2为下标，Res为数组
 	JMP
-	JEQUAL
+	JEQUAL // 条件跳转的操作数为字符串时按字典序比较，为字符时按编码比较
 	JNEQUAL
 	JGREAT
 	JGREATEQUAL
@@ -34,6 +41,13 @@ var OpString = map[int]string{
 	MINUS:       "-",
 	TIMES:       "*",
 	DIVIDE:      "/",
+	MOD:         "%",
+	NEG:         "neg",
+	CONCAT:      "concat",
+	READ:        "read",
+	WRITE:       "write",
+	INDEXLOAD:   "=[]",
+	INDEXSTORE:  "[]=",
 	JMP:         "j",
 	JEQUAL:      "j=",
 	JNEQUAL:     "j!=",
@@ -57,7 +71,8 @@ func NewProgram() *Program {
 	}
 }
 
-// Param 参数，可以是IntParam, FloatParam, StrParam， *Statement
+// Param 参数，可以是IntParam, FloatParam, StrParam, StringParam, CharParam， *Statement
+// StrParam为变量名或跳转标记，StringParam、CharParam为字符串、字符字面量，输出时加引号以与变量名区分
 type Param interface {
 	p()
 	Str() string
@@ -105,6 +120,31 @@ type FloatParam float64
 
 func (f FloatParam) p() {}
 
+type StringParam string
+
+func (s StringParam) p() {}
+
+func (s StringParam) Str() string {
+	return strconv.Quote(string(s))
+}
+
+func (s StringParam) Int() int {
+	glg.Fatal("Cannot convert string to int")
+	return 0
+}
+
+type CharParam rune
+
+func (c CharParam) p() {}
+
+func (c CharParam) Str() string {
+	return strconv.QuoteRune(rune(c))
+}
+
+func (c CharParam) Int() int {
+	return int(c)
+}
+
 func (s *Statement) p() {}
 
 func (s *Statement) Str() string {
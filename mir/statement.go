@@ -4,6 +4,7 @@ import (
 	"CompilerInGo/hir"
 	"fmt"
 	"github.com/kpango/glg"
+	"strings"
 )
 
 // generateStatement 生成语句
@@ -14,6 +15,12 @@ func (g *MIRGenerator) generateStatement(stmt hir.Statement) []Statement {
 		return g.generateConditionalStatement(stmt.(hir.ConditionalStatement))
 	case hir.LoopStatement:
 		return g.generateLoopStatement(stmt.(hir.LoopStatement))
+	case hir.ForStatement:
+		return g.generateForStatement(stmt.(hir.ForStatement))
+	case hir.DoWhileStatement:
+		return g.generateDoWhileStatement(stmt.(hir.DoWhileStatement))
+	case hir.SwitchStatement:
+		return g.generateSwitchStatement(stmt.(hir.SwitchStatement))
 	case hir.CallStatement:
 		return g.generateCallStatement(stmt.(hir.CallStatement))
 	case hir.AssignStatement:
@@ -26,6 +33,10 @@ func (g *MIRGenerator) generateStatement(stmt hir.Statement) []Statement {
 		return g.generateBreakStatement(stmt.(hir.BreakStatement))
 	case hir.ContinueStatement:
 		return g.generateContinueStatement(stmt.(hir.ContinueStatement))
+	case hir.ReadStatement:
+		return g.generateReadStatement(stmt.(hir.ReadStatement))
+	case hir.WriteStatement:
+		return g.generateWriteStatement(stmt.(hir.WriteStatement))
 	case hir.Block:
 		return g.generateBlock(stmt.(hir.Block))
 	default:
@@ -47,9 +58,33 @@ func (g *MIRGenerator) generateBlock(block hir.Block) []Statement {
 // generateLocalVariableDeclaration 生成局部变量声明语句
 func (g *MIRGenerator) generateLocalVariableDeclaration(stmt hir.LocalVariableDeclaration) []Statement {
 	var stmtSeq []Statement
-	for _, pair := range stmt.TypeIDPair {
-		// 遍历局部变量声明语句中的变量
-		stmtSeq = append(stmtSeq, *g.NewLocalVariableDeclaration(pair.Type, pair.ID))
+	for i, pair := range stmt.TypeIDPair {
+		// 遍历局部变量声明语句中的变量，结构体按字段展开
+		for _, field := range g.scalars(pair.Type, pair.ID) {
+			stmtSeq = append(stmtSeq, *g.NewLocalVariableDeclaration(field.Type, field.ID))
+		}
+		// 有初始值时，声明之后赋值
+		if i < len(stmt.Inits) && stmt.Inits[i] != nil {
+			stmtSeq = append(stmtSeq, g.generateAssignStatement(hir.NewAssignStatement(string(pair.ID), stmt.Inits[i]))...)
+		}
+	}
+	return stmtSeq
+}
+
+// generateGlobals 生成全局变量、常量的声明及初始化语句
+func (g *MIRGenerator) generateGlobals(globals []hir.Global) []Statement {
+	var stmtSeq []Statement
+	for _, global := range globals {
+		// 定义新的全局变量，结构体按字段展开
+		for _, field := range g.scalars(global.Type, global.ID) {
+			varID := g.NewGlobal(string(field.ID))
+			name := declName(field.Type, field.ID)
+			stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(varID)), StrParam(name), StrParam(hir.VarToStr(varID)), fmt.Sprintf("global %s = %s", hir.VarToStr(varID), name)))
+		}
+		// 有初始值时，声明之后赋值
+		if global.Init != nil {
+			stmtSeq = append(stmtSeq, g.generateAssignStatement(hir.NewAssignStatement(string(global.ID), global.Init))...)
+		}
 	}
 	return stmtSeq
 }
@@ -58,17 +93,59 @@ func (g *MIRGenerator) generateLocalVariableDeclaration(stmt hir.LocalVariableDe
 func (g *MIRGenerator) NewLocalVariableDeclaration(t hir.Type, id hir.ID) *Statement {
 	// 定义新变量
 	varID := g.NewVar(string(id))
+	name := declName(t, id)
 	// 赋值语句
-	return NewStatement(ASSIGN, StrParam(hir.VarToStr(varID)), StrParam(id), StrParam(hir.VarToStr(varID)), fmt.Sprintf("%s = %s", hir.VarToStr(varID), id))
+	return NewStatement(ASSIGN, StrParam(hir.VarToStr(varID)), StrParam(name), StrParam(hir.VarToStr(varID)), fmt.Sprintf("%s = %s", hir.VarToStr(varID), name))
+}
+
+// declName 声明语句中的变量名，数组带有长度，如a[10]，供分配数组的存储空间
+func declName(t hir.Type, id hir.ID) string {
+	if t.IsArray() {
+		return fmt.Sprintf("%s[%d]", id, t.Len())
+	}
+	return string(id)
+}
+
+// scalars 将类型为t的变量id展开为非结构体的变量，结构体按字段递归展开，字段名如p.q.x
+func (g *MIRGenerator) scalars(t hir.Type, id hir.ID) []hir.TypeIDPair {
+	if !t.IsStruct() {
+		return []hir.TypeIDPair{*hir.NewTypeIDPair(t, string(id))}
+	}
+	var pairs []hir.TypeIDPair
+	for _, field := range g.HIRProgram.Structs[t.StructIndex()].Fields {
+		pairs = append(pairs, g.scalars(field.Type, id+"."+field.ID)...)
+	}
+	return pairs
 }
 
 // generateAssignStatement 生成赋值语句
 func (g *MIRGenerator) generateAssignStatement(stmt hir.AssignStatement) []Statement {
 	var stmtSeq []Statement
 
+	// 结构体赋值，按字段逐个复制
+	if structExp, ok := stmt.Exp.(*hir.StructExp); ok {
+		targets, sources := g.scalars(structExp.Type, hir.ID(stmt.Target)), g.scalars(structExp.Type, structExp.Var)
+		for i := range targets {
+			targetID, sourceID := g.GetVar(string(targets[i].ID)), g.GetVar(string(sources[i].ID))
+			stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(targetID)), StrParam(hir.VarToStr(sourceID)), StrParam(hir.VarToStr(targetID)), fmt.Sprintf("%s = %s", targets[i].ID, sources[i].ID)))
+		}
+		return stmtSeq
+	}
+
 	// 待赋值的变量
 	varID := g.GetVar(stmt.Target)
 
+	// 数组元素赋值
+	if stmt.Index != nil {
+		// 下标语句序列，下标结果变量
+		indexStmtSeq, indexResultID := g.generateIndex(*stmt.Index)
+		stmtSeq = append(stmtSeq, indexStmtSeq...)
+		expStmtSeq, expResultID := g.generateExp(stmt.Exp)
+		stmtSeq = append(stmtSeq, expStmtSeq...)
+		stmtSeq = append(stmtSeq, *NewStatement(INDEXSTORE, StrParam(hir.VarToStr(expResultID)), StrParam(hir.VarToStr(indexResultID)), StrParam(hir.VarToStr(varID)), fmt.Sprintf("%s[%s] = %s", hir.VarToStr(varID), hir.VarToStr(indexResultID), hir.VarToStr(expResultID))))
+		return stmtSeq
+	}
+
 	// 解析表达式语句和表达式值的结果变量
 	expStmtSeq, expResultID := g.generateExp(stmt.Exp)
 	stmtSeq = append(stmtSeq, expStmtSeq...)
@@ -82,12 +159,11 @@ func (g *MIRGenerator) generateReturnStatement(stmt hir.ReturnStatement) []State
 	// 解析表达式语句和表达式值的结果变量
 	stmtSeq, expResultID := g.generateExp(stmt.Exp)
 
-	// 退出当前方法，恢复上下文
-	g.Context = g.CtxStack.Top()
-	g.CtxStack.Pop()
-
 	// 若为main方法，则生成STOP语句
 	if g.Context.MethodIn.Name != "main" {
+		// 将返回值保存到方法的返回值变量，由调用者取出
+		resultVar := g.Methods[g.Context.MethodIn.Name].ResultVar
+		stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(resultVar)), StrParam(hir.VarToStr(expResultID)), StrParam(hir.VarToStr(resultVar)), fmt.Sprintf("method %s result: %s = %s", g.Context.MethodIn.Name, hir.VarToStr(resultVar), hir.VarToStr(expResultID))))
 		stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(hir.VarToStr(g.Methods[g.Context.MethodIn.Name].ReturnVar)), fmt.Sprintf("method %s return value %s : goto %s", g.Context.MethodIn.Name, hir.VarToStr(expResultID), hir.VarToStr(g.Methods[g.Context.MethodIn.Name].ReturnVar))))
 	} else {
 		stmtSeq = append(stmtSeq, *NewStatement(STOP, StrParam("_"), StrParam("_"), StrParam(hir.VarToStr(expResultID)), fmt.Sprintf("main return value: %s : STOP", hir.VarToStr(expResultID))))
@@ -97,19 +173,24 @@ func (g *MIRGenerator) generateReturnStatement(stmt hir.ReturnStatement) []State
 
 // generateCallStatement 生成调用语句
 func (g *MIRGenerator) generateCallStatement(stmt hir.CallStatement) []Statement {
-	var stmtSeq []Statement
+	// 调用语句丢弃方法的返回值
+	stmtSeq, _ := g.generateCall(stmt.Method, stmt.ActParam)
+	return stmtSeq
+}
 
-	// 解析新的函数，生成新的上下文
-	g.Context = Context{
-		MethodIn:      MethodInfo{Name: stmt.Method},
-		LoopCondLabel: -1,
-		LoopEndLabel:  -1,
-	}
-	g.CtxStack.Push(g.Context)
+// generateCall 生成方法调用，返回调用语句序列及被调用方法的信息
+func (g *MIRGenerator) generateCall(name string, actParam []hir.Exp) ([]Statement, MethodInfo) {
+	var stmtSeq []Statement
 
-	// 解析实参
+	// 解析实参，结构体实参按字段展开，与形参一一对应
 	var actParams []int
-	for _, exp := range stmt.ActParam {
+	for _, exp := range actParam {
+		if structExp, ok := exp.(*hir.StructExp); ok {
+			for _, field := range g.scalars(structExp.Type, structExp.Var) {
+				actParams = append(actParams, g.GetVar(string(field.ID)))
+			}
+			continue
+		}
 		// 将表达式的结果赋值给实参，并加入到实参列表中
 		expStmtSeq, expResultID := g.generateExp(exp)
 		stmtSeq = append(stmtSeq, expStmtSeq...)
@@ -117,17 +198,29 @@ func (g *MIRGenerator) generateCallStatement(stmt hir.CallStatement) []Statement
 	}
 
 	// 若方法未定义，则生成新方法
-	method, ok := g.Methods[stmt.Method]
+	method, ok := g.Methods[name]
 	if !ok {
-		// 生成新方法
-		g.NewMethod(stmt.Method)
-		method = g.Methods[stmt.Method]
-		methodStmtSeq, formalParams, returnLabel := g.generateMethod(*g.HIRProgram.GetMethod(stmt.Method))
+		// 解析新的方法，保存当前上下文及变量表并切换到新的上下文
+		g.CtxStack.Push(g.Context)
+		g.Context = Context{
+			MethodIn:      MethodInfo{Name: name},
+			LoopCondLabel: -1,
+			LoopEndLabel:  -1,
+		}
+		vars := g.Vars
+		g.Vars = make(map[string]int)
+
+		// 生成新方法，形参、返回地址及返回值变量在generateMethod中记录
+		g.NewMethod(name)
+		methodStmtSeq, _, _ := g.generateMethod(*g.HIRProgram.GetMethod(name))
+		method = g.Methods[name]
 		method.Pos = len(g.MethodSeq)
 		g.MethodSeq = append(g.MethodSeq, methodStmtSeq...)
-		method.ActParams = formalParams
-		method.ReturnVar = returnLabel
-		g.Methods[stmt.Method] = method
+		g.Methods[name] = method
+
+		// 恢复上下文及变量表
+		g.Context = g.CtxStack.Pop()
+		g.Vars = vars
 	}
 
 	// 将实参赋值给形参
@@ -138,9 +231,9 @@ func (g *MIRGenerator) generateCallStatement(stmt hir.CallStatement) []Statement
 	// 生成执行完后跳转位置的标签
 	stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(method.ReturnVar)), StrParam("_T_HERE_TO_JMP+1"), StrParam(hir.VarToStr(method.ReturnVar)), fmt.Sprintf("call returnTo: %s", hir.VarToStr(method.ReturnVar))))
 	// 生成跳转语句
-	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_METHOD_%s", stmt.Method)), fmt.Sprintf("call method: %s", stmt.Method)))
+	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_METHOD_%s", name)), fmt.Sprintf("call method: %s", name)))
 
-	return stmtSeq
+	return stmtSeq, method
 }
 
 // generateConditionalStatement 生成条件语句
@@ -148,7 +241,7 @@ func (g *MIRGenerator) generateConditionalStatement(stmt hir.ConditionalStatemen
 	var stmtSeq []Statement
 
 	// 解析条件语句中的条件表达式
-	expStmtSeq, expResultID := g.generateConditionalExp(stmt.Condition)
+	expStmtSeq, expResultID := g.generateExp(stmt.Condition)
 	stmtSeq = append(stmtSeq, expStmtSeq...)
 
 	// 若条件为假，则跳转到else语句块/if语句块结束
@@ -156,9 +249,13 @@ func (g *MIRGenerator) generateConditionalStatement(stmt hir.ConditionalStatemen
 
 	// 若条件为真，则执行if语句块
 	trueSeq := g.generateStatement(*stmt.IfBody)
-	// 设置跳转语句的跳转位置
-	expFalseStmt.Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", len(trueSeq)+2))
-	expFalseStmt.Comment = fmt.Sprintf("if condition false: goto here+%d", len(trueSeq)+2)
+	// 设置跳转语句的跳转位置，有else语句块时还需跳过if语句块之后的跳转语句
+	skip := len(trueSeq) + 1
+	if stmt.ElseBody != nil {
+		skip++
+	}
+	expFalseStmt.Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", skip))
+	expFalseStmt.Comment = fmt.Sprintf("if condition false: goto here+%d", skip)
 	stmtSeq = append(stmtSeq, expFalseStmt)
 
 	// 添加if语句块
@@ -181,7 +278,7 @@ func (g *MIRGenerator) generateLoopStatement(stmt hir.LoopStatement) []Statement
 	var stmtSeq []Statement
 
 	// 解析循环语句中的条件表达式
-	expStmtSeq, expResultID := g.generateConditionalExp(stmt.Condition)
+	expStmtSeq, expResultID := g.generateExp(stmt.Condition)
 	stmtSeq = append(stmtSeq, expStmtSeq...)
 
 	// 循环体
@@ -197,19 +294,255 @@ func (g *MIRGenerator) generateLoopStatement(stmt hir.LoopStatement) []Statement
 	stmtSeq = append(stmtSeq, bodySeq...)
 	stmtSeq = append(stmtSeq, nextLoopStmt)
 
+	// continue跳转到条件表达式判断，break跳转到循环结束
+	resolveLoopJumps(stmtSeq, 0, len(stmtSeq))
+
+	return stmtSeq
+}
+
+// generateForStatement 生成for循环语句，布局与while循环相同，步进语句位于循环体之后
+func (g *MIRGenerator) generateForStatement(stmt hir.ForStatement) []Statement {
+	var stmtSeq []Statement
+
+	// 初始化语句
+	if stmt.Init != nil {
+		stmtSeq = append(stmtSeq, g.generateStatement(*stmt.Init)...)
+	}
+	condPos := len(stmtSeq)
+
+	// 解析循环语句中的条件表达式，没有条件时总是继续循环
+	var expStmtSeq []Statement
+	var expResultID int
+	if stmt.Condition != nil {
+		expStmtSeq, expResultID = g.generateExp(stmt.Condition)
+	}
+
+	// 循环体及步进语句
+	bodySeq := g.generateStatement(*stmt.Body)
+	var stepSeq []Statement
+	if stmt.Step != nil {
+		stepSeq = g.generateStatement(*stmt.Step)
+	}
+
+	// 语句拼接
+	stmtSeq = append(stmtSeq, expStmtSeq...)
+	if stmt.Condition != nil {
+		// 跳转到循环结束
+		skip := len(bodySeq) + len(stepSeq) + 2
+		stmtSeq = append(stmtSeq, *NewStatement(JZERO, StrParam(hir.VarToStr(expResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", skip)), fmt.Sprintf("for condition %s false : skip loop: goto here+%d", hir.VarToStr(expResultID), skip)))
+	}
+	stmtSeq = append(stmtSeq, bodySeq...)
+	stepPos := len(stmtSeq)
+	stmtSeq = append(stmtSeq, stepSeq...)
+	// 跳转到条件表达式判断
+	next := condPos - len(stmtSeq)
+	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", next)), fmt.Sprintf("next loop: goto here+%d", next)))
+
+	// continue跳转到步进语句，break跳转到循环结束
+	resolveLoopJumps(stmtSeq, stepPos, len(stmtSeq))
+
+	return stmtSeq
+}
+
+// generateDoWhileStatement 生成do-while循环语句，先执行循环体，条件为真时跳转回循环体开始
+func (g *MIRGenerator) generateDoWhileStatement(stmt hir.DoWhileStatement) []Statement {
+	var stmtSeq []Statement
+
+	// 循环体
+	stmtSeq = append(stmtSeq, g.generateStatement(*stmt.Body)...)
+	condPos := len(stmtSeq)
+
+	// 解析循环语句中的条件表达式
+	expStmtSeq, expResultID := g.generateExp(stmt.Condition)
+	stmtSeq = append(stmtSeq, expStmtSeq...)
+
+	// 条件为真时跳转到循环体开始
+	next := -len(stmtSeq)
+	stmtSeq = append(stmtSeq, *NewStatement(JNZERO, StrParam(hir.VarToStr(expResultID)), StrParam("_"), StrParam(fmt.Sprintf("_T_JMP_REF_%d", next)), fmt.Sprintf("do-while condition %s true : next loop: goto here+%d", hir.VarToStr(expResultID), next)))
+
+	// continue跳转到条件表达式判断，break跳转到循环结束
+	resolveLoopJumps(stmtSeq, condPos, len(stmtSeq))
+
+	return stmtSeq
+}
+
+// 分支数不少于jumpTableMinCases，且case常量的范围不超过分支数的jumpTableMaxSpread倍时，switch语句使用跳转表
+const (
+	jumpTableMinCases  = 3
+	jumpTableMaxSpread = 2
+)
+
+// generateSwitchStatement 生成switch语句
+// 先生成分派语句，跳转到匹配的分支或default分支，没有时跳转到switch结束；之后按源程序顺序拼接各分支，依次贯穿执行
+// case常量密集时分派语句为跳转表，否则为逐个比较的条件跳转
+func (g *MIRGenerator) generateSwitchStatement(stmt hir.SwitchStatement) []Statement {
+	var stmtSeq []Statement
+
+	// 解析被匹配的表达式
+	expStmtSeq, expResultID := g.generateExp(stmt.Exp)
+	stmtSeq = append(stmtSeq, expStmtSeq...)
+
+	// 各分支的语句序列，case常量的范围
+	bodySeqs := make([][]Statement, len(stmt.Cases))
+	bodyLen, caseNum, defaultCase := 0, 0, -1
+	var minValue, maxValue int64
+	for i, c := range stmt.Cases {
+		if c.Body != nil {
+			bodySeqs[i] = g.generateStatement(*c.Body)
+		}
+		bodyLen += len(bodySeqs[i])
+		if c.Default {
+			defaultCase = i
+			continue
+		}
+		if caseNum == 0 || c.Value < minValue {
+			minValue = c.Value
+		}
+		if caseNum == 0 || c.Value > maxValue {
+			maxValue = c.Value
+		}
+		caseNum++
+	}
+	// case常量的跨度按无符号数计算，避免常量相距过远时溢出
+	span := uint64(maxValue) - uint64(minValue)
+	useTable := caseNum >= jumpTableMinCases && span < uint64(jumpTableMaxSpread*caseNum)
+
+	// 分派语句的长度，跳转表为2条范围检查、3条计算跳转地址的语句、1条间接跳转及表项，条件跳转为每个case一条及最后一条无条件跳转
+	dispatchLen := caseNum + 1
+	if useTable {
+		dispatchLen = 6 + int(span) + 1
+	}
+	// 各分支开始位置及switch结束位置在stmtSeq中的下标，没有匹配的分支时跳转到default分支或结束
+	casePos := make([]int, len(stmt.Cases))
+	pos := len(stmtSeq) + dispatchLen
+	for i := range stmt.Cases {
+		casePos[i] = pos
+		pos += len(bodySeqs[i])
+	}
+	endPos := pos
+	missPos := endPos
+	if defaultCase >= 0 {
+		missPos = casePos[defaultCase]
+	}
+	// ref 当前语句跳转到target的相对跳转标记
+	ref := func(target int) StrParam {
+		return StrParam(fmt.Sprintf("_T_JMP_REF_%d", target-len(stmtSeq)))
+	}
+	// value case常量在中间代码中的表示，字符常量为字符
+	value := func(v int64) Param {
+		if stmt.Type == hir.TChar {
+			return CharParam(v)
+		}
+		return IntParam(v)
+	}
+	expVar := StrParam(hir.VarToStr(expResultID))
+
+	if useTable {
+		// 范围检查，超出范围时跳转到default分支或结束
+		stmtSeq = append(stmtSeq, *NewStatement(JLESS, expVar, value(minValue), ref(missPos), fmt.Sprintf("switch %s < %s: goto here+%d", expVar, value(minValue).Str(), missPos-len(stmtSeq))))
+		stmtSeq = append(stmtSeq, *NewStatement(JGREAT, expVar, value(maxValue), ref(missPos), fmt.Sprintf("switch %s > %s: goto here+%d", expVar, value(maxValue).Str(), missPos-len(stmtSeq))))
+		// 跳转地址 = 间接跳转的下一条语句 + (值 - 最小值)
+		// _T_HERE_TO_JMP+1为当前语句+2，即间接跳转的位置，因此偏移量多加1
+		offsetVar, addrVar := g.NewAnonymousVar(), g.NewAnonymousVar()
+		stmtSeq = append(stmtSeq, *NewStatement(MINUS, expVar, IntParam(minValue-1), StrParam(hir.VarToStr(offsetVar)), fmt.Sprintf("jump table offset: %s = %s - %d", hir.VarToStr(offsetVar), expVar, minValue-1)))
+		stmtSeq = append(stmtSeq, *NewStatement(ASSIGN, StrParam(hir.VarToStr(addrVar)), StrParam("_T_HERE_TO_JMP+1"), StrParam(hir.VarToStr(addrVar)), fmt.Sprintf("jump table base: %s", hir.VarToStr(addrVar))))
+		stmtSeq = append(stmtSeq, *NewStatement(PLUS, StrParam(hir.VarToStr(addrVar)), StrParam(hir.VarToStr(offsetVar)), StrParam(hir.VarToStr(addrVar)), fmt.Sprintf("%s = %s + %s", hir.VarToStr(addrVar), hir.VarToStr(addrVar), hir.VarToStr(offsetVar))))
+		stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam(hir.VarToStr(addrVar)), fmt.Sprintf("jump table: goto %s", hir.VarToStr(addrVar))))
+		// 表项，没有对应case的值跳转到default分支或结束
+		targets := make(map[int64]int)
+		for i, c := range stmt.Cases {
+			if !c.Default {
+				targets[c.Value] = casePos[i]
+			}
+		}
+		for i := uint64(0); i <= span; i++ {
+			v := minValue + int64(i)
+			target, ok := targets[v]
+			if !ok {
+				target = missPos
+			}
+			stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), ref(target), fmt.Sprintf("jump table %d: goto here+%d", v, target-len(stmtSeq))))
+		}
+	} else {
+		// 逐个比较case常量，相等时跳转到对应分支
+		for i, c := range stmt.Cases {
+			if c.Default {
+				continue
+			}
+			stmtSeq = append(stmtSeq, *NewStatement(JEQUAL, expVar, value(c.Value), ref(casePos[i]), fmt.Sprintf("case %s: goto here+%d", value(c.Value).Str(), casePos[i]-len(stmtSeq))))
+		}
+		stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), ref(missPos), fmt.Sprintf("no case matched: goto here+%d", missPos-len(stmtSeq))))
+	}
+
+	// 按源程序顺序拼接各分支
+	for i, bodySeq := range bodySeqs {
+		if len(bodySeq) > 0 && stmt.Cases[i].Default {
+			bodySeq[0].Comment = fmt.Sprintf("default: %s", bodySeq[0].Comment)
+		} else if len(bodySeq) > 0 {
+			bodySeq[0].Comment = fmt.Sprintf("case %s: %s", value(stmt.Cases[i].Value).Str(), bodySeq[0].Comment)
+		}
+		stmtSeq = append(stmtSeq, bodySeq...)
+	}
+
+	// break跳转到switch结束，continue由外层循环修正
+	resolveJumps(stmtSeq, "_T_BREAK", "break", endPos)
+
+	return stmtSeq
+}
+
+// resolveLoopJumps 将循环语句序列stmtSeq中的break、continue语句修正为相对跳转
+// continuePos、breakPos为continue、break跳转到的位置在stmtSeq中的下标
+// 内层循环及switch语句的break、continue已由内层修正，不会再次匹配
+func resolveLoopJumps(stmtSeq []Statement, continuePos, breakPos int) {
+	resolveJumps(stmtSeq, "_T_BREAK", "break", breakPos)
+	resolveJumps(stmtSeq, "_T_CONTINUE", "continue", continuePos)
+}
+
+// resolveJumps 将语句序列stmtSeq中跳转标记为marker的语句修正为跳转到下标pos的相对跳转，name为注释中的跳转名称
+func resolveJumps(stmtSeq []Statement, marker, name string, pos int) {
+	for idx := range stmtSeq {
+		if stmtSeq[idx].Res.Str() != marker {
+			continue
+		}
+		// 注释可能带有前缀（如true block:），只替换其中的标记
+		stmtSeq[idx].Res = StrParam(fmt.Sprintf("_T_JMP_REF_%d", pos-idx))
+		stmtSeq[idx].Comment = strings.Replace(stmtSeq[idx].Comment, marker, fmt.Sprintf("%s: goto here+%d", name, pos-idx), 1)
+	}
+}
+
+// generateReadStatement 生成输入语句，每个变量生成一条READ语句
+func (g *MIRGenerator) generateReadStatement(stmt hir.ReadStatement) []Statement {
+	var stmtSeq []Statement
+	for _, pair := range stmt.Vars {
+		varID := g.GetVar(string(pair.ID))
+		stmtSeq = append(stmtSeq, *NewStatement(READ, StrParam(pair.Type.String()), StrParam("_"), StrParam(hir.VarToStr(varID)), fmt.Sprintf("read %s %s", pair.Type, hir.VarToStr(varID))))
+	}
+	return stmtSeq
+}
+
+// generateWriteStatement 生成输出语句，每个表达式生成一条WRITE语句，最后输出换行
+func (g *MIRGenerator) generateWriteStatement(stmt hir.WriteStatement) []Statement {
+	var stmtSeq []Statement
+	for i, exp := range stmt.Exps {
+		// 解析表达式语句和表达式值的结果变量
+		expStmtSeq, expResultID := g.generateExp(exp)
+		stmtSeq = append(stmtSeq, expStmtSeq...)
+		stmtSeq = append(stmtSeq, *NewStatement(WRITE, StrParam(stmt.Types[i].String()), StrParam(hir.VarToStr(expResultID)), StrParam("_"), fmt.Sprintf("write %s %s", stmt.Types[i], hir.VarToStr(expResultID))))
+	}
+	stmtSeq = append(stmtSeq, *NewStatement(WRITE, StrParam(hir.Type(hir.TString).String()), StringParam("\n"), StrParam("_"), "write newline"))
 	return stmtSeq
 }
 
 // genraateBreakStatement 生成break语句
 func (g *MIRGenerator) generateBreakStatement(stmt hir.BreakStatement) []Statement {
 	var stmtSeq []Statement
-	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam("_"), "_T_BREAK"))
+	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam("_T_BREAK"), "_T_BREAK"))
 	return stmtSeq
 }
 
 // generateContinueStatement 生成continue语句
 func (g *MIRGenerator) generateContinueStatement(stmt hir.ContinueStatement) []Statement {
 	var stmtSeq []Statement
-	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam("_"), "_T_CONTINUE"))
+	stmtSeq = append(stmtSeq, *NewStatement(JMP, StrParam("_"), StrParam("_"), StrParam("_T_CONTINUE"), "_T_CONTINUE"))
 	return stmtSeq
 }
@@ -30,5 +30,6 @@ func (s *Stack[T]) Top() T {
 func (s *Stack[T]) Pop() T {
 	e := s.Top()
 	s.Size--
+	s.Elems = s.Elems[:s.Size]
 	return e
 }
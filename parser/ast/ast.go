@@ -9,6 +9,8 @@ import (
 const (
 	PROGRAM = iota
 	METHOD
+	GLOBALDECLARATION
+	STRUCTDECLARATION
 	RESULTTYPE
 	IDTYPE
 	PARAMLIST
@@ -17,26 +19,30 @@ const (
 	STATEMENT
 	CONDITIONALSTATEMENT
 	LOOPSTATEMENT
+	FORSTATEMENT
+	DOWHILESTATEMENT
+	SWITCHSTATEMENT
 	CALLSTATEMENT
 	ASSIGNMENTSTATEMENT
 	RETURNSTATEMENT
 	BREAKSTATEMENT
 	CONTINUESTATEMENT
+	READSTATEMENT
+	WRITESTATEMENT
 	LOCALVARIABLEDECLARATION
 	ACTPARAMLIST
 	EXP
-	CONDITIONALEXP
-	TERM
+	UNARYEXP
+	BINARYEXP
 	FACTOR
-	RELATIONEXP
-	COMPEXP
-	CMPOP
 )
 
 // TypeString 结点类型对应的字符串
 var TypeString = map[uint]string{
 	PROGRAM:                  "Program",
 	METHOD:                   "Methods",
+	GLOBALDECLARATION:        "GlobalDeclaration",
+	STRUCTDECLARATION:        "StructDeclaration",
 	RESULTTYPE:               "ResultType",
 	IDTYPE:                   "ID",
 	PARAMLIST:                "ParamList",
@@ -45,28 +51,49 @@ var TypeString = map[uint]string{
 	STATEMENT:                "Statement",
 	CONDITIONALSTATEMENT:     "ConditionalStatement",
 	LOOPSTATEMENT:            "LoopStatement",
+	FORSTATEMENT:             "ForStatement",
+	DOWHILESTATEMENT:         "DoWhileStatement",
+	SWITCHSTATEMENT:          "SwitchStatement",
 	CALLSTATEMENT:            "CallStatement",
 	ASSIGNMENTSTATEMENT:      "AssignStatement",
 	RETURNSTATEMENT:          "ReturnStatement",
 	BREAKSTATEMENT:           "BreakStatement",
 	CONTINUESTATEMENT:        "ContinueStatement",
+	READSTATEMENT:            "ReadStatement",
+	WRITESTATEMENT:           "WriteStatement",
 	LOCALVARIABLEDECLARATION: "LocalVariableDeclaration",
 	ACTPARAMLIST:             "ActParamList",
 	EXP:                      "Exp",
-	CONDITIONALEXP:           "ConditionalExp",
-	TERM:                     "Term",
+	UNARYEXP:                 "UnaryExp",
+	BINARYEXP:                "BinaryExp",
 	FACTOR:                   "Factor",
-	RELATIONEXP:              "RelationExp",
-	COMPEXP:                  "CompExp",
-	CMPOP:                    "CmpOP",
 }
 
 // Program AST根结点
-// Program→ Method { Method }
+// Program→ { Method | GlobalDeclaration | StructDeclaration }
 type Program struct {
+	Struct []StructDeclaration `json:",omitempty"`
+	Global []GlobalDeclaration `json:",omitempty"`
 	Method []Method
 }
 
+// StructDeclaration 结构体声明，字段声明与局部变量声明形式相同
+// StructDeclaration→ 'struct' ID '{' { LocalVariableDeclaration } '}'
+type StructDeclaration struct {
+	Struct lexer.Token
+	ID     ID
+	LBrace lexer.Token
+	Fields []LocalVariableDeclaration
+	RBrace lexer.Token
+}
+
+// GlobalDeclaration 全局变量、常量声明，常量的每个变量都必须有初始值
+// GlobalDeclaration→ [ 'const' ] LocalVariableDeclaration
+type GlobalDeclaration struct {
+	Const       *lexer.Token `json:",omitempty"`
+	Declaration *LocalVariableDeclaration
+}
+
 // Method 方法结点
 // Method→ ResultType  ID  '('  ParamList  ')'  Block
 type Method struct {
@@ -76,6 +103,7 @@ type Method struct {
 	ParamList  ParamList
 	RParen     lexer.Token
 	Block      Block
+	Doc        string `json:",omitempty"` // 文档注释
 }
 
 // ResultType 方法返回值类型
@@ -90,23 +118,25 @@ type ParamListRest struct {
 	Comma lexer.Token
 	Type  Type
 	ID    ID
+	Dim   *ArrayDim `json:",omitempty"`
 }
 
 // ParamListField 参数列表不为空的必选部分
 type ParamListField struct {
 	Type          Type
 	ID            ID
+	Dim           *ArrayDim `json:",omitempty"`
 	ParamListRest *[]ParamListRest
 }
 
-// ParamList 参数列表
-// ParamList→ Type ID { ',' Type ID } | ε
+// ParamList 参数列表，数组参数按引用传递
+// ParamList→ Type ID [ ArrayDim ] { ',' Type ID [ ArrayDim ] } | ε
 type ParamList struct {
 	ParamList *ParamListField
 }
 
-// Type 变量类型
-// Type→ 'integer' | 'float' | 'char' | 'string'
+// Type 变量类型，结构体类型为结构体名
+// Type→ 'integer' | 'float' | 'char' | 'string' | ID
 type Type lexer.Token
 
 // Block 语句块
@@ -121,11 +151,16 @@ type Block struct {
 // Statement→ ConditionalStatement
 //
 //				  | LoopStatement
+//				  | ForStatement
+//				  | DoWhileStatement
+//				  | SwitchStatement
 //	              | CallStatement
 //	 		      | AssignmentStatement
 //				  | ReturnStatement
 //				  | BreakStatement
 //				  | ContinueStatement
+//				  | ReadStatement
+//				  | WriteStatement
 //				  | LocalVariableDeclaration
 //				  | Block
 //				  | ';'
@@ -136,17 +171,52 @@ type Statement struct {
 	Statement any
 }
 
+// ArrayDim 数组的长度声明
+// ArrayDim→ '[' INTC ']'
+type ArrayDim struct {
+	LBracket lexer.Token
+	Len      lexer.Token
+	RBracket lexer.Token
+}
+
+// Index 数组下标
+// Index→ '[' Exp ']'
+type Index struct {
+	LBracket lexer.Token
+	Exp      Exp
+	RBracket lexer.Token
+}
+
+// Selector 结构体字段
+// Selector→ '.' ID
+type Selector struct {
+	Dot   lexer.Token
+	Field ID
+}
+
+// Initializer 变量的初始值
+// Initializer→ '=' Exp
+type Initializer struct {
+	Assign lexer.Token
+	Exp    Exp
+}
+
 // LocalVariableDeclarationRest 局部变量声明可选部分
 type LocalVariableDeclarationRest struct {
-	Comma lexer.Token
-	ID    ID
+	Comma       lexer.Token
+	ID          ID
+	Dim         *ArrayDim
+	Initializer *Initializer
 }
 
 // LocalVariableDeclaration 局部变量声明
-// LocalVariableDeclaration→Type ID { ',' ID } ';'
+// LocalVariableDeclaration→Type Declarator { ',' Declarator } ';'
+// Declarator→ ID [ ArrayDim ] [ Initializer ]
 type LocalVariableDeclaration struct {
 	Type                         Type
 	ID                           ID
+	Dim                          *ArrayDim
+	Initializer                  *Initializer
 	LocalVariableDeclarationRest *[]LocalVariableDeclarationRest
 	Semicolon                    lexer.Token
 }
@@ -181,34 +251,93 @@ type ActParamList struct {
 }
 
 // AssignmentStatement 赋值语句
-// AssignmentStatement→ ID '=' Exp ';'
+// AssignmentStatement→ ID [ Index | Selector { Selector } ] '=' Exp ';'
 type AssignmentStatement struct {
 	ID        ID
+	Index     *Index     `json:",omitempty"`
+	Selectors []Selector `json:",omitempty"`
 	Assign    lexer.Token
 	Exp       Exp
 	Semicolon lexer.Token
 }
 
 // ConditionalStatement 条件语句
-// ConditionalStatement→'if' '(' ConditionalExp ')' Statement [ 'else' Statement ]
+// ConditionalStatement→'if' '(' Exp ')' Statement [ 'else' Statement ]
 type ConditionalStatement struct {
-	If             lexer.Token
-	LParen         lexer.Token
-	ConditionalExp ConditionalExp
-	RParen         lexer.Token
-	Statement      Statement
-	Else           *lexer.Token
-	ElseStatement  *Statement
+	If            lexer.Token
+	LParen        lexer.Token
+	Condition     Exp
+	RParen        lexer.Token
+	Statement     Statement
+	Else          *lexer.Token
+	ElseStatement *Statement
 }
 
 // LoopStatement 循环语句
-// LoopStatement→'while' '(' ConditionalExp ')' Statement
+// LoopStatement→'while' '(' Exp ')' Statement
 type LoopStatement struct {
-	While          lexer.Token
-	LParen         lexer.Token
-	ConditionalExp ConditionalExp
-	RParen         lexer.Token
-	Statement      Statement
+	While     lexer.Token
+	LParen    lexer.Token
+	Condition Exp
+	RParen    lexer.Token
+	Statement Statement
+}
+
+// ForStep for循环的步进语句
+// ForStep→ ID [ Index | Selector { Selector } ] '=' Exp
+type ForStep struct {
+	ID        ID
+	Index     *Index     `json:",omitempty"`
+	Selectors []Selector `json:",omitempty"`
+	Assign    lexer.Token
+	Exp       Exp
+}
+
+// ForStatement for循环语句
+// ForStatement→'for' '(' ForInit [ Exp ] ';' [ ForStep ] ')' Statement
+// ForInit→ LocalVariableDeclaration | AssignmentStatement | ';'
+type ForStatement struct {
+	For       lexer.Token
+	LParen    lexer.Token
+	Init      Statement // 初始化语句，包括之后的分号，为空语句时Statement为nil
+	Condition *Exp      // 循环条件，为空时一直循环
+	Semicolon lexer.Token
+	Step      *ForStep
+	RParen    lexer.Token
+	Statement Statement
+}
+
+// DoWhileStatement do-while循环语句
+// DoWhileStatement→'do' Statement 'while' '(' Exp ')' ';'
+type DoWhileStatement struct {
+	Do        lexer.Token
+	Statement Statement
+	While     lexer.Token
+	LParen    lexer.Token
+	Condition Exp
+	RParen    lexer.Token
+	Semicolon lexer.Token
+}
+
+// SwitchStatement switch语句，case分支之间贯穿执行，break跳出switch
+// SwitchStatement→'switch' '(' Exp ')' '{' { SwitchCase } '}'
+type SwitchStatement struct {
+	Switch lexer.Token
+	LParen lexer.Token
+	Exp    Exp
+	RParen lexer.Token
+	LBrace lexer.Token
+	Cases  []SwitchCase
+	RBrace lexer.Token
+}
+
+// SwitchCase switch语句的case或default分支，default分支的Value为nil
+// SwitchCase→ ( 'case' ( INTC | CHARC ) | 'default' ) ':' { Statement }
+type SwitchCase struct {
+	Case       lexer.Token
+	Value      *lexer.Token `json:",omitempty"`
+	Colon      lexer.Token
+	Statements []Statement
 }
 
 // ReturnStatement 返回语句
@@ -233,30 +362,56 @@ type ContinueStatement struct {
 	Semicolon lexer.Token
 }
 
-// ExpRest 表达式可选部分
-type ExpRest struct {
-	PlusOrMinus lexer.Token
-	Term        Term
+// ReadStatement 输入语句，按变量的声明类型从标准输入读取以空白分隔的值
+// ReadStatement→ 'read' '(' ID { ',' ID } ')' ';'
+type ReadStatement struct {
+	Read      lexer.Token
+	LParen    lexer.Token
+	ID        ID
+	ReadRest  *[]ReadRest
+	RParen    lexer.Token
+	Semicolon lexer.Token
+}
+
+// ReadRest 输入语句变量列表的可选部分
+type ReadRest struct {
+	Comma lexer.Token
+	ID    ID
+}
+
+// WriteStatement 输出语句，依次输出各表达式的值并换行
+// WriteStatement→ 'write' '(' ActParamList ')' ';'
+type WriteStatement struct {
+	Write        lexer.Token
+	LParen       lexer.Token
+	ActParamList ActParamList
+	RParen       lexer.Token
+	Semicolon    lexer.Token
 }
 
 // Exp 表达式
-// Exp→ Term  { '+' | '-'  Term }
+// Exp→ Factor | UnaryExp | BinaryExp
+// 运算符的优先级及结合性由语法分析时的运算符表决定，AST中只保留运算的结构
+// Exp的类型约束在创建AST时进行
 type Exp struct {
-	Term    Term
-	ExpRest *ExpRest
+	Exp any
 }
 
-// TermRest 单项可选部分
-type TermRest struct {
-	MulOrDiv lexer.Token
-	Factor   Factor
+// UnaryExp 一元表达式
+// UnaryExp→ UnaryOp Exp
+// UnaryOp→ '-' | 'not'
+type UnaryExp struct {
+	Op  lexer.Token
+	Exp *Exp
 }
 
-// Term 单项
-// Term→ Factor { '*' | '/'  Factor }
-type Term struct {
-	Factor   Factor
-	TermRest *TermRest
+// BinaryExp 二元表达式
+// BinaryExp→ Exp BinaryOp Exp
+// BinaryOp→ 'or' | 'and' | '<' | '<=' | '>' | '>=' | '==' | '<>' | '+' | '-' | '*' | '/' | '%'
+type BinaryExp struct {
+	LExp *Exp
+	Op   lexer.Token
+	RExp *Exp
 }
 
 // FactorTuple 括号表达式
@@ -266,59 +421,41 @@ type FactorTuple struct {
 	RParen lexer.Token
 }
 
-// Factor 单因子
-// Factor→ ID | INTC | DECI | '(' Exp ')'
-// Factor的类型约束在创建AST时进行
-type Factor struct {
-	Factor any
-}
-
-// ConditionalExpRest 条件表达式可选部分
-type ConditionalExpRest struct {
-	Or          lexer.Token
-	RelationExp RelationExp
-}
-
-// ConditionalExp 条件表达式
-// ConditionalExp→RelationExp { 'or' RelationExp }
-type ConditionalExp struct {
-	RelationExp        RelationExp
-	ConditionalExpRest *ConditionalExpRest
+// CallTuple 方法调用表达式
+type CallTuple struct {
+	ID           ID
+	LParen       lexer.Token
+	ActParamList ActParamList
+	RParen       lexer.Token
 }
 
-// RelationExpRest 关系表达式可选部分
-type RelationExpRest struct {
-	And     lexer.Token
-	CompExp CompExp
+// IndexTuple 数组元素
+type IndexTuple struct {
+	ID    ID
+	Index Index
 }
 
-// RelationExp 关系表达式
-// RelationExp→ CompExp { 'and' CompExp }
-type RelationExp struct {
-	CompExp         CompExp
-	RelationExpRest *RelationExpRest
+// FieldTuple 结构体字段
+type FieldTuple struct {
+	ID        ID
+	Selectors []Selector
 }
 
-// CompExp 比较表达式
-// CompExp→ Exp CmpOp Exp
-type CompExp struct {
-	LExp  Exp
-	CmpOp CmpOp
-	RExp  Exp
+// Factor 单因子
+// Factor→ ID | INTC | DECI | STRC | CHARC | '(' Exp ')' | ID '(' ActParamList ')' | ID Index | ID Selector { Selector }
+// Factor的类型约束在创建AST时进行
+type Factor struct {
+	Factor any
 }
 
-// CmpOp 比较运算符
-// CmpOp→'<' | '<=' | '>' | '>=| '==' | '<>'
-type CmpOp lexer.Token
-
 // IsResultType 判断是否为返回值类型
 func IsResultType(token lexer.Token) bool {
-	return token.Type == lexer.INT || token.Type == lexer.FLOAT || token.Type == lexer.CHR || token.Type == lexer.STRING || token.Type == lexer.VOID
+	return token.Type == lexer.INT || token.Type == lexer.FLOAT || token.Type == lexer.CHAR || token.Type == lexer.STRING || token.Type == lexer.VOID
 }
 
 // IsType 判断是否为变量类型
 func IsType(token lexer.Token) bool {
-	return token.Type == lexer.INT || token.Type == lexer.FLOAT || token.Type == lexer.CHR || token.Type == lexer.STRING
+	return token.Type == lexer.INT || token.Type == lexer.FLOAT || token.Type == lexer.CHAR || token.Type == lexer.STRING
 }
 
 // IsID 判断是否为标识符
@@ -329,7 +466,55 @@ func IsID(token lexer.Token) bool {
 // NewProgram 创建Program
 func NewProgram() (Program, error) {
 	return Program{
-		Method: make([]Method, 0), // 初始化Method数组
+		Struct: make([]StructDeclaration, 0), // 初始化Struct数组
+		Global: make([]GlobalDeclaration, 0), // 初始化Global数组
+		Method: make([]Method, 0),            // 初始化Method数组
+	}, nil
+}
+
+// NewGlobalDeclaration 创建全局变量、常量声明
+// constToken: const关键字，声明变量时为nil
+// declaration: 变量声明
+func NewGlobalDeclaration(constToken *lexer.Token, declaration *LocalVariableDeclaration) (GlobalDeclaration, error) {
+	// 检查参数是否合法
+	if constToken != nil && constToken.Type != lexer.CONST {
+		return GlobalDeclaration{}, errors.New("GlobalDeclaration: invalid const token")
+	}
+	if declaration == nil {
+		return GlobalDeclaration{}, errors.New("GlobalDeclaration: invalid declaration")
+	}
+	return GlobalDeclaration{
+		Const:       constToken,
+		Declaration: declaration,
+	}, nil
+}
+
+// NewStructDeclaration 创建结构体声明
+// structToken: struct
+// idToken: 结构体名
+// lBrace: 左大括号
+// fields: 字段声明
+// rBrace: 右大括号
+func NewStructDeclaration(structToken lexer.Token, idToken ID, lBrace lexer.Token, fields []LocalVariableDeclaration, rBrace lexer.Token) (StructDeclaration, error) {
+	// 检查struct、结构体名、大括号是否合法
+	if structToken.Type != lexer.STRUCT {
+		return StructDeclaration{}, errors.New("StructDeclaration: invalid struct token")
+	}
+	if idToken.Type != lexer.IDENTIFIER {
+		return StructDeclaration{}, errors.New("StructDeclaration: invalid id token")
+	}
+	if lBrace.Type != lexer.LBRACE {
+		return StructDeclaration{}, errors.New("StructDeclaration: invalid lBrace token")
+	}
+	if rBrace.Type != lexer.RBRACE {
+		return StructDeclaration{}, errors.New("StructDeclaration: invalid rBrace token")
+	}
+	return StructDeclaration{
+		Struct: structToken,
+		ID:     idToken,
+		LBrace: lBrace,
+		Fields: fields,
+		RBrace: rBrace,
 	}, nil
 }
 
@@ -365,7 +550,7 @@ func NewMethod(resultType ResultType, idToken ID, lParen lexer.Token, paramList
 // NewResultType 创建返回值类型
 func NewResultType(typeToken lexer.Token) (ResultType, error) {
 	switch typeToken.Type {
-	case lexer.INT, lexer.FLOAT, lexer.CHR, lexer.STRING, lexer.VOID:
+	case lexer.INT, lexer.FLOAT, lexer.CHAR, lexer.STRING, lexer.VOID:
 		// 检查参数是否合法
 		return ResultType(typeToken), nil
 	default:
@@ -374,96 +559,91 @@ func NewResultType(typeToken lexer.Token) (ResultType, error) {
 }
 
 // NewParamList 创建参数列表
-// param: 不定长参数列表
+// param: 不定长参数列表，Type ID [*ArrayDim] { 逗号 Type ID [*ArrayDim] }
 func NewParamList(param ...any) (ParamList, error) {
 	// 没有参数
 	if len(param) == 0 {
 		return ParamList{}, nil
 	}
-	// 需要Type ID对，返回错误
+	// 第一个参数必须为Type ID对
 	if len(param) == 1 {
 		return ParamList{}, errors.New("ParamList: expect Type ID pair")
 	}
-	// 只有一个Type ID对
-	if len(param) == 2 {
-		switch param[0].(type) {
-		// 第一个参数为Type
-		case Type:
-			switch param[1].(type) {
-			// 第二个参数为ID
-			case ID:
-				return ParamList{
-					ParamList: &ParamListField{
-						Type: param[0].(Type),
-						ID:   param[1].(ID),
-					},
-				}, nil
-			default:
-				return ParamList{}, errors.New("ParamList: expect Type ID pair")
-			}
-		default:
-			return ParamList{}, errors.New("ParamList: expect Type ID pair")
+	typ, ok := param[0].(Type)
+	if !ok {
+		return ParamList{}, errors.New("ParamList: expect Type ID pair")
+	}
+	id, ok := param[1].(ID)
+	if !ok {
+		return ParamList{}, errors.New("ParamList: expect Type ID pair")
+	}
+	field := ParamListField{
+		Type: typ,
+		ID:   id,
+	}
+	index := 2
+	// 数组参数的长度（可选）
+	if index < len(param) {
+		if dim, isDim := param[index].(*ArrayDim); isDim {
+			field.Dim = dim
+			index++
 		}
 	}
-	// 有多个Type ID对
-	if (len(param)-2)%3 == 0 {
-		// 使用ParamListRest存储除第一个外的Type ID对
-		paramListRest := make([]ParamListRest, 0)
-		// 剩下的Type ID对
-		paramRest := param[2:]
-		// 用于存储单个Type ID对
-		var paramListRestSingle ParamListRest
-		// 计数存储的Type ID对个数
-		restCounter := 0
-		// 遍历剩下的Type ID对
-		for index, elem := range paramRest {
-			switch elem.(type) {
-			// 第k个参数为逗号
-			case lexer.Token:
-				if index%3 == 0 && elem.(lexer.Token).Type == lexer.COMMA {
-					paramListRestSingle.Comma = elem.(lexer.Token)
-				} else {
-					return ParamList{}, errors.New("ParamList: expect comma")
-				}
-			// 第k+1个参数为Type
-			case Type:
-				if index%3 == 1 {
-					paramListRestSingle.Type = elem.(Type)
-				} else {
-					return ParamList{}, errors.New("ParamList: expect Type")
-				}
-			// 第k+2个参数为ID
-			case ID:
-				if index%3 == 2 {
-					paramListRestSingle.ID = elem.(ID)
-					// 将单个Type ID对存入paramListRest
-					paramListRest = append(paramListRest, paramListRestSingle)
-					restCounter++
-				} else {
-					return ParamList{}, errors.New("ParamList: expect ID")
-				}
-			}
+	// 只有一个Type ID对
+	if index == len(param) {
+		return ParamList{
+			ParamList: &field,
+		}, nil
+	}
+
+	// 有多个Type ID对，使用ParamListRest存储除第一个外的Type ID对
+	paramListRest := make([]ParamListRest, 0)
+	for index < len(param) {
+		// 第k个参数为逗号
+		comma, ok := param[index].(lexer.Token)
+		if !ok || comma.Type != lexer.COMMA {
+			return ParamList{}, errors.New("ParamList: expect comma")
 		}
-		// 检查存储的Type ID对个数是否正确
-		if restCounter != (len(param)-2)/3 {
+		if index+2 >= len(param) {
 			return ParamList{}, errors.New("ParamList: expect Type ID pair")
 		}
-		return ParamList{
-			ParamList: &ParamListField{
-				Type:          (param[0]).(Type),
-				ID:            (param[1]).(ID),
-				ParamListRest: &paramListRest,
-			},
-		}, nil
-	} else {
-		return ParamList{}, errors.New("ParamList: expect Type ID pair")
+		// 第k+1个参数为Type
+		typ, ok := param[index+1].(Type)
+		if !ok {
+			return ParamList{}, errors.New("ParamList: expect Type")
+		}
+		// 第k+2个参数为ID
+		id, ok := param[index+2].(ID)
+		if !ok {
+			return ParamList{}, errors.New("ParamList: expect ID")
+		}
+		paramListRestSingle := ParamListRest{
+			Comma: comma,
+			Type:  typ,
+			ID:    id,
+		}
+		index += 3
+		// 第k+3个参数为数组长度（可选）
+		if index < len(param) {
+			if dim, isDim := param[index].(*ArrayDim); isDim {
+				paramListRestSingle.Dim = dim
+				index++
+			}
+		}
+		// 将单个Type ID对存入paramListRest
+		paramListRest = append(paramListRest, paramListRestSingle)
 	}
+	field.ParamListRest = &paramListRest
+
+	return ParamList{
+		ParamList: &field,
+	}, nil
 }
 
-// NewType 创建类型
+// NewType 创建类型，标识符为结构体类型
 func NewType(typeToken lexer.Token) (Type, error) {
 	switch typeToken.Type {
-	case lexer.INT, lexer.FLOAT, lexer.CHR, lexer.STRING:
+	case lexer.INT, lexer.FLOAT, lexer.CHAR, lexer.STRING, lexer.IDENTIFIER:
 		return Type(typeToken), nil
 	default:
 		return Type{}, errors.New("Type: invalid type")
@@ -497,12 +677,82 @@ func NewBlock(lBrace lexer.Token, statements []Statement, rBrace lexer.Token) (B
 	}, nil
 }
 
+// NewArrayDim 创建数组的长度声明
+// lBracket: 左方括号
+// length: 数组长度，整数字面量
+// rBracket: 右方括号
+func NewArrayDim(lBracket, length, rBracket lexer.Token) (ArrayDim, error) {
+	if lBracket.Type != lexer.LBRACKET {
+		return ArrayDim{}, errors.New("ArrayDim: invalid lBracket token")
+	}
+	if length.Type != lexer.INTEGER_LITERAL {
+		return ArrayDim{}, errors.New("ArrayDim: invalid length token")
+	}
+	if rBracket.Type != lexer.RBRACKET {
+		return ArrayDim{}, errors.New("ArrayDim: invalid rBracket token")
+	}
+	return ArrayDim{
+		LBracket: lBracket,
+		Len:      length,
+		RBracket: rBracket,
+	}, nil
+}
+
+// NewIndex 创建数组下标
+// lBracket: 左方括号
+// exp: 下标表达式
+// rBracket: 右方括号
+func NewIndex(lBracket lexer.Token, exp Exp, rBracket lexer.Token) (Index, error) {
+	if lBracket.Type != lexer.LBRACKET {
+		return Index{}, errors.New("Index: invalid lBracket token")
+	}
+	if rBracket.Type != lexer.RBRACKET {
+		return Index{}, errors.New("Index: invalid rBracket token")
+	}
+	return Index{
+		LBracket: lBracket,
+		Exp:      exp,
+		RBracket: rBracket,
+	}, nil
+}
+
+// NewSelector 创建结构体字段
+// dotToken: .
+// field: 字段名
+func NewSelector(dotToken lexer.Token, field ID) (Selector, error) {
+	if dotToken.Type != lexer.DOT {
+		return Selector{}, errors.New("Selector: invalid dot token")
+	}
+	if field.Type != lexer.IDENTIFIER {
+		return Selector{}, errors.New("Selector: invalid field token")
+	}
+	return Selector{
+		Dot:   dotToken,
+		Field: field,
+	}, nil
+}
+
+// NewInitializer 创建变量的初始值
+// assignToken: 等号
+// exp: 初始值表达式
+func NewInitializer(assignToken lexer.Token, exp Exp) (Initializer, error) {
+	if assignToken.Type != lexer.ASSIGN {
+		return Initializer{}, errors.New("Initializer: invalid assign token")
+	}
+	return Initializer{
+		Assign: assignToken,
+		Exp:    exp,
+	}, nil
+}
+
 // NewLocalVariableDeclarationStatement  创建局部变量声明
 // typeToken: 类型
 // idToken: 标识符
-// localVariableDeclarationRest: 局部变量声明的后续部分
+// dim: 数组长度（可选）
+// initializer: 标识符的初始值（可选）
+// localVariableDeclarationRest: 局部变量声明的后续部分，逗号 + 标识符 + 数组长度（可选） + 初始值（可选）
 // semicolonToken: 分号
-func NewLocalVariableDeclarationStatement(typeToken Type, idToken ID, localVariableDeclarationRest []any, semicolonToken lexer.Token) (LocalVariableDeclaration, error) {
+func NewLocalVariableDeclarationStatement(typeToken Type, idToken ID, dim *ArrayDim, initializer *Initializer, localVariableDeclarationRest []any, semicolonToken lexer.Token) (LocalVariableDeclaration, error) {
 	// 检查类型、分号是否合法
 	if idToken.Type != lexer.IDENTIFIER {
 		return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid id token")
@@ -511,60 +761,65 @@ func NewLocalVariableDeclarationStatement(typeToken Type, idToken ID, localVaria
 		return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid semicolon token")
 	}
 
-	// 检查局部变量声明的后续部分是否合法
-	// 逗号 +  标识符 必须成对出现
-	if len(localVariableDeclarationRest)%2 != 0 {
-		return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid number of tokens")
-	}
-
 	// 没有后续部分
 	if len(localVariableDeclarationRest) == 0 {
 		return LocalVariableDeclaration{
-			Type:      typeToken,
-			ID:        idToken,
-			Semicolon: semicolonToken,
+			Type:        typeToken,
+			ID:          idToken,
+			Dim:         dim,
+			Initializer: initializer,
+			Semicolon:   semicolonToken,
 		}, nil
 	}
 
 	// 有后续部分 用rest保存后续部分
 	rest := make([]LocalVariableDeclarationRest, 0)
-
-	// 用于存储单个逗号 + 标识符
-	var restSingle LocalVariableDeclarationRest
-	restCounter := 0
-	// 遍历后续部分
-	for index, elem := range localVariableDeclarationRest {
-		// 清空restSingle
-		if index%2 == 0 {
-			restSingle = LocalVariableDeclarationRest{}
+	// 检查局部变量声明的后续部分是否合法
+	// 逗号 + 标识符 必须成对出现，之后可以有数组长度、初始值
+	for index := 0; index < len(localVariableDeclarationRest); {
+		// 第k个元素为逗号
+		comma, ok := localVariableDeclarationRest[index].(lexer.Token)
+		if !ok || comma.Type != lexer.COMMA {
+			return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid comma token")
 		}
-
-		switch elem.(type) {
-		case lexer.Token:
-			// 第k个元素为逗号
-			if index%2 != 0 {
-				return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid comma token")
-			} else {
-				restSingle.Comma = elem.(lexer.Token)
+		// 第k+1个元素为标识符
+		if index+1 >= len(localVariableDeclarationRest) {
+			return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid number of tokens")
+		}
+		id, ok := localVariableDeclarationRest[index+1].(ID)
+		if !ok || id.Type != lexer.IDENTIFIER {
+			return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid id token")
+		}
+		restSingle := LocalVariableDeclarationRest{
+			Comma: comma,
+			ID:    id,
+		}
+		index += 2
+		// 第k+2个元素为数组长度（可选）
+		if index < len(localVariableDeclarationRest) {
+			if dim, isDim := localVariableDeclarationRest[index].(*ArrayDim); isDim {
+				restSingle.Dim = dim
+				index++
 			}
-		case ID:
-			// 第k+1个元素为标识符
-			if index%2 == 0 {
-				return LocalVariableDeclaration{}, errors.New("LocalVariableDeclaration: invalid id token")
-			} else {
-				restSingle.ID = elem.(ID)
-				// 将单个逗号 + 标识符对存入rest
-				rest = append(rest, restSingle)
-				restCounter++
+		}
+		// 之后为初始值（可选）
+		if index < len(localVariableDeclarationRest) {
+			if init, isInit := localVariableDeclarationRest[index].(*Initializer); isInit {
+				restSingle.Initializer = init
+				index++
 			}
-
 		}
+		// 将单个逗号 + 标识符 + 数组长度 + 初始值存入rest
+		rest = append(rest, restSingle)
 	}
 
 	return LocalVariableDeclaration{
 		Type:                         typeToken,
 		ID:                           idToken,
+		Dim:                          dim,
+		Initializer:                  initializer,
 		LocalVariableDeclarationRest: &rest,
+		Semicolon:                    semicolonToken,
 	}, nil
 }
 
@@ -656,14 +911,19 @@ func NewActParamList(token []any) (ActParamList, error) {
 
 // NewAssignmentStatement 创建赋值语句
 // idToken: 标识符
+// index: 数组下标（可选）
+// selectors: 结构体字段（可选），不能与数组下标同时存在
 // equalToken: 等号
 // exp: 表达式
 // semicolonToken: 分号
-func NewAssignmentStatement(idToken ID, equalToken lexer.Token, exp Exp, semicolonToken lexer.Token) (AssignmentStatement, error) {
+func NewAssignmentStatement(idToken ID, index *Index, selectors []Selector, equalToken lexer.Token, exp Exp, semicolonToken lexer.Token) (AssignmentStatement, error) {
 	// 检查标识符、等号、分号是否合法
 	if idToken.Type != lexer.IDENTIFIER {
 		return AssignmentStatement{}, errors.New("AssignmentStatement: invalid id token")
 	}
+	if index != nil && len(selectors) != 0 {
+		return AssignmentStatement{}, errors.New("AssignmentStatement: expected index or selectors, not both")
+	}
 	if equalToken.Type != lexer.ASSIGN {
 		return AssignmentStatement{}, errors.New("AssignmentStatement: invalid equal token")
 	}
@@ -673,6 +933,8 @@ func NewAssignmentStatement(idToken ID, equalToken lexer.Token, exp Exp, semicol
 
 	return AssignmentStatement{
 		ID:        idToken,
+		Index:     index,
+		Selectors: selectors,
 		Assign:    equalToken,
 		Exp:       exp,
 		Semicolon: semicolonToken,
@@ -682,12 +944,12 @@ func NewAssignmentStatement(idToken ID, equalToken lexer.Token, exp Exp, semicol
 // NewConditionalStatement 创建条件语句
 // ifToken: if
 // lParen: 左括号
-// conditionalExp: 条件表达式
+// condition: 条件表达式
 // rParen: 右括号
 // statement: 语句
 // elseToken: else（可选）
 // elseStatement: else语句（可选）
-func NewConditionalStatement(ifToken, lParen lexer.Token, conditionalExp ConditionalExp, rParen lexer.Token, statement Statement, elseToken *lexer.Token, elseStatement *Statement) (ConditionalStatement, error) {
+func NewConditionalStatement(ifToken, lParen lexer.Token, condition Exp, rParen lexer.Token, statement Statement, elseToken *lexer.Token, elseStatement *Statement) (ConditionalStatement, error) {
 	// 检查if、左右括号
 	if ifToken.Type != lexer.IF {
 		return ConditionalStatement{}, errors.New("ConditionalStatement: invalid if token")
@@ -709,23 +971,23 @@ func NewConditionalStatement(ifToken, lParen lexer.Token, conditionalExp Conditi
 	}
 
 	return ConditionalStatement{
-		If:             ifToken,
-		LParen:         lParen,
-		ConditionalExp: conditionalExp,
-		RParen:         rParen,
-		Statement:      statement,
-		Else:           elseToken,
-		ElseStatement:  elseStatement,
+		If:            ifToken,
+		LParen:        lParen,
+		Condition:     condition,
+		RParen:        rParen,
+		Statement:     statement,
+		Else:          elseToken,
+		ElseStatement: elseStatement,
 	}, nil
 }
 
 // NewLoopStatement 创建循环语句
 // whileToken: while
 // lParen: 左括号
-// conditionalExp: 条件表达式
+// condition: 条件表达式
 // rParen: 右括号
 // statement: 语句
-func NewLoopStatement(whileToken, lParen lexer.Token, conditionalExp ConditionalExp, rParen lexer.Token, statement Statement) (LoopStatement, error) {
+func NewLoopStatement(whileToken, lParen lexer.Token, condition Exp, rParen lexer.Token, statement Statement) (LoopStatement, error) {
 	// 检查while、左右括号
 	if whileToken.Type != lexer.WHILE {
 		return LoopStatement{}, errors.New("LoopStatement: invalid while token")
@@ -737,22 +999,180 @@ func NewLoopStatement(whileToken, lParen lexer.Token, conditionalExp Conditional
 		return LoopStatement{}, errors.New("LoopStatement: invalid rParen token")
 	}
 	return LoopStatement{
-		While:          whileToken,
-		LParen:         lParen,
-		ConditionalExp: conditionalExp,
-		RParen:         rParen,
-		Statement:      statement,
+		While:     whileToken,
+		LParen:    lParen,
+		Condition: condition,
+		RParen:    rParen,
+		Statement: statement,
 	}, nil
 }
 
-// NewCmpOp 创建比较运算符
-func NewCmpOp(cmpOpToken lexer.Token) (CmpOp, error) {
-	switch cmpOpToken.Type {
-	case lexer.LESS, lexer.LESSEQUAL, lexer.GREATER, lexer.GREATEREQUAL, lexer.EQUAL, lexer.DIAMOND:
-		return CmpOp(cmpOpToken), nil
+// NewForStep 创建for循环的步进语句
+// idToken: 标识符
+// index: 数组下标（可选）
+// selectors: 结构体字段（可选），不能与数组下标同时存在
+// assignToken: 等号
+// exp: 表达式
+func NewForStep(idToken ID, index *Index, selectors []Selector, assignToken lexer.Token, exp Exp) (ForStep, error) {
+	if idToken.Type != lexer.IDENTIFIER {
+		return ForStep{}, errors.New("ForStep: invalid id token")
+	}
+	if index != nil && len(selectors) != 0 {
+		return ForStep{}, errors.New("ForStep: expected index or selectors, not both")
+	}
+	if assignToken.Type != lexer.ASSIGN {
+		return ForStep{}, errors.New("ForStep: invalid assign token")
+	}
+	return ForStep{
+		ID:        idToken,
+		Index:     index,
+		Selectors: selectors,
+		Assign:    assignToken,
+		Exp:       exp,
+	}, nil
+}
+
+// NewForStatement 创建for循环语句
+// forToken: for
+// lParen: 左括号
+// init: 初始化语句（变量声明、赋值语句或空语句）
+// condition: 循环条件（可选）
+// semicolon: 循环条件之后的分号
+// step: 步进语句（可选）
+// rParen: 右括号
+// statement: 循环体
+func NewForStatement(forToken, lParen lexer.Token, init Statement, condition *Exp, semicolon lexer.Token, step *ForStep, rParen lexer.Token, statement Statement) (ForStatement, error) {
+	// 检查for、左右括号、分号
+	if forToken.Type != lexer.FOR {
+		return ForStatement{}, errors.New("ForStatement: invalid for token")
+	}
+	if lParen.Type != lexer.LPAREN {
+		return ForStatement{}, errors.New("ForStatement: invalid lParen token")
+	}
+	if semicolon.Type != lexer.SEMICOLON {
+		return ForStatement{}, errors.New("ForStatement: invalid semicolon token")
+	}
+	if rParen.Type != lexer.RPAREN {
+		return ForStatement{}, errors.New("ForStatement: invalid rParen token")
+	}
+	// 初始化语句只能是变量声明、赋值语句或空语句
+	switch init.Statement.(type) {
+	case nil, *LocalVariableDeclaration, *AssignmentStatement:
 	default:
-		return CmpOp{}, errors.New("CmpOp: invalid type")
+		return ForStatement{}, errors.New("ForStatement: invalid init statement")
 	}
+	return ForStatement{
+		For:       forToken,
+		LParen:    lParen,
+		Init:      init,
+		Condition: condition,
+		Semicolon: semicolon,
+		Step:      step,
+		RParen:    rParen,
+		Statement: statement,
+	}, nil
+}
+
+// NewDoWhileStatement 创建do-while循环语句
+// doToken: do
+// statement: 循环体
+// whileToken: while
+// lParen: 左括号
+// condition: 循环条件
+// rParen: 右括号
+// semicolon: 分号
+func NewDoWhileStatement(doToken lexer.Token, statement Statement, whileToken, lParen lexer.Token, condition Exp, rParen, semicolon lexer.Token) (DoWhileStatement, error) {
+	// 检查do、while、左右括号、分号
+	if doToken.Type != lexer.DO {
+		return DoWhileStatement{}, errors.New("DoWhileStatement: invalid do token")
+	}
+	if whileToken.Type != lexer.WHILE {
+		return DoWhileStatement{}, errors.New("DoWhileStatement: invalid while token")
+	}
+	if lParen.Type != lexer.LPAREN {
+		return DoWhileStatement{}, errors.New("DoWhileStatement: invalid lParen token")
+	}
+	if rParen.Type != lexer.RPAREN {
+		return DoWhileStatement{}, errors.New("DoWhileStatement: invalid rParen token")
+	}
+	if semicolon.Type != lexer.SEMICOLON {
+		return DoWhileStatement{}, errors.New("DoWhileStatement: invalid semicolon token")
+	}
+	return DoWhileStatement{
+		Do:        doToken,
+		Statement: statement,
+		While:     whileToken,
+		LParen:    lParen,
+		Condition: condition,
+		RParen:    rParen,
+		Semicolon: semicolon,
+	}, nil
+}
+
+// NewSwitchStatement 创建switch语句
+// switchToken: switch
+// lParen: 左括号
+// exp: 被匹配的表达式
+// rParen: 右括号
+// lBrace: 左大括号
+// cases: case及default分支
+// rBrace: 右大括号
+func NewSwitchStatement(switchToken, lParen lexer.Token, exp Exp, rParen, lBrace lexer.Token, cases []SwitchCase, rBrace lexer.Token) (SwitchStatement, error) {
+	// 检查switch、括号、大括号
+	if switchToken.Type != lexer.SWITCH {
+		return SwitchStatement{}, errors.New("SwitchStatement: invalid switch token")
+	}
+	if lParen.Type != lexer.LPAREN {
+		return SwitchStatement{}, errors.New("SwitchStatement: invalid lParen token")
+	}
+	if rParen.Type != lexer.RPAREN {
+		return SwitchStatement{}, errors.New("SwitchStatement: invalid rParen token")
+	}
+	if lBrace.Type != lexer.LBRACE {
+		return SwitchStatement{}, errors.New("SwitchStatement: invalid lBrace token")
+	}
+	if rBrace.Type != lexer.RBRACE {
+		return SwitchStatement{}, errors.New("SwitchStatement: invalid rBrace token")
+	}
+	return SwitchStatement{
+		Switch: switchToken,
+		LParen: lParen,
+		Exp:    exp,
+		RParen: rParen,
+		LBrace: lBrace,
+		Cases:  cases,
+		RBrace: rBrace,
+	}, nil
+}
+
+// NewSwitchCase 创建switch语句的分支
+// caseToken: case或default
+// value: case的常量，default分支为nil
+// colon: 冒号
+// statements: 分支中的语句
+func NewSwitchCase(caseToken lexer.Token, value *lexer.Token, colon lexer.Token, statements []Statement) (SwitchCase, error) {
+	// case分支必须有整数或字符常量，default分支没有常量
+	switch caseToken.Type {
+	case lexer.CASE:
+		if value == nil || value.Type != lexer.INTEGER_LITERAL && value.Type != lexer.CHAR_LITERAL {
+			return SwitchCase{}, errors.New("SwitchCase: invalid case value")
+		}
+	case lexer.DEFAULT:
+		if value != nil {
+			return SwitchCase{}, errors.New("SwitchCase: default has no value")
+		}
+	default:
+		return SwitchCase{}, errors.New("SwitchCase: invalid case token")
+	}
+	if colon.Type != lexer.COLON {
+		return SwitchCase{}, errors.New("SwitchCase: invalid colon token")
+	}
+	return SwitchCase{
+		Case:       caseToken,
+		Value:      value,
+		Colon:      colon,
+		Statements: statements,
+	}, nil
 }
 
 // NewReturnStatement 创建返回语句
@@ -819,62 +1239,146 @@ func NewContinueStatement(continueToken, semicolonToken lexer.Token) (ContinueSt
 	}, nil
 }
 
-// NewExp 创建表达式
-// lTerm: 左项
-// plusOrMinus: 加减号（可选）
-// rTerm: 右项（可选）
-func NewExp(lTerm Term, plusOrMinus *lexer.Token, rTerm *Term) (Exp, error) {
-	// 加减号和右项必须同时存在或同时不存在
-	if (plusOrMinus == nil && rTerm != nil) || (plusOrMinus != nil && rTerm == nil) {
-		return Exp{}, errors.New("Exp: expected plusOrMinus and rTerm to be both nil or both not nil")
-	}
-	// 检查加减号
-	if plusOrMinus != nil && plusOrMinus.Type != lexer.PLUS && plusOrMinus.Type != lexer.MINUS {
-		return Exp{}, errors.New("Exp: invalid plusOrMinus token")
-	}
-	// 如果加减号和右项都不存在，则返回左项
-	if plusOrMinus == nil && rTerm == nil {
-		return Exp{
-			Term: lTerm,
-		}, nil
+// NewReadStatement 创建输入语句
+// readToken: read
+// lParen: 左括号
+// idToken: 第一个变量名
+// readRest: 之后的逗号 + 变量名
+// rParen: 右括号
+// semicolonToken: 分号
+func NewReadStatement(readToken, lParen lexer.Token, idToken ID, readRest []any, rParen, semicolonToken lexer.Token) (ReadStatement, error) {
+	// 检查read、左右括号、标识符、分号是否合法
+	if readToken.Type != lexer.READ {
+		return ReadStatement{}, errors.New("ReadStatement: invalid read token")
+	}
+	if lParen.Type != lexer.LPAREN {
+		return ReadStatement{}, errors.New("ReadStatement: invalid lParen token")
+	}
+	if idToken.Type != lexer.IDENTIFIER {
+		return ReadStatement{}, errors.New("ReadStatement: invalid id token")
+	}
+	if rParen.Type != lexer.RPAREN {
+		return ReadStatement{}, errors.New("ReadStatement: invalid rParen token")
+	}
+	if semicolonToken.Type != lexer.SEMICOLON {
+		return ReadStatement{}, errors.New("ReadStatement: invalid semicolon token")
+	}
+
+	// 逗号 + 变量名
+	if len(readRest)%2 != 0 {
+		return ReadStatement{}, errors.New("ReadStatement: invalid rest length")
+	}
+	rest := make([]ReadRest, 0)
+	for i := 0; i < len(readRest); i += 2 {
+		comma, ok := readRest[i].(lexer.Token)
+		if !ok || comma.Type != lexer.COMMA {
+			return ReadStatement{}, errors.New("ReadStatement: invalid comma token")
+		}
+		id, ok := readRest[i+1].(ID)
+		if !ok || id.Type != lexer.IDENTIFIER {
+			return ReadStatement{}, errors.New("ReadStatement: invalid id token")
+		}
+		rest = append(rest, ReadRest{Comma: comma, ID: id})
 	}
-	return Exp{
-		Term: lTerm,
-		ExpRest: &ExpRest{
-			PlusOrMinus: *plusOrMinus,
-			Term:        *rTerm,
-		},
+
+	return ReadStatement{
+		Read:      readToken,
+		LParen:    lParen,
+		ID:        idToken,
+		ReadRest:  &rest,
+		RParen:    rParen,
+		Semicolon: semicolonToken,
 	}, nil
 }
 
-// NewTerm 创建项
-// lFactor: 左因子
-// mulOrDiv: 乘除号（可选）
-// rFactor: 右因子（可选）
-func NewTerm(lFactor Factor, mulOrDiv *lexer.Token, rFactor *Factor) (Term, error) {
-	// 乘除号和右因子必须同时存在或同时不存在
-	if (mulOrDiv == nil && rFactor != nil) || (mulOrDiv != nil && rFactor == nil) {
-		return Term{}, errors.New("Term: expected mulOrDiv and rFactor to be both nil or both not nil")
+// NewWriteStatement 创建输出语句
+// writeToken: write
+// lParen: 左括号
+// actParamList: 输出的表达式列表
+// rParen: 右括号
+// semicolonToken: 分号
+func NewWriteStatement(writeToken, lParen lexer.Token, actParamList *ActParamList, rParen, semicolonToken lexer.Token) (WriteStatement, error) {
+	// 检查write、左右括号、分号是否合法
+	if writeToken.Type != lexer.WRITE {
+		return WriteStatement{}, errors.New("WriteStatement: invalid write token")
 	}
-	// 检查乘除号
-	if mulOrDiv != nil && mulOrDiv.Type != lexer.TIMES && mulOrDiv.Type != lexer.DIVIDE {
-		return Term{}, errors.New("Term: invalid mulOrDiv token")
+	if lParen.Type != lexer.LPAREN {
+		return WriteStatement{}, errors.New("WriteStatement: invalid lParen token")
 	}
-	// 如果乘除号和右因子都不存在，则返回左因子
-	if mulOrDiv == nil && rFactor == nil {
-		return Term{
-			Factor: lFactor,
-		}, nil
+	if rParen.Type != lexer.RPAREN {
+		return WriteStatement{}, errors.New("WriteStatement: invalid rParen token")
+	}
+	if semicolonToken.Type != lexer.SEMICOLON {
+		return WriteStatement{}, errors.New("WriteStatement: invalid semicolon token")
 	}
-	return Term{
-		Factor: lFactor,
-		TermRest: &TermRest{
-			MulOrDiv: *mulOrDiv,
-			Factor:   *rFactor,
-		},
+
+	return WriteStatement{
+		Write:        writeToken,
+		LParen:       lParen,
+		ActParamList: *actParamList,
+		RParen:       rParen,
+		Semicolon:    semicolonToken,
 	}, nil
 }
 
+// NewExp 创建表达式
+// exp: 不定长度表达式
+//   - Factor 因子
+//   - UnaryOp *Exp 一元表达式
+//   - *Exp BinaryOp *Exp 二元表达式
+func NewExp(exp ...any) (Exp, error) {
+	switch len(exp) {
+	case 1:
+		// 因子
+		factor, ok := exp[0].(Factor)
+		if !ok {
+			return Exp{}, errors.New("Exp: invalid factor")
+		}
+		return Exp{
+			Exp: factor,
+		}, nil
+	case 2:
+		// UnaryOp Exp
+		op, ok := exp[0].(lexer.Token)
+		if _, isUnary := UnaryOp[op.Type]; !ok || !isUnary {
+			return Exp{}, errors.New("Exp: invalid unary operator")
+		}
+		operand, ok := exp[1].(*Exp)
+		if !ok {
+			return Exp{}, errors.New("Exp: invalid operand")
+		}
+		return Exp{
+			Exp: UnaryExp{
+				Op:  op,
+				Exp: operand,
+			},
+		}, nil
+	case 3:
+		// Exp BinaryOp Exp
+		lExp, ok := exp[0].(*Exp)
+		if !ok {
+			return Exp{}, errors.New("Exp: invalid left operand")
+		}
+		op, ok := exp[1].(lexer.Token)
+		if _, isBinary := BinaryOp[op.Type]; !ok || !isBinary {
+			return Exp{}, errors.New("Exp: invalid binary operator")
+		}
+		rExp, ok := exp[2].(*Exp)
+		if !ok {
+			return Exp{}, errors.New("Exp: invalid right operand")
+		}
+		return Exp{
+			Exp: BinaryExp{
+				LExp: lExp,
+				Op:   op,
+				RExp: rExp,
+			},
+		}, nil
+	default:
+		return Exp{}, errors.New("Exp: invalid number of elements")
+	}
+}
+
 // NewFactor 创建因子
 // factor: 不定长度因子
 func NewFactor(factor ...any) (Factor, error) {
@@ -885,15 +1389,44 @@ func NewFactor(factor ...any) (Factor, error) {
 		// 一个因子，判断类型
 		switch factor[0].(type) {
 		case lexer.Token, ID:
-			// 如果是标识符或者token，但不是IDENTIFIER、INTEGER_LITERAL、DECIMAL_LITERAL，返回错误
-			if factor[0].(lexer.Token).Type != lexer.IDENTIFIER && factor[0].(lexer.Token).Type != lexer.INTEGER_LITERAL && factor[0].(lexer.Token).Type != lexer.DECIMAL_LITERAL {
+			// 如果是标识符或者token，但不是标识符或字面量，返回错误
+			switch factor[0].(lexer.Token).Type {
+			case lexer.IDENTIFIER, lexer.INTEGER_LITERAL, lexer.DECIMAL_LITERAL, lexer.STRING_LITERAL, lexer.CHAR_LITERAL:
+			default:
 				return Factor{}, errors.New("Factor: invalid token")
 			}
-			// 返回ID、INTEGER_LITERAL、DECIMAL_LITERAL类型的因子
+			// 返回ID、INTEGER_LITERAL、DECIMAL_LITERAL、STRING_LITERAL、CHAR_LITERAL类型的因子
 			return Factor{
 				Factor: factor[0].(lexer.Token),
 			}, nil
 		}
+	} else if len(factor) == 2 {
+		// ID Index或ID Selector { Selector }类型的因子
+		id, ok := factor[0].(ID)
+		if !ok || id.Type != lexer.IDENTIFIER {
+			return Factor{}, errors.New("Factor: invalid id token")
+		}
+		switch factor[1].(type) {
+		case Index:
+			return Factor{
+				Factor: IndexTuple{
+					ID:    id,
+					Index: factor[1].(Index),
+				},
+			}, nil
+		case []Selector:
+			if len(factor[1].([]Selector)) == 0 {
+				return Factor{}, errors.New("Factor: expected at least one selector")
+			}
+			return Factor{
+				Factor: FieldTuple{
+					ID:        id,
+					Selectors: factor[1].([]Selector),
+				},
+			}, nil
+		default:
+			return Factor{}, errors.New("Factor: invalid index or selectors")
+		}
 	} else if len(factor) == 3 {
 		// '(' Exp ')'类型的因子
 		switch factor[0].(type) {
@@ -927,80 +1460,34 @@ func NewFactor(factor ...any) (Factor, error) {
 		default:
 			return Factor{}, errors.New("Factor: invalid lParen token")
 		}
-	}
-	return Factor{}, nil
-}
-
-// NewConditionalExp 创建条件表达式
-// lExp: 左表达式
-// Or: or（可选）
-// rExp: 右表达式（可选）
-func NewConditionalExp(lExp RelationExp, Or *lexer.Token, rExp *RelationExp) (ConditionalExp, error) {
-	// or和右表达式必须同时存在或同时不存在
-	if (Or == nil && rExp != nil) || (Or != nil && rExp == nil) {
-		return ConditionalExp{}, errors.New("NewConditionalExp: expected Or and rExp to be both nil or both not nil")
-	}
-	// 检查or
-	if Or != nil && Or.Type != lexer.OR {
-		return ConditionalExp{}, errors.New("NewConditionalExp: invalid Or token")
-	}
-	// 如果or和右表达式都不存在，则返回左表达式
-	if Or == nil && rExp == nil {
-		return ConditionalExp{
-			RelationExp: lExp,
-		}, nil
-	}
-	return ConditionalExp{
-		RelationExp: lExp,
-		ConditionalExpRest: &ConditionalExpRest{
-			Or:          *Or,
-			RelationExp: *rExp,
-		},
-	}, nil
-}
-
-// NewRelationExp 创建关系表达式
-// lExp: 左表达式
-// And: and（可选）
-// rExp: 右表达式（可选）
-func NewRelationExp(lExp CompExp, And *lexer.Token, rExp *CompExp) (RelationExp, error) {
-	// and和右表达式必须同时存在或同时不存在
-	if (And == nil && rExp != nil) || (And != nil && rExp == nil) {
-		return RelationExp{}, errors.New("NewRelationExp: expected And and rExp to be both nil or both not nil")
-	}
-	// 检查and
-	if And != nil && And.Type != lexer.AND {
-		return RelationExp{}, errors.New("NewRelationExp: invalid And token")
-	}
-	// 如果and和右表达式都不存在，则返回左表达式
-	if And == nil && rExp == nil {
-		return RelationExp{
-			CompExp: lExp,
+	} else if len(factor) == 4 {
+		// ID '(' ActParamList ')'类型的因子
+		id, ok := factor[0].(ID)
+		if !ok || id.Type != lexer.IDENTIFIER {
+			return Factor{}, errors.New("Factor: invalid id token")
+		}
+		lParen, ok := factor[1].(lexer.Token)
+		if !ok || lParen.Type != lexer.LPAREN {
+			return Factor{}, errors.New("Factor: invalid lParen token")
+		}
+		actParamList, ok := factor[2].(*ActParamList)
+		if !ok {
+			return Factor{}, errors.New("Factor: invalid actParamList")
+		}
+		rParen, ok := factor[3].(lexer.Token)
+		if !ok || rParen.Type != lexer.RPAREN {
+			return Factor{}, errors.New("Factor: invalid rParen token")
+		}
+		return Factor{
+			Factor: CallTuple{
+				ID:           id,
+				LParen:       lParen,
+				ActParamList: *actParamList,
+				RParen:       rParen,
+			},
 		}, nil
 	}
-	return RelationExp{
-		CompExp: lExp,
-		RelationExpRest: &RelationExpRest{
-			And:     *And,
-			CompExp: *rExp,
-		},
-	}, nil
-}
-
-// NewCompExp 创建比较表达式
-// lExp: 左表达式
-// cmpOp: 比较运算符
-// rExp: 右表达式
-func NewCompExp(lExp Exp, cmpOp CmpOp, rExp Exp) (CompExp, error) {
-	// < | <= | > | >= | == | <>
-	if cmpOp.Type != lexer.LESS && cmpOp.Type != lexer.LESSEQUAL && cmpOp.Type != lexer.GREATER && cmpOp.Type != lexer.GREATEREQUAL && cmpOp.Type != lexer.EQUAL && cmpOp.Type != lexer.DIAMOND {
-		return CompExp{}, errors.New("NewCompExp: invalid cmpOp token")
-	}
-	return CompExp{
-		LExp:  lExp,
-		CmpOp: cmpOp,
-		RExp:  rExp,
-	}, nil
+	return Factor{}, nil
 }
 
 func (m Method) GetMethodName() string {
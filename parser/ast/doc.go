@@ -0,0 +1,62 @@
+package ast
+
+import (
+	"CompilerInGo/lexer"
+	"strings"
+)
+
+// NewDoc 从紧邻在target之前的注释中提取文档注释
+// 文档注释为紧邻在target上方的一个 /** ... */ 多行注释，或连续多行的 // 单行注释，
+// 与target之间隔有空行的注释不属于文档注释
+func NewDoc(comments []lexer.Token, target lexer.Token) string {
+	// 从target向前查找紧邻的注释
+	row := target.Pos.Begin.Row
+	lines := make([]string, 0)
+	for i := len(comments) - 1; i >= 0; i-- {
+		comment := comments[i]
+		// 注释与下方的内容之间不能有空行
+		if comment.Pos.End.Row != row && comment.Pos.End.Row+1 != row {
+			break
+		}
+
+		text := comment.Literal.(string)
+		if comment.Type == lexer.MULTILINE_COMMENT_LITERAL {
+			// 多行注释必须以 /** 开头，且不能与单行注释混用
+			if len(lines) == 0 && strings.HasPrefix(text, "*") {
+				return blockDoc(text[1:])
+			}
+			break
+		}
+
+		// 单行注释去掉 // 后的一个空格
+		lines = append([]string{strings.TrimRight(strings.TrimPrefix(text, " "), " \t")}, lines...)
+		row = comment.Pos.Begin.Row
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// blockDoc 整理 /** ... */ 注释的内容，去掉每行开头的空白及 *
+func blockDoc(text string) string {
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	text = strings.ReplaceAll(text, "\r", "\n")
+
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		line = strings.TrimLeft(line, " \t")
+		if strings.HasPrefix(line, "*") {
+			line = strings.TrimPrefix(line[1:], " ")
+		}
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+
+	// 去掉开头和结尾的空行
+	for len(lines) > 0 && lines[0] == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -1,5 +1,7 @@
 package ast
 
+import "CompilerInGo/lexer"
+
 // 为了方便输出AST，我们需要为AST中的每个结构体实现MarshalJSON()方法
 // 输出指定内容，略去位置，null值，以及一些不必要的信息
 const EMPTY = 0
@@ -25,6 +27,36 @@ const (
 	AND
 )
 
+const (
+	MOD = 13 + iota
+	NEG
+	NOT
+	CONCAT // 字符串拼接，由语义分析将字符串之间的+替换得到
+)
+
+// BinaryOp 二元运算符的Token类型对应的运算符
+var BinaryOp = map[lexer.TokenType]int{
+	lexer.PLUS:         PLUS,
+	lexer.MINUS:        MINUS,
+	lexer.TIMES:        TIMES,
+	lexer.DIVIDE:       DIVIDE,
+	lexer.MOD:          MOD,
+	lexer.LESS:         LESS,
+	lexer.LESSEQUAL:    LESSEQUAL,
+	lexer.GREATER:      GREATER,
+	lexer.GREATEREQUAL: GREATEREQUAL,
+	lexer.EQUAL:        EQUAL,
+	lexer.DIAMOND:      DIAMOND,
+	lexer.OR:           OR,
+	lexer.AND:          AND,
+}
+
+// UnaryOp 一元运算符的Token类型对应的运算符
+var UnaryOp = map[lexer.TokenType]int{
+	lexer.MINUS: NEG,
+	lexer.NOT:   NOT,
+}
+
 type TypeIDSequence struct {
 	Seq []TypeIDPair
 }
@@ -38,6 +70,7 @@ func (paramList *ParamList) Integrate() (*TypeIDSequence, error) {
 	tuple = append(tuple, TypeIDPair{
 		Type: paramList.ParamList.Type,
 		ID:   paramList.ParamList.ID,
+		Dim:  paramList.ParamList.Dim,
 	})
 
 	if paramList.ParamList.ParamListRest != nil {
@@ -45,6 +78,7 @@ func (paramList *ParamList) Integrate() (*TypeIDSequence, error) {
 			tuple = append(tuple, TypeIDPair{
 				Type: elem.Type,
 				ID:   elem.ID,
+				Dim:  elem.Dim,
 			})
 		}
 	}
@@ -54,11 +88,21 @@ func (paramList *ParamList) Integrate() (*TypeIDSequence, error) {
 	}, nil
 }
 
+// exp 获取初始值表达式，没有初始值时为nil
+func (i *Initializer) exp() *Exp {
+	if i == nil {
+		return nil
+	}
+	return &i.Exp
+}
+
 func (l *LocalVariableDeclaration) Integrate() (TypeIDSequence, error) {
 	tuple := make([]TypeIDPair, 0)
 	tuple = append(tuple, TypeIDPair{
 		Type: l.Type,
 		ID:   l.ID,
+		Dim:  l.Dim,
+		Init: l.Initializer.exp(),
 	})
 
 	if l.LocalVariableDeclarationRest == nil {
@@ -71,6 +115,8 @@ func (l *LocalVariableDeclaration) Integrate() (TypeIDSequence, error) {
 		tuple = append(tuple, TypeIDPair{
 			Type: l.Type,
 			ID:   rest.ID,
+			Dim:  rest.Dim,
+			Init: rest.Initializer.exp(),
 		})
 	}
 	return TypeIDSequence{
@@ -78,36 +124,16 @@ func (l *LocalVariableDeclaration) Integrate() (TypeIDSequence, error) {
 	}, nil
 }
 
-func (t Term) Integrate() []Factor {
-	if t.TermRest == nil {
-		return []Factor{t.Factor}
+// Integrate 获取输入语句中的全部变量名
+func (r *ReadStatement) Integrate() ([]ID, error) {
+	ids := []ID{r.ID}
+	if r.ReadRest == nil {
+		return ids, nil
 	}
-	return []Factor{t.Factor, t.TermRest.Factor}
-}
-
-func (e Exp) Integrate() []Term {
-	if e.ExpRest == nil {
-		return []Term{e.Term}
-	}
-	return []Term{e.Term, e.ExpRest.Term}
-}
-
-func (conditionalExp ConditionalExp) Integrate() []RelationExp {
-	if conditionalExp.ConditionalExpRest == nil {
-		return []RelationExp{conditionalExp.RelationExp}
+	for _, elem := range *r.ReadRest {
+		ids = append(ids, elem.ID)
 	}
-	return []RelationExp{conditionalExp.RelationExp, conditionalExp.ConditionalExpRest.RelationExp}
-}
-
-func (r RelationExp) Integrate() []CompExp {
-	if r.RelationExpRest == nil {
-		return []CompExp{r.CompExp}
-	}
-	return []CompExp{r.CompExp, r.RelationExpRest.CompExp}
-}
-
-func (c CompExp) Integrate() []Exp {
-	return []Exp{c.LExp, c.RExp}
+	return ids, nil
 }
 
 func (a ActParamList) Integrate() ([]Exp, error) {
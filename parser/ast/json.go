@@ -11,6 +11,8 @@ import (
 type TypeIDPair struct {
 	Type Type
 	ID   ID
+	Dim  *ArrayDim `json:",omitempty"` // 数组的长度
+	Init *Exp      `json:",omitempty"` // 局部变量的初始值
 }
 
 func (resultType *ResultType) MarshalJSON() ([]byte, error) {
@@ -58,6 +60,7 @@ func (paramList *ParamList) MarshalJSON() ([]byte, error) {
 	tuple = append(tuple, TypeIDPair{
 		Type: paramList.ParamList.Type,
 		ID:   paramList.ParamList.ID,
+		Dim:  paramList.ParamList.Dim,
 	})
 
 	if paramList.ParamList.ParamListRest != nil {
@@ -65,6 +68,7 @@ func (paramList *ParamList) MarshalJSON() ([]byte, error) {
 			tuple = append(tuple, TypeIDPair{
 				Type: elem.Type,
 				ID:   elem.ID,
+				Dim:  elem.Dim,
 			})
 		}
 	}
@@ -79,9 +83,13 @@ func (l *LocalVariableDeclaration) MarshalJSON() ([]byte, error) {
 	}{
 		Type: l.Type,
 	}, struct {
-		ID ID
+		ID   ID
+		Dim  *ArrayDim `json:",omitempty"`
+		Init *Exp      `json:",omitempty"`
 	}{
-		ID: l.ID,
+		ID:   l.ID,
+		Dim:  l.Dim,
+		Init: l.Initializer.exp(),
 	})
 
 	if l.LocalVariableDeclarationRest == nil {
@@ -90,9 +98,13 @@ func (l *LocalVariableDeclaration) MarshalJSON() ([]byte, error) {
 
 	for _, elem := range *l.LocalVariableDeclarationRest {
 		pair = append(pair, struct {
-			ID ID
+			ID   ID
+			Dim  *ArrayDim `json:",omitempty"`
+			Init *Exp      `json:",omitempty"`
 		}{
-			ID: elem.ID,
+			ID:   elem.ID,
+			Dim:  elem.Dim,
+			Init: elem.Initializer.exp(),
 		})
 	}
 	return json.Marshal(pair)
@@ -127,92 +139,29 @@ func (f Factor) MarshalJSON() ([]byte, error) {
 	}
 }
 
-func (t Term) MarshalJSON() ([]byte, error) {
-	if t.TermRest == nil {
-		return json.Marshal(struct {
-			Factor Factor
-		}{
-			Factor: t.Factor,
-		})
-	}
-	return json.Marshal(struct {
-		LFactor Factor
-		Op      lexer.Token
-		RFactor Factor
-	}{
-		LFactor: t.Factor,
-		Op:      t.TermRest.MulOrDiv,
-		RFactor: t.TermRest.Factor,
-	})
-}
-
 func (e Exp) MarshalJSON() ([]byte, error) {
-	if e.ExpRest == nil {
+	switch exp := e.Exp.(type) {
+	case UnaryExp:
 		return json.Marshal(struct {
-			Term Term
+			Op  any
+			Exp *Exp
 		}{
-			Term: e.Term,
+			Op:  exp.Op.Literal,
+			Exp: exp.Exp,
 		})
-	}
-	return json.Marshal(struct {
-		LTerm Term
-		Op    lexer.Token
-		RTerm Term
-	}{
-		LTerm: e.Term,
-		Op:    e.ExpRest.PlusOrMinus,
-		RTerm: e.ExpRest.Term,
-	})
-}
-
-func (c CmpOp) MarshalJSON() ([]byte, error) {
-	return json.Marshal(struct {
-		Literal  string
-		Type     string
-		Category string
-	}{
-		Literal:  c.Literal.(string),
-		Type:     lexer.TokenTypeString[c.Type],
-		Category: lexer.TokenCategoryString[c.Category],
-	})
-}
-
-func (conditionalExp ConditionalExp) MarshalJSON() ([]byte, error) {
-	if conditionalExp.ConditionalExpRest == nil {
+	case BinaryExp:
 		return json.Marshal(struct {
-			RelationExp RelationExp
+			LExp *Exp
+			Op   any
+			RExp *Exp
 		}{
-			RelationExp: conditionalExp.RelationExp,
-		})
-	}
-	return json.Marshal(struct {
-		LRelationExp RelationExp
-		Or           lexer.Token
-		RRelationExp RelationExp
-	}{
-		LRelationExp: conditionalExp.RelationExp,
-		Or:           conditionalExp.ConditionalExpRest.Or,
-		RRelationExp: conditionalExp.ConditionalExpRest.RelationExp,
-	})
-}
-
-func (r RelationExp) MarshalJSON() ([]byte, error) {
-	if r.RelationExpRest == nil {
-		return json.Marshal(struct {
-			CompExp CompExp
-		}{
-			CompExp: r.CompExp,
+			LExp: exp.LExp,
+			Op:   exp.Op.Literal,
+			RExp: exp.RExp,
 		})
+	default:
+		return json.Marshal(e.Exp)
 	}
-	return json.Marshal(struct {
-		LCompExp CompExp
-		And      lexer.Token
-		RCompExp CompExp
-	}{
-		LCompExp: r.CompExp,
-		And:      r.RelationExpRest.And,
-		RCompExp: r.RelationExpRest.CompExp,
-	})
 }
 
 func (a ActParamList) MarshalJSON() ([]byte, error) {
@@ -0,0 +1,270 @@
+package parser
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/parser/ast"
+	"CompilerInGo/utils"
+	"errors"
+	"fmt"
+	"github.com/kpango/glg"
+	"strings"
+)
+
+// SyntaxError 语法错误
+type SyntaxError struct {
+	Token    lexer.Token        // 出错的Token
+	Pos      utils.PositionPair // 错误位置
+	Expected []string           // 期望的Token种类，可能为空
+	Message  string             // 错误信息
+}
+
+// newSyntaxError 创建位于token处的语法错误
+func newSyntaxError(token lexer.Token, format string, args ...any) *SyntaxError {
+	return &SyntaxError{
+		Token:   token,
+		Pos:     token.Pos,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// newExpectError 创建token不是期望的Token种类expected时的语法错误
+// 错误信息列出全部期望的Token种类及实际读到的Token，如：expected one of `;`, `,`, found `int`
+func newExpectError(token lexer.Token, expected ...string) *SyntaxError {
+	var message string
+	switch len(expected) {
+	case 0:
+		message = fmt.Sprintf("unexpected %s", token.Describe())
+	case 1:
+		message = fmt.Sprintf("expected %s, found %s", expected[0], token.Describe())
+	default:
+		message = fmt.Sprintf("expected one of %s, found %s", strings.Join(expected, ", "), token.Describe())
+	}
+	return &SyntaxError{
+		Token:    token,
+		Pos:      token.Pos,
+		Expected: expected,
+		Message:  message,
+	}
+}
+
+// typeNames 获取Token类型在错误信息中的名称
+func typeNames(types ...lexer.TokenType) []string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, lexer.TypeName(t))
+	}
+	return names
+}
+
+// contains 判断names中是否包含name
+func contains(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Error 获取语法错误的字符串表示
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error: %s, at %d:%d to %d:%d", e.Message, e.Pos.Begin.Row, e.Pos.Begin.Col, e.Pos.End.Row, e.Pos.End.Col)
+}
+
+// SyntaxErrors 语法分析中出现的全部语法错误
+type SyntaxErrors []SyntaxError
+
+// Error 获取全部语法错误的字符串表示，每行一个错误
+func (e SyntaxErrors) Error() string {
+	str := make([]string, 0, len(e))
+	for _, err := range e {
+		str = append(str, err.Error())
+	}
+	return strings.Join(str, "\n")
+}
+
+// errAbortMethod 语句级恢复同步到了方法头或EOF，放弃当前方法
+var errAbortMethod = errors.New("abort method")
+
+// addError 记录语法错误
+func (p *Parser) addError(err *SyntaxError) {
+	p.Errors = append(p.Errors, *err)
+}
+
+// HasErrors 判断语法分析过程中是否出现语法错误
+func (p *Parser) HasErrors() bool {
+	return len(p.Errors) > 0
+}
+
+// SetSource 设置源程序的文件名及行偏移表，输出语法错误时显示出错行
+// 流式模式下直接使用Lexer的源程序
+func (p *Parser) SetSource(name string, lines *utils.LineTable) {
+	p.name, p.lines = name, lines
+}
+
+// ReportErrors 输出全部语法错误，包括错误所在行及位置指示器
+func (p *Parser) ReportErrors() {
+	name, lines := p.name, p.lines
+	if lines == nil && p.lexer != nil {
+		name, lines = p.lexer.Name, p.lexer.Lines()
+	}
+
+	for _, err := range p.Errors {
+		_ = glg.Fail("Error while parsing: ", err.Error())
+
+		// 没有源程序时只输出错误信息
+		if lines == nil {
+			continue
+		}
+
+		// 获取文件出错行内容
+		errorLine := utils.GetLine(lines, err.Pos.Begin)
+
+		// 显示错误信息
+		_ = glg.Failf("Position: %s, Line %d, Column %d", name, err.Pos.Begin.Row, err.Pos.Begin.Col)
+		_ = glg.Fail(errorLine)
+
+		// 显示错误位置指示器
+		_ = glg.Fail(utils.Indicator(errorLine, err.Pos))
+	}
+}
+
+// Errorf 在当前Token处产生语法错误并panic，由错误恢复捕获
+func (p *Parser) Errorf(format string, args ...interface{}) {
+	var token lexer.Token
+	if p.token != nil {
+		token = *p.token
+	}
+	panic(newSyntaxError(token, format, args...))
+}
+
+// ErrorToken 在token处产生语法错误并panic，由错误恢复捕获
+func (p *Parser) ErrorToken(token lexer.Token, format string, args ...interface{}) {
+	panic(newSyntaxError(token, format, args...))
+}
+
+// parseMethodRecover 解析方法，出错时记录错误并同步到下一个方法头
+func (p *Parser) parseMethodRecover() {
+	defer func() {
+		if r := recover(); r != nil {
+			if r == errAbortMethod {
+				// 语句级恢复已经记录错误并同步到方法头或EOF
+				return
+			}
+			syntaxErr, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			p.addError(syntaxErr)
+			p.syncMethod()
+		}
+	}()
+
+	p.program.Method = append(p.program.Method, *p.parseMethod())
+}
+
+// parseGlobalRecover 解析全局变量、常量声明，出错时记录错误并同步到下一个方法头
+func (p *Parser) parseGlobalRecover() {
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			p.addError(syntaxErr)
+			p.syncMethod()
+		}
+	}()
+
+	p.program.Global = append(p.program.Global, *p.parseGlobalDeclaration())
+}
+
+// parseStructRecover 解析结构体声明，出错时记录错误并同步到下一个方法头
+func (p *Parser) parseStructRecover() {
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			p.addError(syntaxErr)
+			p.syncMethod()
+		}
+	}()
+
+	p.program.Struct = append(p.program.Struct, *p.parseStructDeclaration())
+}
+
+// parseStmtRecover 解析单条语句，出错时记录错误并同步到语句结束
+// 返回false表示出错，语句被跳过
+func (p *Parser) parseStmtRecover() (statement ast.Statement, ok bool) {
+	defer func() {
+		if r := recover(); r != nil {
+			syntaxErr, isSyntaxErr := r.(*SyntaxError)
+			if !isSyntaxErr {
+				panic(r)
+			}
+			p.addError(syntaxErr)
+			if !p.syncStatement() {
+				// 同步到方法头或EOF，当前方法无法继续解析
+				panic(errAbortMethod)
+			}
+			statement, ok = ast.Statement{}, false
+		}
+	}()
+
+	return p.parseStmt(), true
+}
+
+// syncStatement 跳过Token直到语句结束
+// 遇到分号时跳过分号，遇到右大括号时保留右大括号，由语句列表结束代码块，跳过的代码块整体跳过
+// 遇到方法头或EOF时返回false
+func (p *Parser) syncStatement() bool {
+	// 跳过的代码块深度
+	depth := 0
+	for {
+		token := p.PeekToken()
+		switch {
+		case token.Type == lexer.EOF_LITERAL:
+			return false
+		case depth == 0 && p.isDeclarationHeader():
+			return false
+		case token.Type == lexer.SEMICOLON && depth == 0:
+			p.ReadToken()
+			return true
+		case token.Type == lexer.LBRACE:
+			p.ReadToken()
+			depth++
+		case token.Type == lexer.RBRACE:
+			if depth == 0 {
+				return true
+			}
+			p.ReadToken()
+			depth--
+			if depth == 0 {
+				// 跳过的代码块结束，语句也随之结束
+				return true
+			}
+		default:
+			p.ReadToken()
+		}
+	}
+}
+
+// syncMethod 跳过Token直到下一个方法头、常量声明、结构体声明或EOF
+func (p *Parser) syncMethod() {
+	for p.PeekToken().Type != lexer.EOF_LITERAL && !p.isDeclarationHeader() {
+		p.ReadToken()
+	}
+}
+
+// isMethodHeader 判断之后的Token是否为方法头：返回值类型 方法名 (
+func (p *Parser) isMethodHeader() bool {
+	return ast.IsResultType(p.PeekTokenN(0)) && ast.IsID(p.PeekTokenN(1)) && p.PeekTokenN(2).Type == lexer.LPAREN
+}
+
+// isDeclarationHeader 判断之后的Token是否为方法头、常量声明或结构体声明
+// 全局变量声明与局部变量声明形式相同，不作为同步的位置
+func (p *Parser) isDeclarationHeader() bool {
+	return p.isMethodHeader() || p.PeekTokenN(0).Type == lexer.CONST || p.PeekTokenN(0).Type == lexer.STRUCT
+}
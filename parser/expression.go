@@ -0,0 +1,181 @@
+package parser
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/parser/ast"
+	"strings"
+)
+
+// 运算符的绑定强度，数值越大结合越紧密
+const (
+	powerLowest   = iota // 表达式的最低绑定强度
+	powerOr              // or
+	powerAnd             // and
+	powerEquality        // == <>
+	powerRelation        // < <= > >=
+	powerSum             // + -
+	powerProduct         // * / %
+	powerPrefix          // 一元负号
+)
+
+// operator 运算符表中的一项
+type operator struct {
+	Type       lexer.TokenType // 运算符的Token类型
+	Power      int             // 绑定强度
+	RightAssoc bool            // 是否右结合，仅对中缀运算符有效
+}
+
+// prefixOperators 前缀运算符表，Power为操作数的绑定强度
+// not的操作数可以包含比较运算，not a < b 即 not (a < b)
+var prefixOperators = []operator{
+	{Type: lexer.MINUS, Power: powerPrefix},
+	{Type: lexer.NOT, Power: powerAnd},
+}
+
+// infixOperators 中缀运算符表，按绑定强度从高到低排列，错误信息中按此顺序列出期望的运算符
+var infixOperators = []operator{
+	{Type: lexer.TIMES, Power: powerProduct},
+	{Type: lexer.DIVIDE, Power: powerProduct},
+	{Type: lexer.MOD, Power: powerProduct},
+	{Type: lexer.PLUS, Power: powerSum},
+	{Type: lexer.MINUS, Power: powerSum},
+	{Type: lexer.LESS, Power: powerRelation},
+	{Type: lexer.LESSEQUAL, Power: powerRelation},
+	{Type: lexer.GREATER, Power: powerRelation},
+	{Type: lexer.GREATEREQUAL, Power: powerRelation},
+	{Type: lexer.EQUAL, Power: powerEquality},
+	{Type: lexer.DIAMOND, Power: powerEquality},
+	{Type: lexer.AND, Power: powerAnd},
+	{Type: lexer.OR, Power: powerOr},
+}
+
+// findOperator 在运算符表table中查找类型为t的运算符
+func findOperator(table []operator, t lexer.TokenType) (operator, bool) {
+	for _, op := range table {
+		if op.Type == t {
+			return op, true
+		}
+	}
+	return operator{}, false
+}
+
+// operatorNames 获取运算符表table中绑定强度大于minPower的运算符在错误信息中的名称
+func operatorNames(table []operator, minPower int) []string {
+	names := make([]string, 0, len(table))
+	for _, op := range table {
+		if op.Power > minPower {
+			names = append(names, lexer.TypeName(op.Type))
+		}
+	}
+	return names
+}
+
+// isNegativeNumber 判断token是否为带负号的数字字面量
+// 词法分析将紧跟数字的负号扫描为数字的一部分，如a-1中的-1
+func isNegativeNumber(token lexer.Token) bool {
+	if token.Type != lexer.INTEGER_LITERAL && token.Type != lexer.DECIMAL_LITERAL {
+		return false
+	}
+	if token.Raw != "" {
+		return strings.HasPrefix(token.Raw, "-")
+	}
+	switch literal := token.Literal.(type) {
+	case int64:
+		return literal < 0
+	case float64:
+		return literal < 0
+	}
+	return false
+}
+
+// splitNegativeNumber 将带负号的数字字面量拆分为减号及不带负号的数字字面量
+func splitNegativeNumber(token lexer.Token) (lexer.Token, lexer.Token) {
+	minus := lexer.NewToken("-", token.Pos, lexer.MINUS)
+	minus.Pos.End = token.Pos.Begin
+	minus.Raw, minus.Offset = "-", token.Offset
+
+	number := token
+	switch literal := token.Literal.(type) {
+	case int64:
+		number.Literal = -literal
+	case float64:
+		number.Literal = -literal
+	}
+	number.Pos.Begin.Col++
+	number.Pos.Begin.FilePos++
+	if len(token.Raw) > 1 {
+		number.Raw = token.Raw[1:]
+		number.Pos.Begin.Ch = rune(number.Raw[0])
+	}
+	number.Offset++
+	return minus, number
+}
+
+// parseExp 解析表达式
+func (p *Parser) parseExp() *ast.Exp {
+	return p.parseExpression(powerLowest)
+}
+
+// parseExpression 解析绑定强度大于minPower的运算符组成的表达式
+func (p *Parser) parseExpression(minPower int) *ast.Exp {
+	return p.parseInfix(p.parsePrefix(), minPower)
+}
+
+// parsePrefix 解析前缀运算符及其操作数，或者因子
+func (p *Parser) parsePrefix() *ast.Exp {
+	token, isPrefix := p.OptionalAcceptTokenByFunc(func(token lexer.Token) bool {
+		_, ok := findOperator(prefixOperators, token.Type)
+		return ok
+	}, operatorNames(prefixOperators, powerLowest)...)
+	if !isPrefix {
+		// 因子
+		exp, _ := ast.NewExp(*p.parseFactor())
+		return &exp
+	}
+
+	// 前缀运算符 操作数
+	op, _ := findOperator(prefixOperators, token.Type)
+	operand := p.parseExpression(op.Power)
+	exp, _ := ast.NewExp(token, operand)
+	return &exp
+}
+
+// parseInfix 以left为左操作数，不断解析绑定强度大于minPower的中缀运算符及其右操作数
+func (p *Parser) parseInfix(left *ast.Exp, minPower int) *ast.Exp {
+	minus, _ := findOperator(infixOperators, lexer.MINUS)
+	for {
+		token, isInfix := p.OptionalAcceptTokenByFunc(func(token lexer.Token) bool {
+			if isNegativeNumber(token) {
+				// 负数字面量视为减号及其右操作数
+				return minus.Power > minPower
+			}
+			op, ok := findOperator(infixOperators, token.Type)
+			return ok && op.Power > minPower
+		}, operatorNames(infixOperators, minPower)...)
+		if !isInfix {
+			// 没有更紧密的中缀运算符，结束
+			return left
+		}
+
+		var right *ast.Exp
+		if isNegativeNumber(token) {
+			// 减号之后的数字字面量为右操作数的第一个因子
+			var number lexer.Token
+			token, number = splitNegativeNumber(token)
+			factor, _ := ast.NewFactor(number)
+			exp, _ := ast.NewExp(factor)
+			right = p.parseInfix(&exp, minus.Power)
+		} else {
+			op, _ := findOperator(infixOperators, token.Type)
+			// 左结合时右操作数只包含绑定强度更大的运算符，右结合时包含相同绑定强度的运算符
+			power := op.Power
+			if op.RightAssoc {
+				power--
+			}
+			right = p.parseExpression(power)
+		}
+
+		exp, _ := ast.NewExp(left, token, right)
+		left = &exp
+	}
+}
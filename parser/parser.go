@@ -3,8 +3,10 @@ package parser
 import (
 	"CompilerInGo/lexer"
 	"CompilerInGo/parser/ast"
+	"CompilerInGo/utils"
 	"fmt"
 	"github.com/kpango/glg"
+	"strings"
 )
 
 type Parser struct {
@@ -14,34 +16,83 @@ type Parser struct {
 	token *lexer.Token
 	// token流
 	*TokenStream
-	err error
+	// 流式模式下的词法分析器
+	lexer *lexer.Lexer
+	// 非流式模式下的全部Token
+	tokens []lexer.Token
+	err    error
+	// 语法分析中出现的全部语法错误
+	Errors []SyntaxError
+	// 源程序的文件名及行偏移表，输出语法错误时使用
+	name  string
+	lines *utils.LineTable
 }
 
-// NewParser 创建一个新的Parser
-func NewParser() *Parser {
-	return &Parser{}
+// NewParserFromTokens 创建一个读取tokens的Parser
+// tokens只读不修改，不同的Parser可以同时读取同一个Token序列
+func NewParserFromTokens(tokens []lexer.Token) *Parser {
+	return &Parser{
+		tokens: tokens,
+	}
+}
+
+// NewParserFromLexer 创建一个流式Parser
+// 词法分析与语法分析流水线执行，Parser按需从Lexer读取Token
+func NewParserFromLexer(lex *lexer.Lexer) *Parser {
+	return &Parser{
+		lexer: lex,
+	}
+}
+
+// ParseTokens 解析Token序列tokens，不依赖全局状态，可以并发调用
+func ParseTokens(tokens []lexer.Token) (*ast.Program, error) {
+	return NewParserFromTokens(tokens).Parse()
+}
+
+// ParseSource 对文件名为name的源程序src进行词法分析和语法分析，不依赖全局状态，可以并发调用
+// 出现词法错误时不进行语法分析，返回lexer.LexErrors
+func ParseSource(name, src string) (*ast.Program, error) {
+	lex := lexer.NewLexerFromReader(name, strings.NewReader(src))
+	tokens := lex.Tokenize()
+	if lex.HasErrors() {
+		return nil, lexer.LexErrors(lex.Errors)
+	}
+
+	return ParseTokens(tokens.Pool)
 }
 
 // Parse 开始解析
+// 出现语法错误时仍然返回部分AST，err为全部语法错误SyntaxErrors
 func (p *Parser) Parse() (program *ast.Program, err error) {
 	// 错误处理
 	defer func() {
-		// 如果有错误，打印错误
-		if r := recover(); r != p.err {
+		// 语法错误已经被错误恢复捕获，其他panic打印错误
+		if r := recover(); r != nil {
 			_ = glg.Error(r)
+			p.err = fmt.Errorf("%v", r)
+		}
+		// 存在语法错误
+		if p.err == nil && p.HasErrors() {
+			p.err = SyntaxErrors(p.Errors)
 		}
 		// 返回结果
 		program, err = p.program, p.err
 	}()
 
 	// 初始化token流
-	ts := NewTokenStream()
+	var ts TokenStream
+	if p.lexer != nil {
+		ts = NewTokenStreamFromLexer(p.lexer)
+	} else {
+		ts = NewTokenStream(p.tokens)
+	}
 	p.TokenStream = &ts
+	defer ts.Close()
 
 	// 开始解析
 	p.parse()
 
-	return p.program, p.err
+	return
 }
 
 // parse 解析
@@ -59,18 +110,26 @@ func (p *Parser) parse() {
 		case token.Type == lexer.EOF_LITERAL:
 			//读到EOF，解析结束
 			return
+		case token.Type == lexer.STRUCT:
+			// 读到struct，解析结构体声明，出错时同步到下一个方法头
+			p.parseStructRecover()
+		case token.Type == lexer.CONST || ast.IsType(token) && p.PeekTokenN(1).Type != lexer.LPAREN || ast.IsID(token) && ast.IsID(p.PeekTokenN(0)):
+			// 读到const，或类型 变量名之后不是左括号，或结构体名 变量名，解析全局变量、常量声明，出错时同步到下一个方法头
+			p.parseGlobalRecover()
 		case ast.IsResultType(token):
-			// 读到返回值类型，解析函数
-			p.program.Method = append(p.program.Method, *p.parseMethod())
+			// 读到返回值类型，解析函数，出错时同步到下一个方法头
+			p.parseMethodRecover()
 		default:
-			// 读到其他类型的token，报错
-			p.Errorf("Unexpected token %s", token.String())
+			// 读到其他类型的token，记录错误并同步到下一个方法头
+			p.addError(newExpectError(token, "method declaration", "global declaration", "struct declaration"))
+			p.syncMethod()
 		}
 	}
 }
 
 // parseMethod 解析函数
 func (p *Parser) parseMethod() *ast.Method {
+	doc := ast.NewDoc(p.LeadingComments(), *p.token)             //文档注释
 	resultType := (ast.ResultType)(*p.token)                     //返回值类型
 	ident := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) //函数名
 	lParen := p.MustAcceptTokenByType(lexer.LPAREN)              //左括号
@@ -82,14 +141,62 @@ func (p *Parser) parseMethod() *ast.Method {
 	if err != nil {
 		panic(err)
 	}
+	method.Doc = doc
 
 	return &method
 }
 
+// parseGlobalDeclaration 解析全局变量、常量声明
+func (p *Parser) parseGlobalDeclaration() *ast.GlobalDeclaration {
+	// 常量声明，const之后为类型
+	var constToken *lexer.Token
+	if p.token.Type == lexer.CONST {
+		token := *p.token
+		constToken = &token
+		typ := p.MustAcceptTokenByFunc(ast.IsType, "type")
+		p.token = &typ
+	}
+	declaration := p.parseLocalVariableDeclarationStmt() // 变量声明
+
+	global, _ := ast.NewGlobalDeclaration(constToken, declaration)
+	return &global
+}
+
+// parseStructDeclaration 解析结构体声明
+func (p *Parser) parseStructDeclaration() *ast.StructDeclaration {
+	token := *p.token                                         // struct
+	id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) // 结构体名
+	lBrace := p.MustAcceptTokenByType(lexer.LBRACE)           // {
+
+	// 不断解析字段声明，直到右大括号
+	fields := make([]ast.LocalVariableDeclaration, 0)
+	for {
+		// 遇到方法头，缺少右大括号，由之后的MustAccept报错
+		if p.isMethodHeader() {
+			break
+		}
+		typ, isField := p.OptionalAcceptTokenByFunc(isVariableType, "type")
+		if !isField {
+			break
+		}
+		p.token = &typ
+		fields = append(fields, *p.parseLocalVariableDeclarationStmt())
+	}
+	rBrace := p.MustAcceptTokenByType(lexer.RBRACE) // }
+
+	declaration, _ := ast.NewStructDeclaration(token, id, lBrace, fields, rBrace)
+	return &declaration
+}
+
+// isVariableType 判断是否为变量类型，包括基本类型及结构体名
+func isVariableType(token lexer.Token) bool {
+	return ast.IsType(token) || ast.IsID(token)
+}
+
 // parseParamList 解析参数列表
 func (p *Parser) parseParamList() *ast.ParamList {
 	// 可选参数列表，可能为空，使用Optional判断是否接受
-	typ, notEmpty := p.OptionalAcceptTokenByFunc(ast.IsType)
+	typ, notEmpty := p.OptionalAcceptTokenByFunc(isVariableType, "type")
 	if !notEmpty {
 		// 如果为空，返回空的参数列表
 		paramList, _ := ast.NewParamList()
@@ -99,8 +206,11 @@ func (p *Parser) parseParamList() *ast.ParamList {
 	typToken := (ast.Type)(typ)                               //参数类型
 	id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) //参数名
 
-	// 逗号+类型+参数名的tuple数组
+	// 逗号+类型+参数名+数组长度的tuple数组
 	commaTypeIDTuple := []any{typToken, id}
+	if dim := p.parseArrayDim(); dim != nil {
+		commaTypeIDTuple = append(commaTypeIDTuple, dim)
+	}
 
 	for {
 		// 逗号，可能为空，使用Optional判断是否接受
@@ -111,10 +221,13 @@ func (p *Parser) parseParamList() *ast.ParamList {
 			return &paramList
 		}
 		// 逗号后面必须是类型和参数名
-		typ := (ast.Type)(p.MustAcceptTokenByFunc(ast.IsType))
+		typ := (ast.Type)(p.MustAcceptTokenByFunc(isVariableType, "type"))
 		id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER))
 		// 添加到tuple数组
 		commaTypeIDTuple = append(commaTypeIDTuple, comma, typ, id)
+		if dim := p.parseArrayDim(); dim != nil {
+			commaTypeIDTuple = append(commaTypeIDTuple, dim)
+		}
 	}
 }
 
@@ -138,7 +251,15 @@ func (p *Parser) parseStmtList() []ast.Statement {
 
 	// 不断解析语句，直到返回空语句
 	for {
-		statement := p.parseStmt()
+		// 遇到方法头，缺少右大括号，由parseBlock报错
+		if p.isMethodHeader() {
+			return statements
+		}
+		statement, ok := p.parseStmtRecover()
+		if !ok {
+			// 语句出错，已同步到语句结束，继续解析
+			continue
+		}
 		if statement == (ast.Statement{}) {
 			return statements
 		}
@@ -155,13 +276,18 @@ func (p *Parser) parseStmt() ast.Statement {
 			token.Type == lexer.CALL ||
 			token.Type == lexer.IF ||
 			token.Type == lexer.WHILE ||
+			token.Type == lexer.FOR ||
+			token.Type == lexer.DO ||
+			token.Type == lexer.SWITCH ||
+			token.Type == lexer.READ ||
+			token.Type == lexer.WRITE ||
 			token.Type == lexer.RETURN ||
 			token.Type == lexer.BREAK ||
 			token.Type == lexer.CONTINUE ||
 			token.Type == lexer.LBRACE ||
 			token.Type == lexer.RBRACE ||
 			token.Type == lexer.SEMICOLON
-	})
+	}, append([]string{"identifier", "type"}, typeNames(lexer.CALL, lexer.IF, lexer.WHILE, lexer.FOR, lexer.DO, lexer.SWITCH, lexer.READ, lexer.WRITE, lexer.RETURN, lexer.BREAK, lexer.CONTINUE, lexer.LBRACE, lexer.RBRACE, lexer.SEMICOLON)...)...)
 	p.token = &token
 
 	// 根据token类型判断语句类型
@@ -184,6 +310,36 @@ func (p *Parser) parseStmt() ast.Statement {
 			Statement: p.parseLoopStmt(),
 			Type:      ast.LOOPSTATEMENT,
 		}
+	case lexer.FOR:
+		// for循环语句
+		return ast.Statement{
+			Statement: p.parseForStmt(),
+			Type:      ast.FORSTATEMENT,
+		}
+	case lexer.DO:
+		// do-while循环语句
+		return ast.Statement{
+			Statement: p.parseDoWhileStmt(),
+			Type:      ast.DOWHILESTATEMENT,
+		}
+	case lexer.SWITCH:
+		// switch语句
+		return ast.Statement{
+			Statement: p.parseSwitchStmt(),
+			Type:      ast.SWITCHSTATEMENT,
+		}
+	case lexer.READ:
+		// 输入语句
+		return ast.Statement{
+			Statement: p.parseReadStmt(),
+			Type:      ast.READSTATEMENT,
+		}
+	case lexer.WRITE:
+		// 输出语句
+		return ast.Statement{
+			Statement: p.parseWriteStmt(),
+			Type:      ast.WRITESTATEMENT,
+		}
 	case lexer.RETURN:
 		// 返回语句
 		return ast.Statement{
@@ -218,7 +374,13 @@ func (p *Parser) parseStmt() ast.Statement {
 		// 空语句
 		return ast.Statement{}
 	default:
-		if ast.IsID(token) {
+		if ast.IsID(token) && ast.IsID(p.PeekToken()) {
+			// 结构体名 变量名，变量声明语句
+			return ast.Statement{
+				Statement: p.parseLocalVariableDeclarationStmt(),
+				Type:      ast.LOCALVARIABLEDECLARATION,
+			}
+		} else if ast.IsID(token) {
 			// 赋值语句
 			return ast.Statement{
 				Statement: p.parseAssignStmt(),
@@ -232,24 +394,12 @@ func (p *Parser) parseStmt() ast.Statement {
 			}
 		} else {
 			// 未知语句
-			p.Errorf("Unexpected token %s", token.String())
+			p.ErrorToken(token, "unexpected %s", token.Describe())
 			return ast.Statement{}
 		}
 	}
 }
 
-// Errorf 存储错误并panic
-func (p *Parser) Errorf(format string, args ...interface{}) {
-	p.err = fmt.Errorf(format, args...)
-	panic(p.err)
-}
-
-// ErrorToken 存储错误并panic
-func (p *Parser) ErrorToken(token lexer.Token, format string, args ...interface{}) {
-	p.err = fmt.Errorf("%s: %s", token.String(), fmt.Sprintf(format, args...))
-	panic(p.err)
-}
-
 // parseBreakStmt 解析跳出语句
 func (p *Parser) parseBreakStmt() *ast.BreakStatement {
 	token := p.MustAcceptTokenByType(lexer.SEMICOLON)
@@ -285,21 +435,56 @@ func (p *Parser) parseCallStmt() *ast.CallStatement {
 	return &stmt
 }
 
+// parseReadStmt 解析输入语句
+func (p *Parser) parseReadStmt() *ast.ReadStatement {
+	token := *p.token                                         // read
+	lParen := p.MustAcceptTokenByType(lexer.LPAREN)           // (
+	id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) // 变量名
+
+	commaIDPair := make([]any, 0) // 逗号-变量名
+
+	// 不断解析逗号-变量名
+	for {
+		comma, isComma := p.OptionalAcceptTokenByType(lexer.COMMA)
+		if !isComma {
+			break
+		}
+		commaIDPair = append(commaIDPair, comma, (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)))
+	}
+	rParen := p.MustAcceptTokenByType(lexer.RPAREN)       // )
+	semicolon := p.MustAcceptTokenByType(lexer.SEMICOLON) // ;
+
+	stmt, _ := ast.NewReadStatement(token, lParen, id, commaIDPair, rParen, semicolon)
+	return &stmt
+}
+
+// parseWriteStmt 解析输出语句
+func (p *Parser) parseWriteStmt() *ast.WriteStatement {
+	token := *p.token                                     // write
+	lParen := p.MustAcceptTokenByType(lexer.LPAREN)       // (
+	actParamList := p.parseActParamList()                 // 表达式列表
+	rParen := p.MustAcceptTokenByType(lexer.RPAREN)       // )
+	semicolon := p.MustAcceptTokenByType(lexer.SEMICOLON) // ;
+
+	stmt, _ := ast.NewWriteStatement(token, lParen, actParamList, rParen, semicolon)
+	return &stmt
+}
+
 // parseIfStmt 解析条件语句
 func (p *Parser) parseIfStmt() *ast.ConditionalStatement {
 	token := *p.token                                             // if
 	lParen := p.MustAcceptTokenByType(lexer.LPAREN)               // (
-	conditionalExp := p.parseConditionalExp()                     // 条件表达式
+	condition := p.parseExp()                                     // 条件表达式
 	rParen := p.MustAcceptTokenByType(lexer.RPAREN)               // )
 	stmt := p.parseStmt()                                         // if语句
 	elseToken, hasElse := p.OptionalAcceptTokenByType(lexer.ELSE) // 是否有else
 	if hasElse {
 		// 有else
 		elseStmt := p.parseStmt()
-		conditionalStmt, _ := ast.NewConditionalStatement(token, lParen, *conditionalExp, rParen, stmt, &elseToken, &elseStmt)
+		conditionalStmt, _ := ast.NewConditionalStatement(token, lParen, *condition, rParen, stmt, &elseToken, &elseStmt)
 		return &conditionalStmt
 	} else {
-		conditionalStmt, _ := ast.NewConditionalStatement(token, lParen, *conditionalExp, rParen, stmt, nil, nil)
+		conditionalStmt, _ := ast.NewConditionalStatement(token, lParen, *condition, rParen, stmt, nil, nil)
 		return &conditionalStmt
 	}
 }
@@ -308,22 +493,160 @@ func (p *Parser) parseIfStmt() *ast.ConditionalStatement {
 func (p *Parser) parseLoopStmt() *ast.LoopStatement {
 	token := *p.token                               // while
 	lParen := p.MustAcceptTokenByType(lexer.LPAREN) // (
-	conditionalExp := p.parseConditionalExp()       // 条件表达式
+	condition := p.parseExp()                       // 条件表达式
 	rParen := p.MustAcceptTokenByType(lexer.RPAREN) // )
 	stmt := p.parseStmt()                           // while语句
 
-	loopStmt, _ := ast.NewLoopStatement(token, lParen, *conditionalExp, rParen, stmt)
+	loopStmt, _ := ast.NewLoopStatement(token, lParen, *condition, rParen, stmt)
 	return &loopStmt
 }
 
+// parseForStmt 解析for循环语句
+func (p *Parser) parseForStmt() *ast.ForStatement {
+	token := *p.token                               // for
+	lParen := p.MustAcceptTokenByType(lexer.LPAREN) // (
+	init := p.parseForInit()                        // 初始化语句
+
+	// 循环条件（可选）
+	var condition *ast.Exp
+	semicolon, noCondition := p.OptionalAcceptTokenByType(lexer.SEMICOLON)
+	if !noCondition {
+		condition = p.parseExp()
+		semicolon = p.MustAcceptTokenByType(lexer.SEMICOLON)
+	}
+
+	// 步进语句（可选）
+	var step *ast.ForStep
+	rParen, noStep := p.OptionalAcceptTokenByType(lexer.RPAREN)
+	if !noStep {
+		step = p.parseForStep()
+		rParen = p.MustAcceptTokenByType(lexer.RPAREN)
+	}
+
+	stmt := p.parseStmt() // 循环体
+
+	forStmt, _ := ast.NewForStatement(token, lParen, init, condition, semicolon, step, rParen, stmt)
+	return &forStmt
+}
+
+// parseForInit 解析for循环的初始化语句：变量声明、赋值语句或空语句
+func (p *Parser) parseForInit() ast.Statement {
+	token := p.MustAcceptTokenByFunc(func(token lexer.Token) bool {
+		return ast.IsID(token) || ast.IsType(token) || token.Type == lexer.SEMICOLON
+	}, append([]string{"identifier", "type"}, typeNames(lexer.SEMICOLON)...)...)
+	p.token = &token
+
+	switch {
+	case ast.IsID(token) && !ast.IsID(p.PeekToken()):
+		// 赋值语句
+		return ast.Statement{
+			Statement: p.parseAssignStmt(),
+			Type:      ast.ASSIGNMENTSTATEMENT,
+		}
+	case isVariableType(token):
+		// 变量声明语句
+		return ast.Statement{
+			Statement: p.parseLocalVariableDeclarationStmt(),
+			Type:      ast.LOCALVARIABLEDECLARATION,
+		}
+	default:
+		// 空语句
+		return ast.Statement{}
+	}
+}
+
+// parseForStep 解析for循环的步进语句
+func (p *Parser) parseForStep() *ast.ForStep {
+	id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) // 变量名
+	index, selectors := p.parseLValue()                       // 数组下标或结构体字段
+	assign := p.MustAcceptTokenByType(lexer.ASSIGN)           // =
+	exp := p.parseExp()                                       // 表达式
+
+	step, _ := ast.NewForStep(id, index, selectors, assign, *exp)
+	return &step
+}
+
+// parseDoWhileStmt 解析do-while循环语句
+func (p *Parser) parseDoWhileStmt() *ast.DoWhileStatement {
+	token := *p.token                                     // do
+	stmt := p.parseStmt()                                 // 循环体
+	while := p.MustAcceptTokenByType(lexer.WHILE)         // while
+	lParen := p.MustAcceptTokenByType(lexer.LPAREN)       // (
+	condition := p.parseExp()                             // 条件表达式
+	rParen := p.MustAcceptTokenByType(lexer.RPAREN)       // )
+	semicolon := p.MustAcceptTokenByType(lexer.SEMICOLON) // ;
+
+	doWhileStmt, _ := ast.NewDoWhileStatement(token, stmt, while, lParen, *condition, rParen, semicolon)
+	return &doWhileStmt
+}
+
+// parseSwitchStmt 解析switch语句
+func (p *Parser) parseSwitchStmt() *ast.SwitchStatement {
+	token := *p.token                               // switch
+	lParen := p.MustAcceptTokenByType(lexer.LPAREN) // (
+	exp := p.parseExp()                             // 被匹配的表达式
+	rParen := p.MustAcceptTokenByType(lexer.RPAREN) // )
+	lBrace := p.MustAcceptTokenByType(lexer.LBRACE) // {
+
+	// 不断解析case及default分支，直到右大括号
+	cases := make([]ast.SwitchCase, 0)
+	for {
+		caseToken, isCase := p.OptionalAcceptTokenByFunc(func(token lexer.Token) bool {
+			return token.Type == lexer.CASE || token.Type == lexer.DEFAULT
+		}, typeNames(lexer.CASE, lexer.DEFAULT)...)
+		if !isCase {
+			break
+		}
+		// case之后为整数或字符常量
+		var value *lexer.Token
+		if caseToken.Type == lexer.CASE {
+			constant := p.MustAcceptTokenByFunc(func(token lexer.Token) bool {
+				return token.Type == lexer.INTEGER_LITERAL || token.Type == lexer.CHAR_LITERAL
+			}, typeNames(lexer.INTEGER_LITERAL, lexer.CHAR_LITERAL)...)
+			value = &constant
+		}
+		colon := p.MustAcceptTokenByType(lexer.COLON) // :
+		statements := p.parseCaseStmtList()           // 分支中的语句
+
+		switchCase, _ := ast.NewSwitchCase(caseToken, value, colon, statements)
+		cases = append(cases, switchCase)
+	}
+	rBrace := p.MustAcceptTokenByType(lexer.RBRACE) // }
+
+	stmt, _ := ast.NewSwitchStatement(token, lParen, *exp, rParen, lBrace, cases, rBrace)
+	return &stmt
+}
+
+// parseCaseStmtList 解析case分支中的语句，直到下一个分支或switch语句结束
+func (p *Parser) parseCaseStmtList() []ast.Statement {
+	statements := make([]ast.Statement, 0)
+	for {
+		// 遇到下一个分支、右大括号或方法头时结束，由parseSwitchStmt继续解析
+		switch p.PeekToken().Type {
+		case lexer.CASE, lexer.DEFAULT, lexer.RBRACE:
+			return statements
+		}
+		if p.isMethodHeader() {
+			return statements
+		}
+		statement, ok := p.parseStmtRecover()
+		if !ok || statement == (ast.Statement{}) {
+			// 语句出错已同步到语句结束，或者为空语句，继续解析
+			continue
+		}
+		statements = append(statements, statement)
+	}
+}
+
 // parseExpStmt 解析表达式语句
 func (p *Parser) parseAssignStmt() *ast.AssignmentStatement {
 	token := *p.token                                     // id
+	index, selectors := p.parseLValue()                   // 数组下标或结构体字段
 	equal := p.MustAcceptTokenByType(lexer.ASSIGN)        // =
 	exp := p.parseExp()                                   // 表达式
 	semicolon := p.MustAcceptTokenByType(lexer.SEMICOLON) // ;
 
-	stmt, _ := ast.NewAssignmentStatement(ast.ID(token), equal, *exp, semicolon)
+	stmt, _ := ast.NewAssignmentStatement(ast.ID(token), index, selectors, equal, *exp, semicolon)
 	return &stmt
 }
 
@@ -331,10 +654,12 @@ func (p *Parser) parseAssignStmt() *ast.AssignmentStatement {
 func (p *Parser) parseLocalVariableDeclarationStmt() *ast.LocalVariableDeclaration {
 	token := *p.token                                         // 类型
 	id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) // 变量名
+	dim := p.parseArrayDim()                                  // 数组长度
+	initializer := p.parseInitializer()                       // 初始值
 
-	commaIDPair := make([]any, 0) // 逗号-变量名对
+	commaIDPair := make([]any, 0) // 逗号-变量名-数组长度-初始值
 
-	// 不断解析逗号-变量名对
+	// 不断解析逗号-变量名-数组长度-初始值
 	for {
 		// 检查是否有逗号
 		comma, isComma := p.OptionalAcceptTokenByType(lexer.COMMA)
@@ -343,60 +668,121 @@ func (p *Parser) parseLocalVariableDeclarationStmt() *ast.LocalVariableDeclarati
 			// 解析分号
 			semicolon := p.MustAcceptTokenByType(lexer.SEMICOLON)
 
-			paramList, _ := ast.NewLocalVariableDeclarationStatement(ast.Type(token), id, commaIDPair, semicolon)
+			paramList, _ := ast.NewLocalVariableDeclarationStatement(ast.Type(token), id, dim, initializer, commaIDPair, semicolon)
 			return &paramList
 		}
 		// 解析变量名
 		id := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER))
 		// 添加逗号-变量名对到commaIDPair列表
 		commaIDPair = append(commaIDPair, comma, id)
+		// 解析数组长度
+		if dim := p.parseArrayDim(); dim != nil {
+			commaIDPair = append(commaIDPair, dim)
+		}
+		// 解析初始值
+		if initializer := p.parseInitializer(); initializer != nil {
+			commaIDPair = append(commaIDPair, initializer)
+		}
 	}
 }
 
-// parseExp 解析表达式
-func (p *Parser) parseExp() *ast.Exp {
-	// 左项
-	lTerm := p.parseTerm()
-	// 检查是否有加号或减号
-	plusOrMinus, isPlusOrMinus := p.OptionalAcceptTokenByType(lexer.PLUS, lexer.MINUS)
-	if !isPlusOrMinus {
-		// 没有加号或减号，结束
-		exp, _ := ast.NewExp(*lTerm, nil, nil)
-		return &exp
+// parseArrayDim 解析数组的长度声明，不是数组时返回nil
+func (p *Parser) parseArrayDim() *ast.ArrayDim {
+	// 检查是否有左方括号
+	lBracket, isArray := p.OptionalAcceptTokenByType(lexer.LBRACKET)
+	if !isArray {
+		return nil
 	}
-	// 有加减号，解析右项
-	rTerm := p.parseTerm()
+	length := p.MustAcceptTokenByType(lexer.INTEGER_LITERAL) // 数组长度
+	rBracket := p.MustAcceptTokenByType(lexer.RBRACKET)      // ]
+
+	dim, _ := ast.NewArrayDim(lBracket, length, rBracket)
+	return &dim
+}
+
+// parseIndex 解析数组下标，没有下标时返回nil
+func (p *Parser) parseIndex() *ast.Index {
+	// 检查是否有左方括号
+	lBracket, isIndex := p.OptionalAcceptTokenByType(lexer.LBRACKET)
+	if !isIndex {
+		return nil
+	}
+	exp := p.parseExp()                                 // 下标表达式
+	rBracket := p.MustAcceptTokenByType(lexer.RBRACKET) // ]
+
+	index, _ := ast.NewIndex(lBracket, *exp, rBracket)
+	return &index
+}
+
+// parseLValue 解析赋值目标中变量名之后的数组下标或结构体字段，都没有时返回nil
+func (p *Parser) parseLValue() (*ast.Index, []ast.Selector) {
+	if index := p.parseIndex(); index != nil {
+		return index, nil
+	}
+	return nil, p.parseSelectors()
+}
+
+// parseSelectors 解析结构体字段序列，没有字段时返回nil
+func (p *Parser) parseSelectors() []ast.Selector {
+	var selectors []ast.Selector
+	for {
+		// 检查是否有点号
+		dot, isSelector := p.OptionalAcceptTokenByType(lexer.DOT)
+		if !isSelector {
+			return selectors
+		}
+		field := (ast.ID)(p.MustAcceptTokenByType(lexer.IDENTIFIER)) // 字段名
 
-	exp, _ := ast.NewExp(*lTerm, &plusOrMinus, rTerm)
-	return &exp
+		selector, _ := ast.NewSelector(dot, field)
+		selectors = append(selectors, selector)
+	}
 }
 
-// parseTerm 解析项
-func (p *Parser) parseTerm() *ast.Term {
-	// 左因子
-	lFactor := p.parseFactor()
-	// 检查是否有乘号或除号
-	mulOrDiv, isMulOrDiv := p.OptionalAcceptTokenByType(lexer.TIMES, lexer.DIVIDE)
-	if !isMulOrDiv {
-		// 没有乘号或除号，结束
-		term, _ := ast.NewTerm(*lFactor, nil, nil)
-		return &term
+// parseInitializer 解析变量的初始值，没有初始值时返回nil
+func (p *Parser) parseInitializer() *ast.Initializer {
+	// 检查是否有等号
+	assign, hasInit := p.OptionalAcceptTokenByType(lexer.ASSIGN)
+	if !hasInit {
+		return nil
 	}
-	// 有乘除号，解析右因子
-	rFactor := p.parseFactor()
+	exp := p.parseExp() // 初始值表达式
 
-	term, _ := ast.NewTerm(*lFactor, &mulOrDiv, rFactor)
-	return &term
+	initializer, _ := ast.NewInitializer(assign, *exp)
+	return &initializer
 }
 
 // parseFactor 解析因子
 func (p *Parser) parseFactor() *ast.Factor {
-	// 判断单token或(Exp)
+	// 判断单token、(Exp)、方法调用或数组元素
 	token := p.MustAcceptTokenByFunc(func(token lexer.Token) bool {
-		return ast.IsID(token) || token.Type == lexer.INTEGER_LITERAL || token.Type == lexer.DECIMAL_LITERAL || token.Type == lexer.LPAREN
-	})
+		return ast.IsID(token) || isLiteral(token) || token.Type == lexer.LPAREN
+	}, typeNames(lexer.IDENTIFIER, lexer.INTEGER_LITERAL, lexer.DECIMAL_LITERAL, lexer.STRING_LITERAL, lexer.CHAR_LITERAL, lexer.LPAREN)...)
+
+	if ast.IsID(token) {
+		// 标识符之后为左括号，是方法调用
+		if lParen, isCall := p.OptionalAcceptTokenByType(lexer.LPAREN); isCall {
+			// ID(ActParamList)
+			actParamList := p.parseActParamList()           // 实参列表
+			rParen := p.MustAcceptTokenByType(lexer.RPAREN) // )
+
+			factor, _ := ast.NewFactor(ast.ID(token), lParen, actParamList, rParen)
+			return &factor
+		}
+		// 标识符之后为左方括号，是数组元素
+		if index := p.parseIndex(); index != nil {
+			// ID[Exp]
+			factor, _ := ast.NewFactor(ast.ID(token), *index)
+			return &factor
+		}
+		// 标识符之后为点号，是结构体字段
+		if selectors := p.parseSelectors(); selectors != nil {
+			// ID.ID
+			factor, _ := ast.NewFactor(ast.ID(token), selectors)
+			return &factor
+		}
+	}
 
-	if token.Type == lexer.INTEGER_LITERAL || token.Type == lexer.DECIMAL_LITERAL || ast.IsID(token) {
+	if isLiteral(token) || ast.IsID(token) {
 		// 单token
 		factor, _ := ast.NewFactor(token)
 		return &factor
@@ -409,68 +795,27 @@ func (p *Parser) parseFactor() *ast.Factor {
 		return &factor
 	} else {
 		// 未知token
-		p.Errorf("Unexpected token %s", token.String())
+		p.ErrorToken(token, "unexpected %s", token.Describe())
 		return nil
 	}
 }
 
-// parseConditionalExp 解析条件表达式
-func (p *Parser) parseConditionalExp() *ast.ConditionalExp {
-	// 左关系表达式
-	lRelationExp := p.parseRelationExp()
-	// 检查是否有or
-	or, hasOr := p.OptionalAcceptTokenByType(lexer.OR)
-	if !hasOr {
-		// 没有or，结束
-		conditionalExp, _ := ast.NewConditionalExp(*lRelationExp, nil, nil)
-		return &conditionalExp
-	}
-
-	// 有or，解析右关系表达式
-	rRelationExp := p.parseRelationExp()
-	conditionalExp, _ := ast.NewConditionalExp(*lRelationExp, &or, rRelationExp)
-
-	return &conditionalExp
-}
-
-// parseRelationExp 解析关系表达式
-func (p *Parser) parseRelationExp() *ast.RelationExp {
-	// 左比较表达式
-	lCompExp := p.parseCompExp()
-	// 检查是否有and
-	and, hasAnd := p.OptionalAcceptTokenByType(lexer.AND)
-	if !hasAnd {
-		// 没有and，结束
-		relationExp, _ := ast.NewRelationExp(*lCompExp, nil, nil)
-		return &relationExp
+// isLiteral 判断是否为整数、小数、字符串或字符字面量
+func isLiteral(token lexer.Token) bool {
+	switch token.Type {
+	case lexer.INTEGER_LITERAL, lexer.DECIMAL_LITERAL, lexer.STRING_LITERAL, lexer.CHAR_LITERAL:
+		return true
 	}
-
-	// 有and，解析右比较表达式
-	rCompExp := p.parseCompExp()
-	relationExp, _ := ast.NewRelationExp(*lCompExp, &and, rCompExp)
-
-	return &relationExp
-}
-
-// parseCompExp 解析比较表达式
-func (p *Parser) parseCompExp() *ast.CompExp {
-	// 左表达式
-	lExp := p.parseExp()
-	// 比较运算符
-	cmpOp := p.MustAcceptTokenByType(lexer.LESS, lexer.LESSEQUAL, lexer.GREATER, lexer.GREATEREQUAL, lexer.EQUAL, lexer.DIAMOND)
-	// 右表达式
-	rExp := p.parseExp()
-
-	compExp, _ := ast.NewCompExp(*lExp, ast.CmpOp(cmpOp), *rExp)
-	return &compExp
+	return false
 }
 
 // parseActParamList 解析实参列表
 func (p *Parser) parseActParamList() *ast.ActParamList {
 	// 检查是否有实参
 	_, hasExp := p.OptionalAcceptTokenByFunc(func(token lexer.Token) bool {
-		return ast.IsID(token) || token.Type == lexer.INTEGER_LITERAL || token.Type == lexer.DECIMAL_LITERAL || token.Type == lexer.LPAREN
-	})
+		_, isPrefix := findOperator(prefixOperators, token.Type)
+		return isPrefix || ast.IsID(token) || isLiteral(token) || token.Type == lexer.LPAREN
+	}, append(typeNames(lexer.IDENTIFIER, lexer.INTEGER_LITERAL, lexer.DECIMAL_LITERAL, lexer.STRING_LITERAL, lexer.CHAR_LITERAL, lexer.LPAREN), operatorNames(prefixOperators, powerLowest)...)...)
 	if !hasExp {
 		// 没有实参，结束
 		actParamList, _ := ast.NewActParamList(nil)
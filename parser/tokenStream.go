@@ -3,39 +3,134 @@ package parser
 import (
 	"CompilerInGo/lexer"
 	"CompilerInGo/utils"
-	"github.com/kpango/glg"
-	"runtime/debug"
+)
+
+// 流式模式下的缓冲区参数
+const (
+	tokenChanSize   = 256  // Lexer到Parser的Token通道容量
+	tokenLookBehind = 16   // 缓冲区中保留的已读Token数量，供UnreadToken使用
+	tokenCompactAt  = 1024 // 已读Token达到该数量时压缩缓冲区
 )
 
 // TokenStream Token流
 type TokenStream struct {
-	pool  lexer.TokenPool
-	pos   int
-	width int
+	buffer []lexer.Token      // Token缓冲区
+	pos    int                // 当前位置
+	width  int                // 上一次读取的宽度
+	source <-chan lexer.Token // 流式模式下的Token来源，为nil时缓冲区即为全部Token
+	done   chan struct{}      // 流式模式下通知Lexer停止扫描
+
+	// 在同一Token处尝试失败的可选Token种类，出错时与必须的Token种类一起列出
+	expectedAt utils.Position
+	expected   []string
+}
+
+// NewTokenStream 创建一个读取tokens的Token流，tokens只读不修改
+func NewTokenStream(tokens []lexer.Token) TokenStream {
+	return TokenStream{
+		buffer: tokens,
+		pos:    0,
+		width:  0,
+	}
 }
 
-// NewTokenStream 创建一个新的Token流
-func NewTokenStream() TokenStream {
+// NewTokenStreamFromLexer 创建一个流式Token流
+// Lexer在单独的goroutine中扫描，Token经过有界通道按需传给Parser，
+// Parser无需等待扫描结束，也不需要保存全部Token
+func NewTokenStreamFromLexer(lex *lexer.Lexer) TokenStream {
+	source := make(chan lexer.Token, tokenChanSize)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(source)
+		for {
+			// IfTokenError 检查Token是否出错，若出错则输出错误信息并跳过
+			token := lex.IfTokenError(lex.ScanToken())
+			select {
+			case source <- token:
+			case <-done:
+				// Parser已结束，停止扫描
+				return
+			}
+			if token.Category == lexer.EOF {
+				return
+			}
+		}
+	}()
+
 	return TokenStream{
-		pool:  *lexer.Pool,
-		pos:   0,
-		width: 0,
+		buffer: make([]lexer.Token, 0, tokenCompactAt+tokenLookBehind),
+		source: source,
+		done:   done,
+	}
+}
+
+// Close 关闭Token流，流式模式下通知Lexer停止扫描并等待其结束
+func (ts *TokenStream) Close() {
+	if ts.done != nil {
+		close(ts.done)
+		ts.done = nil
+	}
+	// 等待Lexer结束，此后可以安全读取Lexer的状态
+	if ts.source != nil {
+		for range ts.source {
+		}
+		ts.source = nil
+	}
+}
+
+// fill 保证缓冲区中存在位置为index的Token
+// 返回false表示Token已经读完
+func (ts *TokenStream) fill(index int) bool {
+	for index >= len(ts.buffer) {
+		if ts.source == nil {
+			return false
+		}
+		token, ok := <-ts.source
+		if !ok {
+			// Lexer已结束
+			ts.source = nil
+			return false
+		}
+		ts.buffer = append(ts.buffer, token)
+	}
+	return true
+}
+
+// compact 丢弃缓冲区中已读且不再需要回退的Token
+func (ts *TokenStream) compact() {
+	// 非流式模式下缓冲区为共享的Token池，不能修改
+	if ts.done == nil || ts.pos < tokenCompactAt {
+		return
 	}
+	drop := ts.pos - tokenLookBehind
+	// 不从中间截断连续的空格和注释，保证LeadingComments能获取完整的注释
+	for drop > 0 && isSkipped(ts.buffer[drop-1]) {
+		drop--
+	}
+	n := copy(ts.buffer, ts.buffer[drop:])
+	ts.buffer = ts.buffer[:n]
+	ts.pos -= drop
 }
 
 // ReadToken 读取一个Token
 func (ts *TokenStream) ReadToken() lexer.Token {
+	ts.compact()
 	// 越界，返回EOF
-	if ts.pos >= len(ts.pool.Pool) {
+	if !ts.fill(ts.pos) {
 		ts.width = 0
 		return lexer.NewToken("EOF_LITERAL", utils.PositionPair{}, lexer.EOF_LITERAL)
 	}
 	// 读取Token
-	token := ts.pool.Pool[ts.pos]
+	token := ts.buffer[ts.pos]
 	// 跳过空格和注释
-	for token.Type == lexer.SPACE || token.Type == lexer.SINGLELINE_COMMENT_LITERAL || token.Type == lexer.MULTILINE_COMMENT_LITERAL {
+	for isSkipped(token) {
 		ts.pos++
-		token = ts.pool.Pool[ts.pos]
+		if !ts.fill(ts.pos) {
+			ts.width = 0
+			return lexer.NewToken("EOF_LITERAL", utils.PositionPair{}, lexer.EOF_LITERAL)
+		}
+		token = ts.buffer[ts.pos]
 	}
 	// 更新宽度
 	ts.width = 1
@@ -45,6 +140,38 @@ func (ts *TokenStream) ReadToken() lexer.Token {
 	return token
 }
 
+// isSkipped 判断Token是否为读取时跳过的空格或注释
+func isSkipped(token lexer.Token) bool {
+	return token.Type == lexer.SPACE || token.Category == lexer.COMMENT
+}
+
+// LeadingComments 获取上一次读取的Token之前紧邻的注释Token
+// 与之前的Token位于同一行的行尾注释不包括在内
+func (ts *TokenStream) LeadingComments() []lexer.Token {
+	comments := make([]lexer.Token, 0)
+	if ts.width == 0 {
+		// 上一次读取到EOF
+		return comments
+	}
+
+	// 从上一次读取的Token向前查找
+	i := ts.pos - ts.width - 1
+	for ; i >= 0 && isSkipped(ts.buffer[i]); i-- {
+		if ts.buffer[i].Category == lexer.COMMENT {
+			comments = append([]lexer.Token{ts.buffer[i]}, comments...)
+		}
+	}
+
+	// 去掉与之前的Token位于同一行的行尾注释
+	if i >= 0 {
+		for len(comments) > 0 && comments[0].Pos.Begin.Row == ts.buffer[i].Pos.End.Row {
+			comments = comments[1:]
+		}
+	}
+
+	return comments
+}
+
 // UnreadToken 回退一个Token
 func (ts *TokenStream) UnreadToken() {
 	ts.pos -= ts.width
@@ -57,6 +184,49 @@ func (ts *TokenStream) PeekToken() lexer.Token {
 	return token
 }
 
+// PeekTokenN 预读之后的第n个Token（从0开始），不移动当前位置
+func (ts *TokenStream) PeekTokenN(n int) lexer.Token {
+	for index := ts.pos; ts.fill(index); index++ {
+		// 跳过空格和注释
+		if isSkipped(ts.buffer[index]) {
+			continue
+		}
+		if n == 0 {
+			return ts.buffer[index]
+		}
+		n--
+	}
+	// 越界，返回EOF
+	return lexer.NewToken("EOF_LITERAL", utils.PositionPair{}, lexer.EOF_LITERAL)
+}
+
+// expect 记录在token处尝试失败的Token种类
+// 读到新的位置时清空之前记录的种类
+func (ts *TokenStream) expect(token lexer.Token, expected ...string) {
+	if token.Pos.Begin != ts.expectedAt {
+		ts.expectedAt = token.Pos.Begin
+		ts.expected = ts.expected[:0]
+	}
+	for _, e := range expected {
+		if !contains(ts.expected, e) {
+			ts.expected = append(ts.expected, e)
+		}
+	}
+}
+
+// expectError 创建token处的语法错误，期望的Token种类包括expected及在该处尝试失败的可选Token种类
+func (ts *TokenStream) expectError(token lexer.Token, expected ...string) *SyntaxError {
+	all := append([]string{}, expected...)
+	if token.Pos.Begin == ts.expectedAt {
+		for _, e := range ts.expected {
+			if !contains(all, e) {
+				all = append(all, e)
+			}
+		}
+	}
+	return newExpectError(token, all...)
+}
+
 // AcceptTokenByType 读取一个指定类型的Token
 // 如果读取到的Token不是指定类型，那么回退Token并返回错误
 func (ts *TokenStream) AcceptTokenByType(exceptedType ...lexer.TokenType) (lexer.Token, error) {
@@ -66,47 +236,39 @@ func (ts *TokenStream) AcceptTokenByType(exceptedType ...lexer.TokenType) (lexer
 			return token, nil
 		}
 	}
-	var str string
-	for _, t := range exceptedType {
-		str += lexer.TokenTypeString[t] + " "
-	}
 	ts.UnreadToken()
-	return token, utils.NewErrorf("Expect token type %s, but got \"%s\", at %d:%d to %d:%d", str, token.Literal, token.Pos.Begin.Row, token.Pos.Begin.Col, token.Pos.End.Row, token.Pos.End.Col)
+	return token, ts.expectError(token, typeNames(exceptedType...)...)
 }
 
 // AcceptTokenByFunc 读取一个满足条件的Token
+// expected为条件所接受的Token种类，用于错误信息
 // 如果读取到的Token不满足条件，那么回退Token并返回错误
-func (ts *TokenStream) AcceptTokenByFunc(f func(token lexer.Token) bool) (lexer.Token, error) {
+func (ts *TokenStream) AcceptTokenByFunc(f func(token lexer.Token) bool, expected ...string) (lexer.Token, error) {
 	token := ts.ReadToken()
 	if f(token) {
 		return token, nil
 	}
 	ts.UnreadToken()
-	return token, utils.NewErrorf("Token \"%s\" does not meet the expectation, at %d:%d to %d:%d", token.Literal, token.Pos.Begin.Row, token.Pos.Begin.Col, token.Pos.End.Row, token.Pos.End.Col)
+	return token, ts.expectError(token, expected...)
 }
 
 // MustAcceptTokenByType 必须满足指定类型的Token
-// 如果读取到的Token不是指定类型，那么直接退出程序
+// 如果读取到的Token不是指定类型，那么回退Token并以语法错误panic，由Parser的错误恢复捕获
 func (ts *TokenStream) MustAcceptTokenByType(exceptedType ...lexer.TokenType) lexer.Token {
 	token, err := ts.AcceptTokenByType(exceptedType...)
-	var str string
-	for _, t := range exceptedType {
-		str += lexer.TokenTypeString[t] + " "
-	}
 	if err != nil {
-		debug.PrintStack()
-		glg.Fatalf("Expect token type %s, but got \"%s\", at %d:%d to %d:%d", str, token.Literal, token.Pos.Begin.Row, token.Pos.Begin.Col, token.Pos.End.Row, token.Pos.End.Col)
+		panic(err)
 	}
 	return token
 }
 
 // MustAcceptTokenByFunc 必须满足条件的Token
-// 如果读取到的Token不满足条件，那么直接退出程序
-func (ts *TokenStream) MustAcceptTokenByFunc(f func(token lexer.Token) bool) lexer.Token {
-	token, err := ts.AcceptTokenByFunc(f)
+// expected为条件所接受的Token种类，用于错误信息
+// 如果读取到的Token不满足条件，那么回退Token并以语法错误panic，由Parser的错误恢复捕获
+func (ts *TokenStream) MustAcceptTokenByFunc(f func(token lexer.Token) bool, expected ...string) lexer.Token {
+	token, err := ts.AcceptTokenByFunc(f, expected...)
 	if err != nil {
-		debug.PrintStack()
-		glg.Fatalf("Token \"%s\" does not meet the expectation, at %d:%d to %d:%d", token.Literal, token.Pos.Begin.Row, token.Pos.Begin.Col, token.Pos.End.Row, token.Pos.End.Col)
+		panic(err)
 	}
 	return token
 }
@@ -121,16 +283,19 @@ func (ts *TokenStream) OptionalAcceptTokenByType(exceptedType ...lexer.TokenType
 		}
 	}
 	ts.UnreadToken()
+	ts.expect(token, typeNames(exceptedType...)...)
 	return token, false
 }
 
 // OptionalAcceptTokenByFunc 可选满足条件的Token
+// expected为条件所接受的Token种类，用于错误信息
 // 如果读取到的Token不满足条件，那么回退Token并返回false
-func (ts *TokenStream) OptionalAcceptTokenByFunc(f func(token lexer.Token) bool) (lexer.Token, bool) {
+func (ts *TokenStream) OptionalAcceptTokenByFunc(f func(token lexer.Token) bool, expected ...string) (lexer.Token, bool) {
 	token := ts.ReadToken()
 	if f(token) {
 		return token, true
 	}
 	ts.UnreadToken()
+	ts.expect(token, expected...)
 	return token, false
 }
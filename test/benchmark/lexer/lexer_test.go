@@ -11,18 +11,8 @@ func BenchmarkLexerLong(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		lex := lexer.NewLexer("../../long.program")
-		// 初始化token池
-		tokenPool := lexer.NewTokenPool()
-
-		// 读取第一个Token
-		// IfTokenError 检查Token是否出错，若出错则输出错误信息并退出程序
-		token := lexer.IfTokenError(lex.ScanToken())
-		tokenPool.PushBack(token)
-		// 若未读到EOF则继续读取
-		for tokenPool.Last().Category != lexer.EOF {
-			token := lexer.IfTokenError(lex.ScanToken())
-			tokenPool.PushBack(token)
-		}
+		// 扫描全部Token
+		lex.Tokenize()
 	}
 }
 
@@ -31,17 +21,33 @@ func BenchmarkLexer(b *testing.B) {
 
 	for i := 0; i < b.N; i++ {
 		lex := lexer.NewLexer("../../sample1.program")
-		// 初始化token池
-		tokenPool := lexer.NewTokenPool()
-
-		// 读取第一个Token
-		// IfTokenError 检查Token是否出错，若出错则输出错误信息并退出程序
-		token := lexer.IfTokenError(lex.ScanToken())
-		tokenPool.PushBack(token)
-		// 若未读到EOF则继续读取
-		for tokenPool.Last().Category != lexer.EOF {
-			token := lexer.IfTokenError(lex.ScanToken())
-			tokenPool.PushBack(token)
-		}
+		// 扫描全部Token
+		lex.Tokenize()
+	}
+}
+
+func BenchmarkTableLexerLong(b *testing.B) {
+	utils.InitLogger("CLOSE")
+
+	for i := 0; i < b.N; i++ {
+		lex := lexer.NewTableLexer("../../long.program")
+		// 扫描全部Token
+		lex.Tokenize()
+	}
+}
+
+func BenchmarkTableLexer(b *testing.B) {
+	utils.InitLogger("CLOSE")
+
+	for i := 0; i < b.N; i++ {
+		lex := lexer.NewTableLexer("../../sample1.program")
+		// 扫描全部Token
+		lex.Tokenize()
+	}
+}
+
+func BenchmarkCompileSpec(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		_, _ = lexer.CompileSpec(lexer.Spec, lexer.Definitions)
 	}
 }
@@ -12,21 +12,11 @@ func BenchmarkParserLong(b *testing.B) {
 	utils.InitLogger("CLOSE")
 
 	lex := lexer.NewLexer("../../long.program")
-	// 初始化token池
-	tokenPool := lexer.NewTokenPool()
-
-	// 读取第一个Token
-	// IfTokenError 检查Token是否出错，若出错则输出错误信息并退出程序
-	token := lexer.IfTokenError(lex.ScanToken())
-	tokenPool.PushBack(token)
-	// 若未读到EOF则继续读取
-	for tokenPool.Last().Category != lexer.EOF {
-		token := lexer.IfTokenError(lex.ScanToken())
-		tokenPool.PushBack(token)
-	}
+	// 扫描全部Token，作为Parser的输入
+	tokens := lex.Tokenize()
 
 	for i := 0; i < b.N; i++ {
-		pser := parser.NewParser()
+		pser := parser.NewParserFromTokens(tokens.Pool)
 		_, err := pser.Parse()
 		if err != nil {
 			glg.Fatal(err)
@@ -38,21 +28,44 @@ func BenchmarkParser(b *testing.B) {
 	utils.InitLogger("CLOSE")
 
 	lex := lexer.NewLexer("../../sample1.program")
-	// 初始化token池
-	tokenPool := lexer.NewTokenPool()
-
-	// 读取第一个Token
-	// IfTokenError 检查Token是否出错，若出错则输出错误信息并退出程序
-	token := lexer.IfTokenError(lex.ScanToken())
-	tokenPool.PushBack(token)
-	// 若未读到EOF则继续读取
-	for tokenPool.Last().Category != lexer.EOF {
-		token := lexer.IfTokenError(lex.ScanToken())
-		tokenPool.PushBack(token)
+	// 扫描全部Token，作为Parser的输入
+	tokens := lex.Tokenize()
+
+	for i := 0; i < b.N; i++ {
+		pser := parser.NewParserFromTokens(tokens.Pool)
+		_, err := pser.Parse()
+		if err != nil {
+			glg.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkParserLongBatch(b *testing.B) {
+	utils.InitLogger("CLOSE")
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		// 先扫描全部Token，再进行语法分析
+		lex := lexer.NewLexer("../../long.program")
+		tokens := lex.Tokenize()
+
+		pser := parser.NewParserFromTokens(tokens.Pool)
+		_, err := pser.Parse()
+		if err != nil {
+			glg.Fatal(err)
+		}
 	}
+}
+
+func BenchmarkParserLongStream(b *testing.B) {
+	utils.InitLogger("CLOSE")
+	b.ReportAllocs()
 
 	for i := 0; i < b.N; i++ {
-		pser := parser.NewParser()
+		// 词法分析与语法分析流水线执行
+		lex := lexer.NewLexer("../../long.program")
+
+		pser := parser.NewParserFromLexer(lex)
 		_, err := pser.Parse()
 		if err != nil {
 			glg.Fatal(err)
@@ -0,0 +1,92 @@
+package doc
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/doc"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"strings"
+	"testing"
+)
+
+const source = `/**
+ * Returns a constant.
+ *
+ * Used by test.
+ */
+int avd(int c){
+    return 45;
+} // trailing comment of avd
+
+// Adds b to a.
+//   Calls avd.
+int test(int a,int b){
+    a=a+b;
+    call avd(65);
+    return 33+56;
+}
+
+// not a doc comment: a blank line follows
+
+int main(){
+    call test(56,78);
+    return 0;
+}
+`
+
+func TestDocComment(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	program, err := parser.ParseSource("test", source)
+	if err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+
+	// 方法的文档注释
+	var docCase = map[string]string{
+		"avd":  "Returns a constant.\n\nUsed by test.",
+		"test": "Adds b to a.\n  Calls avd.",
+		"main": "",
+	}
+
+	for _, method := range program.Method {
+		if v := docCase[method.GetMethodName()]; method.Doc != v {
+			t.Error("Doc comment failed")
+			t.Error("Method: ", method.GetMethodName())
+			t.Errorf("Expected: %q", v)
+			t.Errorf("Actual: %q", method.Doc)
+		}
+	}
+
+	// 文档注释随方法进入HIR
+	hirProgram, errs := analyser.NewAnalyser().Analyse(program)
+	if errs != 0 {
+		t.Fatal("Analyse failed with ", errs, " errors")
+	}
+	if method := hirProgram.GetMethod("avd"); method == nil || method.Doc != docCase["avd"] {
+		t.Error("HIR doc comment failed")
+	}
+
+	// 生成的文档包含方法签名、参数及文档注释
+	markdown := doc.Markdown(hirProgram, "test")
+	for _, v := range []string{"## avd", "int test(int a, int b)", "| `b` | `int` |", "Used by test.", "**Returns** `int`"} {
+		if !strings.Contains(markdown, v) {
+			t.Error("Markdown doc failed")
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", markdown)
+		}
+	}
+	html := doc.HTML(hirProgram, "test")
+	for _, v := range []string{"<h2 id=\"test\">test</h2>", "<pre><code>int avd(int c)</code></pre>", "Adds b to a."} {
+		if !strings.Contains(html, v) {
+			t.Error("HTML doc failed")
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", html)
+		}
+	}
+	if strings.Contains(markdown, "not a doc comment") || strings.Contains(markdown, "trailing comment") {
+		t.Error("Doc comment failed")
+		t.Error("Actual: ", markdown)
+	}
+}
@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"CompilerInGo/utils"
+	"errors"
 	"testing"
 )
 
@@ -24,6 +25,12 @@ func TestEscapeRune(t *testing.T) {
 		'@': "@",
 		'#': "#",
 		'$': "$",
+
+		0:        "\\0",
+		'\x01':   "\\x01",
+		'\x7f':   "\\x7F",
+		'中':      "中",
+		'\u200b': "\\u{200B}",
 	}
 
 	for k, v := range rightCase {
@@ -61,3 +68,54 @@ func TestEscapeString(t *testing.T) {
 		}
 	}
 }
+
+func TestUnescapeString(t *testing.T) {
+	// Right Case
+	var rightCase = map[string]string{
+		"abc":                    "abc",
+		"a\\\"b":                 "a\"b",
+		"\\'\\\\\\n\\r\\t\\b\\f": "'\\\n\r\t\b\f",
+		"\\0":                    "\x00",
+		"\\x41\\x7f":             "A\x7f",
+		"\\u{4E2D}\\u{6587}":     "中文",
+		"\\u{1F600}":             "\U0001F600",
+		"\\u{10FFFF}":            "\U0010FFFF",
+		"中\\t文":                  "中\t文",
+	}
+
+	for k, v := range rightCase {
+		if res, err := utils.UnescapeString(k); res != v || err != nil {
+			t.Error("UnescapeString failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", res)
+			t.Error("Error: ", err)
+		}
+	}
+
+	// Wrong Case
+	var wrongCase = map[string]error{
+		"\\q":          utils.ErrUnknownEscape,
+		"a\\中":         utils.ErrUnknownEscape,
+		"\\x4":         utils.ErrMalformedEscape,
+		"\\xZZ":        utils.ErrMalformedEscape,
+		"\\x80":        utils.ErrInvalidCodePoint,
+		"\\u41":        utils.ErrMalformedEscape,
+		"\\u{}":        utils.ErrMalformedEscape,
+		"\\u{41":       utils.ErrMalformedEscape,
+		"\\u{1234567}": utils.ErrMalformedEscape,
+		"\\u{D800}":    utils.ErrInvalidCodePoint,
+		"\\u{110000}":  utils.ErrInvalidCodePoint,
+		"abc\\":        utils.ErrMalformedEscape,
+	}
+
+	for k, v := range wrongCase {
+		if res, err := utils.UnescapeString(k); !errors.Is(err, v) {
+			t.Error("UnescapeString failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", err)
+			t.Error("Actual Result: ", res)
+		}
+	}
+}
@@ -0,0 +1,384 @@
+package lexer
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/utils"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// scanTypes 扫描源程序，返回除空格外的Token类型序列
+func scanTypes(lex *lexer.Lexer) []lexer.TokenType {
+	types := make([]lexer.TokenType, 0)
+	for _, token := range lex.Tokenize().Pool {
+		if token.Type == lexer.SPACE {
+			continue
+		}
+		types = append(types, token.Type)
+	}
+	return types
+}
+
+func TestLexerFromReader(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// Right Case
+	var rightCase = map[string][]lexer.TokenType{
+		"int a;":             {lexer.INT, lexer.IDENTIFIER, lexer.SEMICOLON, lexer.EOF_LITERAL},
+		"a = 0x1F + 1.5e-3;": {lexer.IDENTIFIER, lexer.ASSIGN, lexer.INTEGER_LITERAL, lexer.PLUS, lexer.DECIMAL_LITERAL, lexer.SEMICOLON, lexer.EOF_LITERAL},
+		"while(a<>b)\n{}":    {lexer.WHILE, lexer.LPAREN, lexer.IDENTIFIER, lexer.DIAMOND, lexer.IDENTIFIER, lexer.RPAREN, lexer.LBRACE, lexer.RBRACE, lexer.EOF_LITERAL},
+		"":                   {lexer.EOF_LITERAL},
+	}
+
+	for k, v := range rightCase {
+		res := scanTypes(lexer.NewLexerFromReader("test", strings.NewReader(k)))
+		if len(res) != len(v) {
+			t.Error("Lexer failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", res)
+			continue
+		}
+		for i := range v {
+			if res[i] != v[i] {
+				t.Error("Lexer failed")
+				t.Error("Input: ", k)
+				t.Error("Expected: ", v)
+				t.Error("Actual: ", res)
+				break
+			}
+		}
+	}
+}
+
+func TestLexerUnicode(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// Unicode XID标识符
+	var identifierCase = map[string][]lexer.TokenType{
+		"变量 = a_1;":    {lexer.IDENTIFIER, lexer.ASSIGN, lexer.IDENTIFIER, lexer.SEMICOLON, lexer.EOF_LITERAL},
+		"café = ñ2;":   {lexer.IDENTIFIER, lexer.ASSIGN, lexer.IDENTIFIER, lexer.SEMICOLON, lexer.EOF_LITERAL},
+		"x\u0301;":     {lexer.IDENTIFIER, lexer.SEMICOLON, lexer.EOF_LITERAL},
+		"\uFEFFint a;": {lexer.INT, lexer.IDENTIFIER, lexer.SEMICOLON, lexer.EOF_LITERAL},
+	}
+
+	for k, v := range identifierCase {
+		lex := lexer.NewLexerFromReader("test", strings.NewReader(k))
+		if res := scanTypes(lex); !reflect.DeepEqual(res, v) || lex.HasErrors() {
+			t.Error("Lexer unicode failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", res, lex.Errors)
+		}
+	}
+
+	// 非XID_Start字符不能作为标识符开头
+	for _, v := range []string{"_a", "\u0301a", "·a"} {
+		lex := lexer.NewLexerFromReader("test", strings.NewReader(v))
+		lex.Tokenize()
+		if !lex.HasErrors() {
+			t.Error("Lexer unicode failed")
+			t.Error("Input: ", v)
+			t.Error("Expected: ", lexer.ILLEGAL_CHARACTER)
+		}
+	}
+
+	// BOM不占列，\r\n与单独的\r均为一个换行
+	lex := lexer.NewLexerFromReader("test", strings.NewReader("\uFEFFa\r\nb\rc\n\nd"))
+	pool := lex.Tokenize()
+	var positionCase = []struct{ row, col uint }{{1, 1}, {2, 1}, {3, 1}, {5, 1}}
+	for i, v := range positionCase {
+		if pos := pool.Get(i).Pos.Begin; pos.Row != v.row || pos.Col != v.col {
+			t.Error("Lexer position failed")
+			t.Error("Token: ", pool.Get(i))
+			t.Error("Expected: ", v.row, v.col)
+		}
+		// 字节偏移与行偏移表一致
+		if loc := lex.Lines().Location(int(pool.Get(i).Pos.Begin.Offset())); loc.Row != v.row || loc.RuneCol != v.col {
+			t.Error("Lexer line table failed")
+			t.Error("Token: ", pool.Get(i))
+			t.Error("Actual: ", loc)
+		}
+	}
+}
+
+func TestLexerIndependent(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// 两个词法分析器交替扫描，互不影响
+	lexA := lexer.NewLexerFromReader("a", strings.NewReader("int a;"))
+	lexB := lexer.NewLexerFromReader("b", strings.NewReader("float b;"))
+
+	for {
+		tokenA := lexA.IfTokenError(lexA.ScanToken())
+		tokenB := lexB.IfTokenError(lexB.ScanToken())
+		lexA.Tokens.PushBack(tokenA)
+		lexB.Tokens.PushBack(tokenB)
+		if tokenA.Category == lexer.EOF || tokenB.Category == lexer.EOF {
+			break
+		}
+	}
+
+	if lexA.Tokens.Get(0).Type != lexer.INT || lexB.Tokens.Get(0).Type != lexer.FLOAT {
+		t.Error("Lexer state shared between instances")
+	}
+	if lexA.Tokens.Get(2).Literal != "a" || lexB.Tokens.Get(2).Literal != "b" {
+		t.Error("Lexer state shared between instances")
+		t.Error("Actual: ", lexA.Tokens.Get(2).Literal, lexB.Tokens.Get(2).Literal)
+	}
+}
+
+func TestLexerErrors(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// 每个输入中出现的全部词法错误类型
+	var errorCase = map[string][]lexer.ErrorKind{
+		"int a;":                             {},
+		"\"abc":                              {lexer.UNTERMINATED_STRING},
+		"a = 1; /* abc":                      {lexer.UNTERMINATED_COMMENT},
+		"a = 87.64.87;":                      {lexer.INVALID_NUMBER},
+		"a = 99999999999999999999;":          {lexer.INVALID_NUMBER},
+		"a = 'ab';":                          {lexer.INVALID_CHAR},
+		"a = 'a":                             {lexer.UNTERMINATED_CHAR},
+		"a = # 1 @ 2;":                       {lexer.ILLEGAL_CHARACTER, lexer.ILLEGAL_CHARACTER},
+		"a = 0b12; b = #;":                   {lexer.INVALID_NUMBER, lexer.ILLEGAL_CHARACTER},
+		"a = \"\\q\\x80\"; b = '\\u{D800}';": {lexer.INVALID_ESCAPE, lexer.INVALID_ESCAPE, lexer.INVALID_ESCAPE},
+		"a = \"\\u{41\";":                    {lexer.INVALID_ESCAPE},
+	}
+
+	for k, v := range errorCase {
+		lex := lexer.NewLexerFromReader("test", strings.NewReader(k))
+		lex.Tokenize()
+		if len(lex.Errors) != len(v) {
+			t.Error("Lexer errors failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", lex.Errors)
+			continue
+		}
+		for i := range v {
+			if lex.Errors[i].Kind != v[i] {
+				t.Error("Lexer errors failed")
+				t.Error("Input: ", k)
+				t.Error("Expected: ", v)
+				t.Error("Actual: ", lex.Errors)
+				break
+			}
+		}
+	}
+
+	// 错误之后的Token仍然被扫描
+	lex := lexer.NewLexerFromReader("test", strings.NewReader("a = 87.64.87; b"))
+	types := scanTypes(lex)
+	expected := []lexer.TokenType{lexer.IDENTIFIER, lexer.ASSIGN, lexer.SEMICOLON, lexer.IDENTIFIER, lexer.EOF_LITERAL}
+	if len(types) != len(expected) {
+		t.Error("Lexer recovery failed")
+		t.Error("Expected: ", expected)
+		t.Error("Actual: ", types)
+	}
+	// 错误位置
+	if err := lex.Errors[0]; err.Pos.Begin.Col != 5 || err.Pos.End.Col != 12 {
+		t.Error("Lexer error position failed")
+		t.Error("Actual: ", err.Error())
+	}
+}
+
+func TestLexerEscape(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// 字面量为还原后的值，Raw为原始文本
+	var escapeCase = []struct {
+		src     string
+		literal any
+	}{
+		{`"a\"b"`, "a\"b"},
+		{`"\\\n\t\0"`, "\\\n\t\x00"},
+		{`"\x41\u{4E2D}\u{1F600}"`, "A中\U0001F600"},
+		{`'\''`, '\''},
+		{`'\\'`, '\\'},
+		{`'\x7A'`, 'z'},
+		{`'\u{6587}'`, '文'},
+		{`'\0'`, rune(0)},
+	}
+
+	for _, c := range escapeCase {
+		lex := lexer.NewLexerFromReader("test", strings.NewReader(c.src))
+		token := lex.Tokenize().Get(0)
+		if token.Literal != c.literal || token.Raw != c.src || lex.HasErrors() {
+			t.Error("Lexer escape failed")
+			t.Error("Input: ", c.src)
+			t.Error("Expected: ", c.literal)
+			t.Error("Actual: ", token.Literal, token.Raw, lex.Errors)
+		}
+	}
+
+	// 非法转义序列不影响后续扫描
+	lex := lexer.NewLexerFromReader("test", strings.NewReader(`a = "x\qy"; b`))
+	types := scanTypes(lex)
+	expected := []lexer.TokenType{lexer.IDENTIFIER, lexer.ASSIGN, lexer.STRING_LITERAL, lexer.SEMICOLON, lexer.IDENTIFIER, lexer.EOF_LITERAL}
+	if !reflect.DeepEqual(types, expected) {
+		t.Error("Lexer escape recovery failed")
+		t.Error("Expected: ", expected)
+		t.Error("Actual: ", types)
+	}
+	// 错误位置为转义序列
+	if len(lex.Errors) != 1 || lex.Errors[0].Pos.Begin.Col != 7 || lex.Errors[0].Pos.End.Col != 8 {
+		t.Error("Lexer escape error position failed")
+		t.Error("Actual: ", lex.Errors)
+	}
+}
+
+func TestLexerLossless(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// 无损模式下拼接全部Token应还原源程序
+	var losslessCase = []string{
+		"",
+		"int a;",
+		"int main()\r\n{\r\n\tint a;\r\n\ta = 0x1F + 1.5e-3;\r\n}\r\n",
+		"  // comment\n/* multi\n line */ float b ; // trailing\n\n",
+		"while(a<>b)\t{ a = 'c'; b = \"str\"; }",
+		"a = 87.64.87; b = # 1;",
+		"a = 1; /* abc",
+		"\"abc",
+		"int 变量 = 1;\n",
+		"\uFEFFint a;\rb;",
+		"a = \"x\\\"y\\q\"; b = '\\u{4E2D}';",
+	}
+
+	for _, v := range losslessCase {
+		lex := lexer.NewLexerFromReader("test", strings.NewReader(v))
+		lex.Lossless = true
+		if res := lex.Tokenize().SourceText(); res != v {
+			t.Error("Lossless lexer failed")
+			t.Errorf("Expected: %q", v)
+			t.Errorf("Actual: %q", res)
+		}
+	}
+
+	// 无损模式不产生SPACE Token，空白与注释作为trivia附加
+	lex := lexer.NewLexerFromReader("test", strings.NewReader("int a; // x\nb"))
+	lex.Lossless = true
+	pool := lex.Tokenize()
+	for _, token := range pool.Pool {
+		if token.Type == lexer.SPACE || token.Category == lexer.COMMENT {
+			t.Error("Lossless lexer emitted trivia as Token: ", token.String())
+		}
+	}
+	semicolon := pool.Get(2)
+	if semicolon.Raw != ";" || len(semicolon.TrailingTrivia) != 3 || semicolon.TrailingTrivia[1].Kind != lexer.SINGLELINE_COMMENT_TRIVIA {
+		t.Error("Lossless lexer trivia failed")
+		t.Error("Actual: ", semicolon.TrailingTrivia)
+	}
+	if b := pool.Get(3); b.Literal != "b" || len(b.LeadingTrivia) != 0 {
+		t.Error("Lossless lexer trivia failed")
+		t.Error("Actual: ", b.LeadingTrivia)
+	}
+
+	// 未配对的多行注释延伸到EOF
+	lex = lexer.NewLexerFromReader("test", strings.NewReader("a /* x"))
+	lex.Lossless = true
+	a := lex.Tokenize().Get(0)
+	if len(a.TrailingTrivia) != 2 || a.TrailingTrivia[1].Kind != lexer.MULTILINE_COMMENT_TRIVIA {
+		t.Error("Lossless lexer trivia failed")
+		t.Error("Actual: ", a.TrailingTrivia)
+	} else if comment := a.TrailingTrivia[1].Pos; comment.Begin.Offset() != 2 || comment.End.Offset() != 6 || comment.End.Row != 1 || comment.End.Col != 6 {
+		t.Error("Lossless lexer trivia span failed")
+		t.Error("Actual: ", comment)
+	}
+}
+
+// relexCase 增量词法分析测试用例
+type relexCase struct {
+	src  string
+	edit lexer.Edit
+}
+
+// checkRelex 比较增量词法分析与对编辑后源程序完整扫描的结果
+func checkRelex(t *testing.T, c relexCase, lossless bool) {
+	lex := lexer.NewLexerFromReader("test", strings.NewReader(c.src))
+	lex.Lossless = lossless
+	lex.Tokenize()
+	pool, err := lex.Relex(c.edit)
+	if err != nil {
+		t.Error("Relex failed: ", err)
+		return
+	}
+
+	edited := c.src[:c.edit.Start] + c.edit.Text + c.src[c.edit.End:]
+	full := lexer.NewLexerFromReader("test", strings.NewReader(edited))
+	full.Lossless = lossless
+	full.Tokenize()
+
+	if !reflect.DeepEqual(pool.Pool, full.Tokens.Pool) || !reflect.DeepEqual(lex.Errors, full.Errors) || string(lex.File) != edited {
+		t.Error("Relex failed")
+		t.Errorf("Input: %q, Edit: %+v, Lossless: %v", c.src, c.edit, lossless)
+		t.Error("Expected: ", full.Tokens.Pool, full.Errors)
+		t.Error("Actual: ", pool.Pool, lex.Errors)
+	}
+}
+
+func TestLexerRelex(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	src := "int main()\n{\n\tint a;\n\ta = 1 + 2; // c\n\tb = \"s\";\n}\n"
+	var relexCases = []relexCase{
+		// 修改标识符
+		{src, lexer.Edit{Start: 4, End: 8, Text: "foo"}},
+		// 在Token末尾追加字符
+		{src, lexer.Edit{Start: 26, End: 26, Text: "3"}},
+		// 插入多行
+		{src, lexer.Edit{Start: 13, End: 13, Text: "\tfloat x;\n\tx = 0.5;\n"}},
+		// 删除多行
+		{src, lexer.Edit{Start: 13, End: 31, Text: ""}},
+		// 打开多行注释
+		{src, lexer.Edit{Start: 13, End: 13, Text: "/*"}},
+		// 闭合多行注释
+		{"a = 1; /* b = 2; c = 3;\nd = 4;", lexer.Edit{Start: 16, End: 16, Text: "*/"}},
+		// 删除多行注释开头
+		{"a = 1; /* b = 2; */ c = 3;", lexer.Edit{Start: 7, End: 9, Text: ""}},
+		// 打开字符串
+		{src, lexer.Edit{Start: 22, End: 22, Text: "\""}},
+		// 闭合字符串
+		{"a = \"abc; b = 2;\nc = 3;", lexer.Edit{Start: 8, End: 8, Text: "\""}},
+		// 形成双字符运算符
+		{"a < b;", lexer.Edit{Start: 3, End: 3, Text: ">"}},
+		// 在文件开头和末尾编辑
+		{src, lexer.Edit{Start: 0, End: 0, Text: "  "}},
+		{src, lexer.Edit{Start: len(src), End: len(src), Text: "x"}},
+		// 引入和修复词法错误
+		{src, lexer.Edit{Start: 24, End: 25, Text: "#"}},
+		{"a = #; b = 1;", lexer.Edit{Start: 4, End: 5, Text: "2"}},
+		// 对齐Token的trivia中的词法错误
+		{"a = b #c;", lexer.Edit{Start: 4, End: 5, Text: "x"}},
+		{"a = b; c #d;\ne = 1;", lexer.Edit{Start: 4, End: 5, Text: "x"}},
+		// 空文件
+		{"", lexer.Edit{Start: 0, End: 0, Text: "int a;"}},
+	}
+
+	for _, c := range relexCases {
+		checkRelex(t, c, false)
+		checkRelex(t, c, true)
+	}
+
+	// 随机编辑
+	fragments := []string{"", " ", "\n", "\r\n", "\r", "\uFEFF", "_", "a", "12", "/*", "*/", "//", "\"", "'", "<", ">", "=", ";", "#", "\t", "中"}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 500; i++ {
+		start := rnd.Intn(len(src) + 1)
+		end := start + rnd.Intn(len(src)-start+1)/4
+		text := fragments[rnd.Intn(len(fragments))] + fragments[rnd.Intn(len(fragments))]
+		checkRelex(t, relexCase{src, lexer.Edit{Start: start, End: end, Text: text}}, i%2 == 0)
+	}
+
+	// 非法的编辑范围
+	lex := lexer.NewLexerFromReader("test", strings.NewReader("int a;"))
+	lex.Tokenize()
+	if _, err := lex.Relex(lexer.Edit{Start: 3, End: 10}); err == nil {
+		t.Error("Relex failed")
+		t.Error("Expected: ", "error")
+		t.Error("Actual: ", "nil")
+	}
+}
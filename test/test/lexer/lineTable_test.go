@@ -0,0 +1,106 @@
+package lexer
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/utils"
+	"strings"
+	"testing"
+)
+
+func TestLineTable(t *testing.T) {
+	src := []byte(utils.BOM + "ab\r\n中文\r😀x\ny")
+	table := utils.NewLineTable(src)
+
+	// 行内容，\n、\r\n及单独的\r均为换行
+	var lineCase = map[uint]string{
+		1: "ab",
+		2: "中文",
+		3: "😀x",
+		4: "y",
+	}
+
+	if table.LineCount() != len(lineCase) {
+		t.Error("LineTable failed")
+		t.Error("Expected: ", len(lineCase))
+		t.Error("Actual: ", table.LineCount())
+	}
+	for k, v := range lineCase {
+		if res := utils.GetLine(table, utils.Position{Row: k}); res != v {
+			t.Error("GetLine failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", res)
+		}
+	}
+	if res := utils.GetLine(table, utils.Position{Row: 5}); res != "EOF" {
+		t.Error("GetLine failed")
+		t.Error("Expected: ", "EOF")
+		t.Error("Actual: ", res)
+	}
+
+	// 字节偏移对应的位置
+	var locationCase = map[int]utils.Location{
+		0:  {Row: 1, ByteCol: 1, RuneCol: 1, UTF16Col: 1}, // BOM
+		3:  {Row: 1, ByteCol: 1, RuneCol: 1, UTF16Col: 1}, // a
+		4:  {Row: 1, ByteCol: 2, RuneCol: 2, UTF16Col: 2}, // b
+		5:  {Row: 1, ByteCol: 3, RuneCol: 3, UTF16Col: 3}, // \r
+		7:  {Row: 2, ByteCol: 1, RuneCol: 1, UTF16Col: 1}, // 中
+		10: {Row: 2, ByteCol: 4, RuneCol: 2, UTF16Col: 2}, // 文
+		13: {Row: 2, ByteCol: 7, RuneCol: 3, UTF16Col: 3}, // \r
+		14: {Row: 3, ByteCol: 1, RuneCol: 1, UTF16Col: 1}, // 😀
+		18: {Row: 3, ByteCol: 5, RuneCol: 2, UTF16Col: 3}, // x
+		20: {Row: 4, ByteCol: 1, RuneCol: 1, UTF16Col: 1}, // y
+		21: {Row: 4, ByteCol: 2, RuneCol: 2, UTF16Col: 2}, // EOF
+	}
+
+	for k, v := range locationCase {
+		if res := table.Location(k); res != v {
+			t.Error("LineTable Location failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", res)
+		}
+	}
+}
+
+func TestOffsetInvalidUTF8(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// 行中的非法UTF-8字节只占一个字节，其后的字节偏移不受影响
+	src := "a \xff bc\nd"
+	var offsetCase = map[string]struct {
+		offset int
+		loc    utils.Location
+	}{
+		"a":  {0, utils.Location{Row: 1, ByteCol: 1, RuneCol: 1, UTF16Col: 1}},
+		"bc": {4, utils.Location{Row: 1, ByteCol: 5, RuneCol: 5, UTF16Col: 5}},
+		"d":  {7, utils.Location{Row: 2, ByteCol: 1, RuneCol: 1, UTF16Col: 1}},
+	}
+
+	lex := lexer.NewLexerFromReader("test", strings.NewReader(src))
+	pools := map[string]*lexer.TokenPool{
+		"Lexer":      lex.Tokenize(),
+		"TableLexer": lexer.NewTableLexerFromBytes("test", []byte(src)).Tokenize(),
+	}
+	table := utils.NewLineTable([]byte(src))
+	for name, pool := range pools {
+		for _, token := range pool.Pool {
+			v, ok := offsetCase[token.Raw]
+			if !ok {
+				continue
+			}
+			offset := int(token.Pos.Begin.Offset())
+			if offset != v.offset || table.Location(offset) != v.loc {
+				t.Error(name + " offset failed")
+				t.Error("Token: ", token)
+				t.Error("Expected: ", v.offset, v.loc)
+				t.Error("Actual: ", offset, table.Location(offset))
+			}
+		}
+	}
+	// 非法字节本身的位置
+	if len(lex.Errors) != 1 || lex.Errors[0].Pos.Begin.Offset() != 2 {
+		t.Error("Invalid byte offset failed")
+		t.Error("Actual: ", lex.Errors)
+	}
+}
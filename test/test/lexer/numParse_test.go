@@ -2,6 +2,7 @@ package lexer
 
 import (
 	"CompilerInGo/utils"
+	"errors"
 	"testing"
 )
 
@@ -14,6 +15,21 @@ func TestParseInt(t *testing.T) {
 		"1":           1,
 		"1234567890":  1234567890,
 		"-1234567890": -1234567890,
+
+		"0x1F":                  31,
+		"0X1f":                  31,
+		"0x123":                 291,
+		"-0x10":                 -16,
+		"0x_FF":                 255,
+		"0o17":                  15,
+		"0O777":                 511,
+		"0b1010":                10,
+		"0B1111_0000":           240,
+		"1_000_000":             1000000,
+		"00":                    0,
+		"9223372036854775807":   9223372036854775807,
+		"-9223372036854775808":  -9223372036854775808,
+		"0x7FFF_FFFF_FFFF_FFFF": 9223372036854775807,
 	}
 
 	for k, v := range rightCase {
@@ -36,8 +52,16 @@ func TestParseInt(t *testing.T) {
 		"123.456",
 		"123.456.789",
 		"-123.456",
-		"0x123",
 		"0b123",
+		"0x",
+		"0xG1",
+		"0o8",
+		"0b",
+		"_1",
+		"1_",
+		"1__0",
+		"0x__1",
+		"1e5",
 	}
 
 	for _, v := range wrongCase {
@@ -48,6 +72,25 @@ func TestParseInt(t *testing.T) {
 			t.Error("Actual: ", "nil")
 		}
 	}
+
+	// Overflow Case
+	var overflowCase = []string{
+		"9223372036854775808",
+		"-9223372036854775809",
+		"99999999999999999999",
+		"0x8000000000000000",
+		"0b1_0000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	for _, v := range overflowCase {
+		if num, err := utils.ParseInt(v); !errors.Is(err, utils.ErrRange) {
+			t.Error("ParseInt failed")
+			t.Error("Input: ", v)
+			t.Error("Expected: ", utils.ErrRange)
+			t.Error("Actual: ", err)
+			t.Error("Actual Result: ", num)
+		}
+	}
 }
 
 func TestParseFloat(t *testing.T) {
@@ -63,6 +106,17 @@ func TestParseFloat(t *testing.T) {
 		"-0.0":  0,
 		"123.":  123,
 		"9.":    9,
+
+		"0.1":                     0.1,
+		"0.3":                     0.3,
+		"1.5e-3":                  1.5e-3,
+		"1e10":                    1e10,
+		"2E+2":                    200,
+		"-2.5e2":                  -250,
+		"1_000.000_1":             1000.0001,
+		"123456789.123456789":     123456789.123456789,
+		"2.2250738585072014e-308": 2.2250738585072014e-308,
+		"1.7976931348623157e308":  1.7976931348623157e308,
 	}
 
 	for k, v := range rightCase {
@@ -87,6 +141,14 @@ func TestParseFloat(t *testing.T) {
 		"0b123",
 		".123",
 		"78.@",
+		"1e",
+		"1e+",
+		"1.5e-",
+		"1__0.5",
+		"1_.5",
+		"1._5",
+		"1e_5",
+		"0x1p3",
 	}
 
 	for _, v := range wrongCase {
@@ -98,4 +160,20 @@ func TestParseFloat(t *testing.T) {
 			t.Error("Actual Result: ", num)
 		}
 	}
+
+	// Overflow Case
+	var overflowCase = []string{
+		"1e309",
+		"-1.8e308",
+	}
+
+	for _, v := range overflowCase {
+		if num, err := utils.ParseFloat(v); !errors.Is(err, utils.ErrRange) {
+			t.Error("ParseFloat failed")
+			t.Error("Input: ", v)
+			t.Error("Expected: ", utils.ErrRange)
+			t.Error("Actual: ", err)
+			t.Error("Actual Result: ", num)
+		}
+	}
 }
@@ -0,0 +1,136 @@
+package lexer
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/utils"
+	"math/rand"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// checkTable 检查表驱动词法分析器与Lexer的扫描结果是否完全一致
+func checkTable(t *testing.T, src string) {
+	lex := lexer.NewLexerFromReader("test", strings.NewReader(src))
+	expected := lex.Tokenize().Pool
+
+	table := lexer.NewTableLexerFromBytes("test", []byte(src))
+	actual := table.Tokenize().Pool
+
+	if !reflect.DeepEqual(actual, expected) {
+		t.Error("Table lexer failed")
+		t.Errorf("Input: %q", src)
+		for i := 0; i < len(expected) || i < len(actual); i++ {
+			if i >= len(expected) || i >= len(actual) || !reflect.DeepEqual(actual[i], expected[i]) {
+				if i < len(expected) {
+					t.Errorf("Expected: %+v", expected[i])
+				}
+				if i < len(actual) {
+					t.Errorf("Actual: %+v", actual[i])
+				}
+				break
+			}
+		}
+	}
+	if !reflect.DeepEqual(table.Errors, lex.Errors) {
+		t.Error("Table lexer errors failed")
+		t.Errorf("Input: %q", src)
+		t.Error("Expected: ", lex.Errors)
+		t.Error("Actual: ", table.Errors)
+	}
+}
+
+func TestTableLexer(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	var cases = []string{
+		"",
+		"int a;",
+		"int a[10]; a[i-1] = a[ 0 ];",
+		"struct Point { int x; } p.x = q . y;",
+		"switch (c) { case 'a': case -1: break; default : ; }",
+		"a = 0x1F + 1.5e-3;",
+		"while(a<>b)\n{}",
+		"a==b<=c>=d<e>f=g+h-i*j/k%l",
+		"a-1 --2 -0x10 0b101 0o17 0e+5 0x1e+5 1e+ 1.2.3 12abc 1_000 99999999999999999999 1e999",
+		"\"abc\" \"a\\\"b\" \"\\x41\\u{4E2D}\\n\" \"\\q\" \"\\x\" \"\\u{110000}\" \"a\\\nb\"",
+		"'a' '' '\\'' '\\n' '\\x41' '\\u{41}' '\\x4' '\\q' 'ab' 'a\n'x' '\\\n' '\\'",
+		"'\\x41\n'\\u{41\n'\\x414' 'a",
+		"'",
+		"\"abc",
+		"\"abc\\",
+		"// comment\n/* multi\n line */ /**/ /***/ /* a * b */ //\n//\r\n/* a",
+		"变量 = café + x\u0301; $a$ _a ·a",
+		"\uFEFFint a;",
+		"a\r\nb\rc\n\rd",
+		"# @ \\ ` . : \xff \xe4\xb8",
+		"if then else begin end void var int float string char do call read write and or not return continue break for const struct switch case default",
+	}
+	for _, c := range cases {
+		checkTable(t, c)
+	}
+
+	// 测试程序
+	for _, file := range []string{"../../long.program", "../../sample1.program", "../../sample2.program", "../../fail.program"} {
+		content, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+		checkTable(t, string(content))
+	}
+
+	// 随机拼接的片段
+	fragments := []string{" ", "\n", "\r\n", "\r", "\t", "\uFEFF", "_", "$", "a", "e", "x", "0", "12", "0x", "1.5", "-", "+", "*", "/", "/*", "*/", "//",
+		"\"", "'", "\\", "\\x4", "\\u{", "}", "{", "<", ">", "=", ";", ",", "(", ")", "#", "中", "٣", "\u0301", "\xff", "if", "int"}
+	rnd := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		var src strings.Builder
+		for j := rnd.Intn(12); j >= 0; j-- {
+			src.WriteString(fragments[rnd.Intn(len(fragments))])
+		}
+		checkTable(t, src.String())
+	}
+}
+
+func TestCompileSpec(t *testing.T) {
+	utils.InitLogger("CLOSE")
+
+	// 规范的正则表达式错误
+	var errorCase = []string{"(a", "a)", "[a", "{undefined}", "{loop}", "*a", "a\\", "中", "[{word}]", "[a--{digit}]", "[z-a]"}
+	defs := map[string]string{"loop": "a{loop}", "word": "[a-z]"}
+	for _, c := range errorCase {
+		if _, err := lexer.CompileSpec([]lexer.Rule{{Pattern: c, Type: lexer.IDENTIFIER}}, defs); err == nil {
+			t.Error("Compile spec failed")
+			t.Errorf("Input: %q", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "nil")
+		}
+	}
+
+	// 最长匹配，长度相同时取靠前的规则
+	dfa, err := lexer.CompileSpec([]lexer.Rule{
+		{Pattern: "if", Type: lexer.IF},
+		{Pattern: "[a-z]+", Type: lexer.IDENTIFIER},
+		{Pattern: "[0-9]+(\\.[0-9]+)?", Type: lexer.INTEGER_LITERAL},
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var matchCase = map[string][2]int{
+		"if":   {0, 2},
+		"ifa":  {1, 3},
+		"i f":  {1, 1},
+		"12.":  {2, 2},
+		"12.5": {2, 4},
+		"中":    {-1, 0},
+	}
+	for k, v := range matchCase {
+		if rule, end := dfa.Match([]byte(k), 0); rule != v[0] || end != v[1] {
+			t.Error("DFA match failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", rule, end)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestArray(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 数组元素的读写、数组参数按引用传递
+	var arrayCase = map[string]int{
+		"int main(){ int a[10]; a[0] = 1; for (int i = 1; i < 10; i = i + 1) a[i] = a[i-1] + 1; return a[9]; }":                                                10,
+		"int main(){ int a[5], s = 0; for (int i = 0; i < 5; i = i + 1) a[i] = i * i; for (int i = 0; i < 5; i = i + 1) s = s + a[i]; return s; }":             30,
+		"int main(){ int a[3], b[3]; a[0] = 2; b[a[0]] = 7; return b[2] + a[1]; }":                                                                             7,
+		"int sum(int a[4]){ int s = 0; for (int i = 0; i < 4; i = i + 1) s = s + a[i]; return s; } int main(){ int a[4]; a[1] = 3; a[3] = 4; return sum(a); }": 7,
+		"void fill(int a[3], int v){ for (int i = 0; i < 3; i = i + 1) a[i] = v; } int main(){ int a[3]; call fill(a, 5); return a[0] + a[2]; }":               10,
+		"int g[4]; void set(int i){ g[i] = i * 10; } int main(){ call set(1); call set(3); return g[1] + g[3]; }":                                              40,
+		"int main(){ int a[2]; int n = 0; for (; n < 2; n = n + 1) a[n] = n + 1; return a[0] * 10 + a[1]; }":                                                   12,
+	}
+
+	for k, v := range arrayCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Array failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestArrayBounds(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 运行时下标越界
+	var boundsCase = []string{
+		"int main(){ int a[3], i = 3; a[i] = 1; return 0; }",
+		"int main(){ int a[3], i = 0; i = i - 1; return a[i]; }",
+		"int f(int a[2], int i){ return a[i]; } int main(){ int a[2]; return f(a, 2); }",
+	}
+	for _, c := range boundsCase {
+		if actual, output := runIO(t, generate(t, c), ""); actual != -1 || output != "error: index out of range for array a" {
+			t.Error("Array bounds failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error: index out of range for array a")
+			t.Error("Actual: ", actual, output)
+		}
+	}
+}
+
+func TestArrayCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 常量下标越界、下标类型错误、数组整体使用及参数类型不匹配
+	var errorCase = []string{
+		"int main(){ int a[3]; a[3] = 1; return 0; }",
+		"int main(){ int a[3]; return a[-1]; }",
+		"int main(){ int a[0]; return 0; }",
+		"int main(){ int a[3]; a[1.5] = 1; return 0; }",
+		"int main(){ int a; a[0] = 1; return 0; }",
+		"int main(){ int a[3], b[3]; a = b; return 0; }",
+		"int main(){ int a[3] = 1; return 0; }",
+		"int main(){ int a[3]; return a + 1; }",
+		"int main(){ int a[3]; write(a); return 0; }",
+		"int main(){ int a[3]; read(a); return 0; }",
+		"int main(){ string a[3]; a[0] = 1; return 0; }",
+		"int f(int a[4]){ return a[0]; } int main(){ int a[3]; return f(a); }",
+		"int f(int a[3]){ return a[0]; } int main(){ float a[3]; return f(a); }",
+		"int f(int a[3]){ return a[0]; } int main(){ return f(1); }",
+		"int g[2]; int main(){ return g[2]; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Array check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
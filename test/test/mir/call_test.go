@@ -0,0 +1,80 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestCallExp(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 方法调用作为表达式，返回值参与运算
+	var callCase = map[string]int{
+		"int f(int a){ return a * 2; } int g(int a, int b){ return a - b; } int main(){ int x; x = f(3) + g(10, 1); return x; }":                                   15,
+		"int f(int a){ return a + 1; } int main(){ return f(f(f(1))) * f(0); }":                                                                                    4,
+		"int f(int a){ return a * a; } int main(){ int r; r = 0; if (f(3) > 8 and f(2) == 4) r = f(4) - f(1); return r; }":                                         15,
+		"int test(int a, int b){ a = a + b; return 33 + 56; } int main(){ int r; r = test(56, 78); return r; }":                                                    89,
+		"void p(int a){ int b; b = a; } int h(){ return 7; } int main(){ int i, s; i = 0; s = 0; while (i < 3) { call p(i); s = s + h(); i = i + 1; } return s; }": 21,
+	}
+
+	for k, v := range callCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Call expression failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestCallCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 实参个数、类型错误，无返回值方法及返回值类型错误
+	var errorCase = []string{
+		"int f(int a){ return a; } int main(){ return f(); }",
+		"int f(int a){ return a; } int main(){ return f(1, 2); }",
+		"int f(int a){ return a; } int main(){ string s; return f(s); }",
+		"void f(int a){ int b; b = a; } int main(){ return f(1); }",
+		"int main(){ return g(1); }",
+		"int f(int a){ return a; } int main(){ string s; call f(s + 1); return 0; }",
+		"int f(){ return \"s\"; } int main(){ int a = f(); return a; }",
+		"char f(){ return 1; } int main(){ return 0; }",
+		"void g(){ return 1; } int main(){ call g(); return 0; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Call check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+
+	// 整数与小数可以隐式转换，递归调用
+	var validCase = []string{
+		"float f(float a){ return a; } int main(){ float x; x = f(1); return 0; }",
+		"int f(int a){ return a; } int main(){ float x; x = 1.5; return f(x * 2); }",
+		"int f(int a){ if (a < 1) return 0; return a + f(a - 1); } int main(){ return f(3); }",
+	}
+	for _, c := range validCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs != 0 {
+			t.Error("Call check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "no error")
+			t.Error("Actual: ", errs, " errors")
+		}
+	}
+}
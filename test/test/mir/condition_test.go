@@ -0,0 +1,54 @@
+package mir
+
+import (
+	"fmt"
+	"testing"
+
+	"CompilerInGo/utils"
+)
+
+func TestConditionChain(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 条件表达式及其在a, b, c取值下的结果
+	var condCase = map[string]func(a, b, c int) bool{
+		"a<1 or b<2 or c<3": func(a, b, c int) bool {
+			return a < 1 || b < 2 || c < 3
+		},
+		"a<1 and b<2 and c<3": func(a, b, c int) bool {
+			return a < 1 && b < 2 && c < 3
+		},
+		"a<44 and a>32 or a<47 and a>35 or b==c": func(a, b, c int) bool {
+			return a < 44 && a > 32 || a < 47 && a > 35 || b == c
+		},
+		"(a<1 or b<2) and c>3": func(a, b, c int) bool {
+			return (a < 1 || b < 2) && c > 3
+		},
+		"not a<1 and not (b<2 or c<3)": func(a, b, c int) bool {
+			return !(a < 1) && !(b < 2 || c < 3)
+		},
+		"not not (a+b)*2 < c": func(a, b, c int) bool {
+			return (a+b)*2 < c
+		},
+		"((a<b)) or (a) == (c)": func(a, b, c int) bool {
+			return a < b || a == c
+		},
+	}
+
+	for k, v := range condCase {
+		for _, vars := range [][3]int{{0, 0, 0}, {0, 5, 5}, {5, 1, 4}, {40, 3, 9}, {46, 9, 9}, {50, 1, 2}, {2, 3, 20}} {
+			src := fmt.Sprintf("int main(){ int a, b, c, r; a = %d; b = %d; c = %d; if (%s) r = 1; else r = 0; return r; }", vars[0], vars[1], vars[2], k)
+			expected := 0
+			if v(vars[0], vars[1], vars[2]) {
+				expected = 1
+			}
+			if actual := run(t, generate(t, src)); actual != expected {
+				t.Error("Condition chain failed")
+				t.Error("Input: ", k, vars)
+				t.Error("Expected: ", expected)
+				t.Error("Actual: ", actual)
+			}
+		}
+	}
+}
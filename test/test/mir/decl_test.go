@@ -0,0 +1,75 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestDeclInit(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 变量声明时的初始值，从左到右依次声明并赋值
+	var declCase = map[string]int{
+		"int main(){ int a = 1, b, c = a + 2; b = c * 2; return a + b + c; }":                              10,
+		"int main(){ int a = 2; int b = a * a, c = b - a; return c; }":                                     2,
+		"int f(int x){ return x + 1; } int main(){ int a = f(1), b = f(a) % 2; return a * 10 + b; }":       21,
+		"int main(){ int s = 0, i = 0; while (i < 4) { int j = i * 2; s = s + j; i = i + 1; } return s; }": 12,
+	}
+
+	for k, v := range declCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Declaration initialiser failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestDeclInitCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 初始值类型错误、使用尚未声明的变量
+	var errorCase = []string{
+		"int main(){ string s = 1; return 0; }",
+		"int main(){ int a = 1; string s = a + 1; return 0; }",
+		"int main(){ int a = a + 1; return a; }",
+		"int main(){ int b = c, c = 1; return b; }",
+		"int main(){ int a = 1, a = 2; return a; }",
+		"void f(){ int b; b = 1; } int main(){ int a = f(); return a; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Declaration check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+
+	// 整数与小数可以隐式转换
+	var validCase = []string{
+		"int main(){ float x = 1, y = x * 2; int a = y; return a; }",
+		"int main(){ int a = 1, b = a, c = a + b; return c; }",
+	}
+	for _, c := range validCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs != 0 {
+			t.Error("Declaration check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "no error")
+			t.Error("Actual: ", errs, " errors")
+		}
+	}
+}
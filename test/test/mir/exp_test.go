@@ -0,0 +1,309 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/mir"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"fmt"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// generate 编译源程序，返回中间代码
+func generate(t *testing.T, src string) *mir.Program {
+	program, err := parser.ParseSource("test", src)
+	if err != nil {
+		t.Fatal("Parse failed: ", err)
+	}
+	hirProgram, errs := analyser.NewAnalyser().Analyse(program)
+	if errs != 0 {
+		t.Fatal("Analyse failed with ", errs, " errors")
+	}
+	return mir.NewMIRGenerator().Generate(hirProgram)
+}
+
+// run 解释执行中间代码，返回main方法的返回值
+func run(t *testing.T, program *mir.Program) int {
+	result, _ := runIO(t, program, "")
+	return result
+}
+
+// runIO 以input为标准输入解释执行中间代码，返回main方法的返回值及输出
+// 变量的值为int、float64、string或数组，字符按编码保存为int，数组按引用传递
+// 出现运行时错误时返回-1，输出以错误信息结尾
+func runIO(t *testing.T, program *mir.Program, input string) (int, string) {
+	vars := make(map[string]any)
+	inputs := strings.Fields(input)
+	var output strings.Builder
+	value := func(param mir.Param) any {
+		switch p := param.(type) {
+		case mir.IntParam:
+			return int(p)
+		case mir.FloatParam:
+			return float64(p)
+		case mir.CharParam:
+			return int(p)
+		case mir.StringParam:
+			return string(p)
+		}
+		if v, ok := vars[param.Str()]; ok {
+			return v
+		}
+		return 0
+	}
+	num := func(v any) int {
+		if f, ok := v.(float64); ok {
+			return int(f)
+		}
+		i, _ := v.(int)
+		return i
+	}
+	// float 获取数值的小数值，整数隐式转换为小数
+	float := func(v any) float64 {
+		if f, ok := v.(float64); ok {
+			return f
+		}
+		return float64(num(v))
+	}
+	// isFloat 判断运算是否按小数进行，任一操作数为小数时按小数运算
+	isFloat := func(l, r any) bool {
+		_, lf := l.(float64)
+		_, rf := r.(float64)
+		return lf || rf
+	}
+	// compare 比较两个值，字符串按字典序比较
+	compare := func(l, r any) int {
+		if ls, ok := l.(string); ok {
+			return strings.Compare(ls, r.(string))
+		}
+		if isFloat(l, r) {
+			switch lf, rf := float(l), float(r); {
+			case lf < rf:
+				return -1
+			case lf > rf:
+				return 1
+			}
+			return 0
+		}
+		return num(l) - num(r)
+	}
+	// array 获取数组变量，未赋值的元素为0
+	array := func(param mir.Param) map[int]any {
+		arr, ok := vars[param.Str()].(map[int]any)
+		if !ok {
+			t.Fatal("Run failed: not an array ", param.Str())
+		}
+		return arr
+	}
+
+	for pc, steps := 0, 0; pc < len(program.StmtSeq); steps++ {
+		if steps > 100000 {
+			t.Fatal("Run failed: too many steps")
+		}
+		stmt := program.StmtSeq[pc]
+		pc++
+		arg1, arg2 := value(stmt.Arg1), value(stmt.Arg2)
+		// 条件跳转是否成立
+		jump := false
+		switch stmt.Op {
+		case mir.ASSIGN:
+			// 变量声明语句的右侧为源程序中的变量名，不改变变量的值，数组在第一次声明时分配
+			if name, ok := stmt.Arg2.(mir.StrParam); ok && !strings.HasPrefix(string(name), "_T") {
+				if _, declared := vars[stmt.Res.Str()]; !declared && strings.HasSuffix(string(name), "]") {
+					vars[stmt.Res.Str()] = make(map[int]any)
+				}
+				break
+			}
+			vars[stmt.Res.Str()] = arg2
+		case mir.PLUS:
+			if isFloat(arg1, arg2) {
+				vars[stmt.Res.Str()] = float(arg1) + float(arg2)
+			} else {
+				vars[stmt.Res.Str()] = num(arg1) + num(arg2)
+			}
+		case mir.MINUS:
+			if isFloat(arg1, arg2) {
+				vars[stmt.Res.Str()] = float(arg1) - float(arg2)
+			} else {
+				vars[stmt.Res.Str()] = num(arg1) - num(arg2)
+			}
+		case mir.TIMES:
+			if isFloat(arg1, arg2) {
+				vars[stmt.Res.Str()] = float(arg1) * float(arg2)
+			} else {
+				vars[stmt.Res.Str()] = num(arg1) * num(arg2)
+			}
+		case mir.DIVIDE:
+			if isFloat(arg1, arg2) {
+				vars[stmt.Res.Str()] = float(arg1) / float(arg2)
+			} else {
+				vars[stmt.Res.Str()] = num(arg1) / num(arg2)
+			}
+		case mir.MOD:
+			vars[stmt.Res.Str()] = num(arg1) % num(arg2)
+		case mir.NEG:
+			if f, ok := arg1.(float64); ok {
+				vars[stmt.Res.Str()] = -f
+			} else {
+				vars[stmt.Res.Str()] = -num(arg1)
+			}
+		case mir.CONCAT:
+			vars[stmt.Res.Str()] = arg1.(string) + arg2.(string)
+		case mir.READ:
+			// 按变量类型读取下一个以空白分隔的值
+			if len(inputs) == 0 {
+				t.Fatal("Run failed: no more input")
+			}
+			in := inputs[0]
+			inputs = inputs[1:]
+			switch stmt.Arg1.Str() {
+			case "int":
+				i, err := strconv.Atoi(in)
+				if err != nil {
+					t.Fatal("Run failed: ", err)
+				}
+				vars[stmt.Res.Str()] = i
+			case "float":
+				f, err := strconv.ParseFloat(in, 64)
+				if err != nil {
+					t.Fatal("Run failed: ", err)
+				}
+				vars[stmt.Res.Str()] = f
+			case "char":
+				vars[stmt.Res.Str()] = int([]rune(in)[0])
+			default:
+				vars[stmt.Res.Str()] = in
+			}
+		case mir.WRITE:
+			if stmt.Arg1.Str() == "char" {
+				output.WriteRune(rune(num(arg2)))
+			} else {
+				fmt.Fprint(&output, arg2)
+			}
+		case mir.INDEXLOAD:
+			if v, ok := array(stmt.Arg1)[num(arg2)]; ok {
+				vars[stmt.Res.Str()] = v
+			} else {
+				vars[stmt.Res.Str()] = 0
+			}
+		case mir.INDEXSTORE:
+			array(stmt.Res)[num(arg2)] = arg1
+		case mir.ERROR:
+			return -1, output.String() + "error: " + arg1.(string)
+		case mir.JMP:
+			jump = true
+		case mir.JEQUAL:
+			jump = compare(arg1, arg2) == 0
+		case mir.JNEQUAL:
+			jump = compare(arg1, arg2) != 0
+		case mir.JGREAT:
+			jump = compare(arg1, arg2) > 0
+		case mir.JGREATEQUAL:
+			jump = compare(arg1, arg2) >= 0
+		case mir.JLESS:
+			jump = compare(arg1, arg2) < 0
+		case mir.JLESSEQUAL:
+			jump = compare(arg1, arg2) <= 0
+		case mir.JZERO:
+			jump = num(arg1) == 0
+		case mir.JNZERO:
+			jump = num(arg1) != 0
+		case mir.STOP:
+			return num(value(stmt.Res)), output.String()
+		default:
+			t.Fatal("Run failed: unknown op ", stmt.Str())
+		}
+		if jump {
+			// 跳转目标为语句位置，或保存返回地址的变量
+			pc = num(value(stmt.Res))
+		}
+	}
+	t.Fatal("Run failed: no STOP")
+	return 0, ""
+}
+
+// arithmetic 按顺序取出中间代码中的算术运算语句
+func arithmetic(program *mir.Program) []string {
+	res := make([]string, 0)
+	for _, stmt := range program.StmtSeq {
+		switch stmt.Op {
+		case mir.PLUS, mir.MINUS, mir.TIMES, mir.DIVIDE, mir.MOD:
+			res = append(res, stmt.Comment)
+		}
+	}
+	return res
+}
+
+func TestExpChain(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 任意长度的算术表达式，左结合，乘除优先于加减
+	var expCase = map[string][]string{
+		"a = a - b - c;": {
+			"_T4 = _T1 - _T2", "_T5 = _T4 - _T3",
+		},
+		"a = a + b * c / 2 - (a - b - c);": {
+			"_T4 = _T2 * _T3", "_T6 = _T4 / _T5", "_T7 = _T1 + _T6", "_T8 = _T1 - _T2", "_T9 = _T8 - _T3", "_T10 = _T7 - _T9",
+		},
+		"a = a * b * c + a + b;": {
+			"_T4 = _T1 * _T2", "_T5 = _T4 * _T3", "_T6 = _T5 + _T1", "_T7 = _T6 + _T2",
+		},
+	}
+
+	for k, v := range expCase {
+		src := "int main(){ int a, b, c; " + k + " return a; }"
+		actual := arithmetic(generate(t, src))
+		if strings.Join(actual, "; ") != strings.Join(v, "; ") {
+			t.Error("Expression chain failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestOperatorExp(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 表达式及其在a, b, c取值下的结果，比较及逻辑运算的结果为0或1
+	var expCase = map[string]func(a, b, c int) int{
+		"a % b + c * -2": func(a, b, c int) int {
+			return a%b + c*-2
+		},
+		"-a-1 - -b": func(a, b, c int) int {
+			return -a - 1 - -b
+		},
+		"(a < b) + (b < c) * 2": func(a, b, c int) int {
+			return boolToInt(a < b) + boolToInt(b < c)*2
+		},
+		"a + 1 < b and not c % 2 == 0": func(a, b, c int) int {
+			return boolToInt(a+1 < b && !(c%2 == 0))
+		},
+		"a-b*c%4": func(a, b, c int) int {
+			return a - b*c%4
+		},
+	}
+
+	for k, v := range expCase {
+		for _, vars := range [][3]int{{7, 3, 5}, {1, 2, 4}, {10, 9, 8}, {2, 5, 6}} {
+			src := fmt.Sprintf("int main(){ int a, b, c, r; a = %d; b = %d; c = %d; r = %s; return r; }", vars[0], vars[1], vars[2], k)
+			if actual, expected := run(t, generate(t, src)), v(vars[0], vars[1], vars[2]); actual != expected {
+				t.Error("Operator expression failed")
+				t.Error("Input: ", k, vars)
+				t.Error("Expected: ", expected)
+				t.Error("Actual: ", actual)
+			}
+		}
+	}
+}
+
+func boolToInt(b bool) int {
+	if b {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,61 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestGlobal(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 全局变量在所有方法中共享，常量及初始值在main方法之前计算
+	var globalCase = map[string]int{
+		"int counter; void inc(){ counter = counter + 1; } int main(){ call inc(); call inc(); call inc(); return counter; }":       3,
+		"const int N = 4; int main(){ int s = 0; for (int i = 0; i < N; i = i + 1) s = s + i; return s; }":                          6,
+		"const int A = 2, B = A * 3; int total = A + B; int main(){ return total * 10 + B; }":                                       86,
+		"int calls = 0; int f(int n){ calls = calls + 1; return n * 2; } int main(){ int a = f(1) + f(2); return a * 10 + calls; }": 62,
+		"int x = 5; int get(){ return x; } int main(){ x = x * 2; return get(); }":                                                  10,
+	}
+
+	for k, v := range globalCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Global failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestGlobalCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 常量只读、必须有初始值，局部变量、参数及方法不能与全局变量重名
+	var errorCase = []string{
+		"const int N = 1; int main(){ N = 2; return N; }",
+		"const int N = 1; int main(){ for (int i = 0; i < 3; N = N + 1) ; return 0; }",
+		"const int N; int main(){ return 0; }",
+		"int a; float a; int main(){ return 0; }",
+		"int a; int main(){ int a = 1; return a; }",
+		"int a; int f(int a){ return a; } int main(){ return f(1); }",
+		"int f; int f(){ return 1; } int main(){ return 0; }",
+		"int a = b; int b = 1; int main(){ return a; }",
+		"string s = 1; int main(){ return 0; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Global check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
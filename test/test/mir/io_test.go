@@ -0,0 +1,62 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestReadWrite(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 源程序、标准输入 -> 输出
+	var ioCase = []struct {
+		src, input, output string
+	}{
+		{`int main(){ write(1, " ", 2 + 3); return 0; }`, "", "1 5\n"},
+		{`int main(){ int a, b; read(a, b); write("sum=", a + b); return 0; }`, "3 4", "sum=7\n"},
+		{`int main(){ string s; char c; read(s, c); write(s + "!", c, 'z'); write(); return 0; }`, "hi\n q", "hi!qz\n\n"},
+		{`int n; int main(){ read(n); for (int i = 0; i < n; i = i + 1) write(i); return 0; }`, "3", "0\n1\n2\n"},
+		{`void show(int x){ write("x:", x); } int main(){ int a; read(a); call show(a * 2); return 0; }`, "21", "x:42\n"},
+		{`int main(){ write(2.5, " ", -0.25); return 0; }`, "", "2.5 -0.25\n"},
+		{`int main(){ float f; read(f); write(f * 2); return 0; }`, "1.25", "2.5\n"},
+		{`int main(){ float f; int n; read(f, n); write(f + n, " ", n / 4, " ", f / 4); if (f < n) write("less"); return 0; }`, "0.5 2", "2.5 0 0.125\nless\n"},
+	}
+
+	for _, c := range ioCase {
+		if _, actual := runIO(t, generate(t, c.src), c.input); actual != c.output {
+			t.Error("Read write failed")
+			t.Error("Input: ", c.src)
+			t.Errorf("Expected: %q", c.output)
+			t.Errorf("Actual: %q", actual)
+		}
+	}
+}
+
+func TestReadWriteCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 读取未声明的变量、常量或方法，输出没有返回值的方法调用
+	var errorCase = []string{
+		"int main(){ read(a); return 0; }",
+		"const int N = 1; int main(){ read(N); return 0; }",
+		"int f(){ return 1; } int main(){ read(f); return 0; }",
+		"void f(){ } int main(){ write(f()); return 0; }",
+		"int main(){ write(x); return 0; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Read write check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
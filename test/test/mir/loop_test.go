@@ -0,0 +1,63 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestLoop(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// for、do-while循环及break、continue的跳转位置
+	var loopCase = map[string]int{
+		"int main(){ int s = 0; for (int i = 0; i < 5; i = i + 1) s = s + i; return s; }":                                                                     10,
+		"int main(){ int s = 0, i; for (i = 1; i <= 3; i = i + 1) { s = s * 10 + i; } return s; }":                                                            123,
+		"int main(){ int s = 0; for (int i = 0; i < 6; i = i + 1) { if (i % 2 == 0) continue; s = s + i; } return s; }":                                       9,
+		"int main(){ int s = 0, i = 0; for (;;) { i = i + 1; if (i > 4) break; s = s + i; } return s; }":                                                      10,
+		"int main(){ int s = 0; for (int i = 0; i < 3; i = i + 1) s = s + i; for (int i = 10; i < 12; i = i + 1) s = s + i; return s; }":                      24,
+		"int main(){ int n = 0; do n = n + 1; while (n < 0); return n; }":                                                                                     1,
+		"int main(){ int n = 0, s = 0; do { n = n + 1; if (n == 2) continue; s = s + n; } while (n < 4); return s; }":                                         8,
+		"int main(){ int n = 0; do { n = n + 1; if (n == 3) break; } while (1); return n; }":                                                                  3,
+		"int main(){ int s = 0, i = 0; while (i < 3) { i = i + 1; for (int j = 0; j < 3; j = j + 1) { if (j == i) break; s = s + 1; } } return s; }":          6,
+		"int main(){ int s = 0, i = 0; while (i < 3) { i = i + 1; if (i == 2) continue; do { s = s + i; break; } while (1); s = s + 100; } return s; }":       204,
+		"int main(){ int s = 0; for (int i = 0; i < 3; i = i + 1) { int j = 0; while (1) { j = j + 1; if (j > i) break; continue; } s = s + j; } return s; }": 6,
+	}
+
+	for k, v := range loopCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Loop failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestLoopCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 循环外的break、continue，初始化语句中声明的变量在循环外不可见
+	var errorCase = []string{
+		"int main(){ break; return 0; }",
+		"int main(){ if (1) continue; return 0; }",
+		"int f(){ continue; return 0; } int main(){ while (1) { call f(); } return 0; }",
+		"int main(){ for (int i = 0; i < 3; i = i + 1) ; return i; }",
+		"int main(){ for (; 1; k = 1) break; return 0; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Loop check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
@@ -0,0 +1,90 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/mir"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestStringExp(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 字符串拼接、字符串及字符的比较
+	var stringCase = map[string]int{
+		`int main(){ string s = "hi"; s = s + ", " + "there"; if (s == "hi, there") return 1; return 0; }`:                           1,
+		`int main(){ string a = "abc", b = "abd"; return (a < b) * 100 + (a == "abc") * 10 + (b <> "abd"); }`:                        110,
+		`int main(){ char c = 'x'; return (c == 'x') + (c > 'a') * 10 + (c >= 'y') * 100; }`:                                         11,
+		`string greet(string name){ return "hello " + name; } int main(){ return greet("bob") == "hello bob"; }`:                     1,
+		`const string SEP = "-"; int main(){ string s = "a"; int i; for (i = 0; i < 2; i = i + 1) s = s + SEP; return s == "a--"; }`: 1,
+		`int main(){ char c = '\n'; return c == '\n' and not (c == 'n'); }`:                                                          1,
+	}
+
+	for k, v := range stringCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("String expression failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestStringLiteralParam(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 字面量使用单独的参数类型，与同名的变量名区分
+	program := generate(t, `int main(){ string s = "s"; char c = 'c'; s = s + "_T1"; return c == 'c'; }`)
+	var strs, chars []string
+	for _, stmt := range program.StmtSeq {
+		switch p := stmt.Arg2.(type) {
+		case mir.StringParam:
+			strs = append(strs, p.Str())
+		case mir.CharParam:
+			chars = append(chars, p.Str())
+		}
+	}
+	if len(strs) != 2 || strs[0] != `"s"` || strs[1] != `"_T1"` || len(chars) != 2 || chars[0] != `'c'` {
+		t.Error("String literal param failed")
+		t.Error("Expected: ", `["s" "_T1"] ['c' 'c']`)
+		t.Error("Actual: ", strs, chars)
+	}
+}
+
+func TestStringCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 字符串只能拼接和比较，字符、字符串只能与相同类型比较
+	var errorCase = []string{
+		`int main(){ string s = "a" - "b"; return 0; }`,
+		`int main(){ string s = "a" + 1; return 0; }`,
+		`int main(){ char c = 'a' + 'b'; return 0; }`,
+		`int main(){ return "a" < 'a'; }`,
+		`int main(){ return 'a' == 97; }`,
+		`int main(){ return not "a"; }`,
+		`int main(){ return -'a'; }`,
+		`int main(){ return "a" and 1; }`,
+		`int main(){ int a = "1"; return a; }`,
+		`int main(){ int a; a = "hi"; return a; }`,
+		`int main(){ string s; s = 3; return 0; }`,
+		`int main(){ char c; c = 5; return 0; }`,
+		`int main(){ string s; s = 'a'; return 0; }`,
+		`int main(){ float f; f = 'a'; return 0; }`,
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("String check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
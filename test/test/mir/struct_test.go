@@ -0,0 +1,75 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestStruct(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 结构体字段的读写、嵌套结构体、整体赋值及按值传参
+	var structCase = map[string]int{
+		"struct P { int x; int y; } int main(){ P p; p.x = 3; p.y = 4; return p.x * 10 + p.y; }":                                                           34,
+		"struct P { int x, y; } struct L { P a; P b; } int main(){ L l; l.a.x = 1; l.b.y = 2; l.a.y = l.a.x + l.b.y; return l.a.y; }":                      3,
+		"struct P { int x; float f; } int main(){ P p, q; p.x = 5; p.f = 1; q = p; p.x = 6; return q.x * 10 + p.x; }":                                      56,
+		"struct P { int x; int y; } int sum(P p){ p.x = p.x + p.y; return p.x; } int main(){ P p; p.x = 1; p.y = 2; return sum(p) * 10 + p.x; }":           31,
+		"struct P { int x; } struct L { P a; int n; } int f(L l, int k){ return l.a.x * k + l.n; } int main(){ L l; l.a.x = 4; l.n = 1; return f(l, 2); }": 9,
+		"struct P { int x; } P g; void inc(){ g.x = g.x + 1; } int main(){ call inc(); call inc(); return g.x; }":                                          2,
+		"struct C { int n; } int main(){ C c; c.n = 0; for (int i = 0; i < 4; c.n = c.n + i) i = i + 1; return c.n; }":                                     10,
+		"struct P { int x; } struct L { P a; } int main(){ L l, m; P p; p.x = 7; l.a = p; m = l; return m.a.x; }":                                          7,
+	}
+
+	for k, v := range structCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Struct failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+func TestStructCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 未定义的类型、字段，结构体声明错误及结构体的非法使用
+	var errorCase = []string{
+		"int main(){ Q q; return 0; }",
+		"struct P { int x; } int main(){ P p; p.y = 1; return 0; }",
+		"struct P { int x; } int main(){ P p; return p.y; }",
+		"struct P { int x; } int main(){ int a; a.x = 1; return 0; }",
+		"struct P { int x; } int main(){ P p; return p.x.y; }",
+		"struct P { int x; } struct P { int y; } int main(){ return 0; }",
+		"struct P { int x; float x; } int main(){ return 0; }",
+		"struct P { int x = 1; } int main(){ return 0; }",
+		"struct P { int x[3]; } int main(){ return 0; }",
+		"struct P { P p; } int main(){ return 0; }",
+		"struct P { int x; } int main(){ P p[3]; return 0; }",
+		"struct P { int x; } struct Q { int x; } int main(){ P p; Q q; p = q; return 0; }",
+		"struct P { int x; } int main(){ P p; p = 1; return 0; }",
+		"struct P { int x; } int main(){ P p; int a; a = p; return 0; }",
+		"struct P { int x; } int main(){ P p; return p + 1; }",
+		"struct P { int x; } int main(){ P p; write(p); return 0; }",
+		"struct P { int x; } int main(){ P p; read(p); return 0; }",
+		"struct P { int x; } int f(P p){ return p; } int main(){ P p; return f(p); }",
+		"struct P { int x; } struct Q { int x; } int f(P p){ return p.x; } int main(){ Q q; return f(q); }",
+		"struct P { int x; } int main(){ P p; p.x = \"s\"; return 0; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Struct check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
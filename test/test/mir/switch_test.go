@@ -0,0 +1,105 @@
+package mir
+
+import (
+	"CompilerInGo/analyser"
+	"CompilerInGo/mir"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"strings"
+	"testing"
+)
+
+func TestSwitch(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 分支匹配、贯穿执行、default分支及break、continue的跳转位置
+	var switchCase = map[string]int{
+		"int main(){ int x = 1, s = 0; switch (x) { case 1: s = s + 1; case 2: s = s + 10; break; case 3: s = 100; } return s; }":                                                           11,
+		"int main(){ int x = 3, s = 0; switch (x) { case 1: s = s + 1; case 2: s = s + 10; break; case 3: s = 100; } return s; }":                                                           100,
+		"int main(){ int x = 5, s = 0; switch (x) { case 1: s = s + 1; case 2: s = s + 10; break; case 3: s = 100; } return s; }":                                                           0,
+		"int main(){ int x = 3, s = 0; switch (x) { case 1: s = 1; break; case 2: s = 2; break; case 4: s = 4; break; default: s = 9; } return s; }":                                        9,
+		"int main(){ int x = 9, s = 0; switch (x) { case 1: s = 1; default: s = s + 5; case 2: s = s + 10; } return s; }":                                                                   15,
+		"int main(){ int x = -5, s = 0; switch (x) { case 100: s = 1; break; case -5: s = 2; break; case 7: s = 3; break; default: s = 4; } return s; }":                                    2,
+		"int main(){ int x = 8, s = 0; switch (x) { case 100: s = 1; break; case -5: s = 2; break; case 7: s = 3; break; default: s = 4; } return s; }":                                     4,
+		"int main(){ char c = 'b'; int s = 0; switch (c) { case 'a': s = 1; break; case 'b': s = 2; break; case 'c': s = 3; break; } return s; }":                                           2,
+		"int main(){ int s = 0; for (int i = 0; i < 5; i = i + 1) { switch (i % 3) { case 0: continue; case 1: s = s + 10; break; default: s = s + 1; } s = s + 100; } return s; }":         321,
+		"int main(){ int x = 1, s = 0; switch (x) { case 1: while (1) { s = s + 1; if (s > 2) break; } s = s * 10; break; case 2: s = 5; } return s; }":                                     30,
+		"int main(){ int x = 4611686018427387904, s = 0; switch (x) { case -4611686018427387904: s = 1; break; case 0: s = 2; break; case 4611686018427387904: s = 3; } return s; }":        3,
+		"int main(){ int x = 9223372036854775807, s = 0; switch (x) { case 9223372036854775805: s = 1; case 9223372036854775806: s = 2; case 9223372036854775807: s = s + 3; } return s; }": 3,
+		"int main(){ int x = 2, y = 1, s = 0; switch (x) { case 2: switch (y) { case 1: s = 1; break; } s = s + 10; case 3: s = s + 100; } return s; }":                                     111,
+	}
+
+	for k, v := range switchCase {
+		if actual := run(t, generate(t, k)); actual != v {
+			t.Error("Switch failed")
+			t.Error("Input: ", k)
+			t.Error("Expected: ", v)
+			t.Error("Actual: ", actual)
+		}
+	}
+}
+
+// hasIndirectJump 判断中间代码中是否有跳转到变量中地址的语句
+func hasIndirectJump(program *mir.Program) bool {
+	for _, stmt := range program.StmtSeq {
+		if stmt.Op == mir.JMP && strings.HasPrefix(stmt.Res.Str(), "_T") {
+			return true
+		}
+	}
+	return false
+}
+
+func TestSwitchDispatch(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// case常量密集时使用跳转表，稀疏时使用条件跳转
+	var dispatchCase = map[string]bool{
+		"int main(){ int x = 2, s = 0; switch (x) { case 1: s = 1; case 2: s = 2; case 3: s = 3; case 5: s = 5; } return s; }":                                        true,
+		"int main(){ int x = 2, s = 0; switch (x) { case 1: s = 1; case 20: s = 2; case 300: s = 3; } return s; }":                                                    false,
+		"int main(){ int x = 2, s = 0; switch (x) { case 1: s = 1; case 2: s = 2; } return s; }":                                                                      false,
+		"int main(){ int x = 0, s = 0; switch (x) { case -4611686018427387904: s = 1; case 0: s = 2; case 4611686018427387904: s = 3; } return s; }":                  false,
+		"int main(){ int x = 0, s = 0; switch (x) { case -9223372036854775807: s = 1; case 0: s = 2; case 9223372036854775807: s = 3; } return s; }":                  false,
+		"int main(){ int x = 0, s = 0; switch (x) { case 9223372036854775805: s = 1; case 9223372036854775806: s = 2; case 9223372036854775807: s = 3; } return s; }": true,
+	}
+
+	for k, v := range dispatchCase {
+		if actual := hasIndirectJump(generate(t, k)); actual != v {
+			t.Error("Switch dispatch failed")
+			t.Error("Input: ", k)
+			t.Error("Expected jump table: ", v)
+			t.Error("Actual jump table: ", actual)
+		}
+	}
+}
+
+func TestSwitchCheck(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 被匹配的值及case常量的类型，重复的case常量、default分支，switch中的continue
+	var errorCase = []string{
+		"int main(){ float x = 1; switch (x) { case 1: break; } return 0; }",
+		"int main(){ string x = \"a\"; switch (x) { case 1: break; } return 0; }",
+		"int main(){ int x = 1; switch (x) { case 1: break; case 1: break; } return 0; }",
+		"int main(){ char c = 'a'; switch (c) { case 'a': case 'b': case 'a': break; } return 0; }",
+		"int main(){ int x = 1; switch (x) { default: break; default: break; } return 0; }",
+		"int main(){ int x = 1; switch (x) { case 'a': break; } return 0; }",
+		"int main(){ char c = 'a'; switch (c) { case 1: break; } return 0; }",
+		"int main(){ int x = 1; switch (x) { case 1: continue; } return 0; }",
+		"int main(){ int x = 1; switch (x) { case 1: y = 1; } return 0; }",
+	}
+	for _, c := range errorCase {
+		program, err := parser.ParseSource("test", c)
+		if err != nil {
+			t.Fatal("Parse failed: ", err)
+		}
+		if _, errs := analyser.NewAnalyser().Analyse(program); errs == 0 {
+			t.Error("Switch check failed")
+			t.Error("Input: ", c)
+			t.Error("Expected: ", "error")
+			t.Error("Actual: ", "no error")
+		}
+	}
+}
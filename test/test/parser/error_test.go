@@ -0,0 +1,82 @@
+package parser
+
+import (
+	"CompilerInGo/utils"
+	"testing"
+)
+
+func TestExpectedTokens(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 源程序 -> 第一个语法错误的信息
+	var cases = map[string]string{
+		"int main(){\n    int a\n    return a;\n}\n":   "expected one of `;`, `[`, `=`, `,`, found `return`",
+		"int main(){\n    a = 1 1;\n}\n":               "expected one of `;`, `*`, `/`, `%`, `+`, `-`, `<`, `<=`, `>`, `>=`, `==`, `<>`, `and`, `or`, found integer literal `1`",
+		"int main(){\n    a = ;\n}\n":                  "expected one of identifier, integer literal, decimal literal, string literal, char literal, `(`, `-`, `not`, found `;`",
+		"int main(){\n    call f(a b);\n}\n":           "expected one of `)`, `(`, `[`, `.`, `*`, `/`, `%`, `+`, `-`, `<`, `<=`, `>`, `>=`, `==`, `<>`, `and`, `or`, `,`, found identifier `b`",
+		"int main(int a, b){\n}\n":                     "expected identifier, found `)`",
+		"int main(){\n    else;\n}\n":                  "expected one of identifier, type, `call`, `if`, `while`, `for`, `do`, `switch`, `read`, `write`, `return`, `break`, `continue`, `{`, `}`, `;`, found `else`",
+		"int main(){\n    return 0;\n":                 "expected one of identifier, type, `call`, `if`, `while`, `for`, `do`, `switch`, `read`, `write`, `return`, `break`, `continue`, `{`, `}`, `;`, found end of file",
+		"a int main(){\n}\n":                           "expected one of method declaration, global declaration, struct declaration, found identifier `a`",
+		"int main(){\n    if(a < 1 b) a = 1;\n}\n":     "expected one of `)`, `*`, `/`, `%`, `+`, `-`, `<`, `<=`, `>`, `>=`, `==`, `<>`, `and`, `or`, found identifier `b`",
+		"int main(){\n    a = else;\n}\n":              "expected one of identifier, integer literal, decimal literal, string literal, char literal, `(`, `-`, `not`, found `else`",
+		"int main(){\n    int a, 1;\n}\n":              "expected identifier, found integer literal `1`",
+		"int main(){\n    while(a <) a = 1;\n}\n":      "expected one of identifier, integer literal, decimal literal, string literal, char literal, `(`, `-`, `not`, found `)`",
+		"int main(){\n    call f(;\n}\n":               "expected one of `)`, identifier, integer literal, decimal literal, string literal, char literal, `(`, `-`, `not`, found `;`",
+		"int main(){\n    return 0;\n}\nint f(}\n":     "expected one of `)`, type, found `}`",
+		"int main(){\n    for(1;;) a = 1;\n}\n":        "expected one of identifier, type, `;`, found integer literal `1`",
+		"int main(){\n    for(;; a) a = 1;\n}\n":       "expected one of `=`, `[`, `.`, found `)`",
+		"int main(){\n    do a = 1; (a);\n}\n":         "expected `while`, found `(`",
+		"int main(){\n    int a[n];\n}\n":              "expected integer literal, found identifier `n`",
+		"int main(){\n    a[1 = 2;\n}\n":               "expected one of `]`, `*`, `/`, `%`, `+`, `-`, `<`, `<=`, `>`, `>=`, `==`, `<>`, `and`, `or`, found `=`",
+		"struct P {\n    int x\n}\n":                   "expected one of `;`, `[`, `=`, `,`, found `}`",
+		"struct P {\n    int x;\n\nint main(){\n}\n":   "expected `}`, found `int`",
+		"int main(){\n    p.1 = 2;\n}\n":               "expected identifier, found integer literal `1`",
+		"int main(){\n    switch (a) { case b: }\n}\n": "expected one of integer literal, char literal, found identifier `b`",
+		"int main(){\n    switch (a) { case 1 }\n}\n":  "expected `:`, found `}`",
+		"int main(){\n    switch (a) { a = 1; }\n}\n":  "expected one of `}`, `case`, `default`, found identifier `a`",
+	}
+
+	for src, message := range cases {
+		src, message := src, message
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			syntaxErrors, _ := parse(t, src)
+			if len(syntaxErrors) == 0 {
+				t.Error("Expected tokens failed")
+				t.Errorf("Input: %q", src)
+				t.Error("Expected: ", message)
+				t.Error("Actual: no error")
+				return
+			}
+			if syntaxErrors[0].Message != message {
+				t.Error("Expected tokens failed")
+				t.Errorf("Input: %q", src)
+				t.Error("Expected: ", message)
+				t.Error("Actual: ", syntaxErrors[0].Message)
+			}
+		})
+	}
+}
+
+func TestIndicator(t *testing.T) {
+	t.Parallel()
+	var cases = []struct {
+		line     string
+		pos      utils.PositionPair
+		expected string
+	}{
+		{"a = 1 1;", utils.PositionPair{Begin: utils.Position{Row: 1, Col: 7}, End: utils.Position{Row: 1, Col: 7}}, "------^"},
+		{"\treturn 0;", utils.PositionPair{Begin: utils.Position{Row: 1, Col: 2}, End: utils.Position{Row: 1, Col: 7}}, "\t^^^^^^"},
+		{"int main(){", utils.PositionPair{Begin: utils.Position{Row: 1, Col: 1}, End: utils.Position{Row: 2, Col: 1}}, "^"},
+	}
+	for _, c := range cases {
+		if actual := utils.Indicator(c.line, c.pos); actual != c.expected {
+			t.Error("Indicator failed")
+			t.Errorf("Input: %q", c.line)
+			t.Errorf("Expected: %q", c.expected)
+			t.Errorf("Actual: %q", actual)
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package parser
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestParseTokens(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 多个Parser同时读取同一个Token序列，结果与单独解析一致
+	for _, file := range []string{"../../long.program", "../../sample1.program"} {
+		file := file
+		t.Run(file, func(t *testing.T) {
+			t.Parallel()
+			content, err := os.ReadFile(file)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sourceProgram, err := parser.ParseSource(file, string(content))
+			if err != nil {
+				t.Fatal("Parse failed: ", err)
+			}
+			expected, _ := json.Marshal(sourceProgram)
+
+			lex := lexer.NewLexer(file)
+			tokens := lex.Tokenize().Pool
+			for i := 0; i < 4; i++ {
+				t.Run("", func(t *testing.T) {
+					t.Parallel()
+					program, err := parser.ParseTokens(tokens)
+					if err != nil {
+						t.Fatal("Parse failed: ", err)
+					}
+					if actual, _ := json.Marshal(program); string(actual) != string(expected) {
+						t.Error("Parse tokens failed")
+						t.Error("Input: ", file)
+					}
+				})
+			}
+		})
+	}
+}
+
+func TestParseSource(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 词法错误时不进行语法分析
+	program, err := parser.ParseSource("test", "int main(){\n    a = 1 # 2;\n}\n")
+	var lexErrors lexer.LexErrors
+	if program != nil || !errors.As(err, &lexErrors) || len(lexErrors) != 1 {
+		t.Error("Parse source failed")
+		t.Error("Expected: ", "1 lexical error")
+		t.Error("Actual: ", err)
+	}
+
+	// 语法错误时返回部分AST
+	program, err = parser.ParseSource("test", "int f({\n}\nint main(){\n    return 0;\n}\n")
+	var syntaxErrors parser.SyntaxErrors
+	if program == nil || len(program.Method) != 1 || !errors.As(err, &syntaxErrors) {
+		t.Error("Parse source failed")
+		t.Error("Expected: ", "partial program with syntax errors")
+		t.Error("Actual: ", program, err)
+	}
+}
@@ -0,0 +1,89 @@
+package parser
+
+import (
+	"CompilerInGo/lexer"
+	"CompilerInGo/parser"
+	"CompilerInGo/parser/ast"
+	"CompilerInGo/utils"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// render 将表达式输出为完全加括号的形式，如 (a + (b * c))
+func render(exp ast.Exp) string {
+	switch e := exp.Exp.(type) {
+	case ast.BinaryExp:
+		return fmt.Sprintf("(%s %v %s)", render(*e.LExp), e.Op.Literal, render(*e.RExp))
+	case ast.UnaryExp:
+		return fmt.Sprintf("(%v %s)", e.Op.Literal, render(*e.Exp))
+	case ast.Factor:
+		switch f := e.Factor.(type) {
+		case ast.FactorTuple:
+			return render(*f.Exp)
+		case ast.CallTuple:
+			args := make([]string, 0)
+			exps, _ := f.ActParamList.Integrate()
+			for _, arg := range exps {
+				args = append(args, render(arg))
+			}
+			return fmt.Sprintf("%v(%s)", f.ID.Literal, strings.Join(args, ", "))
+		case ast.IndexTuple:
+			return fmt.Sprintf("%v[%s]", f.ID.Literal, render(f.Index.Exp))
+		case ast.FieldTuple:
+			path := fmt.Sprint(f.ID.Literal)
+			for _, selector := range f.Selectors {
+				path += "." + fmt.Sprint(selector.Field.Literal)
+			}
+			return path
+		case lexer.Token:
+			return fmt.Sprint(f.Literal)
+		}
+	}
+	return "?"
+}
+
+func TestPratt(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	// 表达式 -> 按优先级及结合性加括号后的形式
+	var cases = map[string]string{
+		"1 + 2 * 3":                     "(1 + (2 * 3))",
+		"1 - 2 - 3":                     "((1 - 2) - 3)",
+		"a % b * c":                     "((a % b) * c)",
+		"-a * b":                        "((- a) * b)",
+		"- -a":                          "(- (- a))",
+		"a-1":                           "(a - 1)",
+		"a*b-1.5":                       "((a * b) - 1.5)",
+		"-1 * a":                        "(-1 * a)",
+		"(a < b) + 1":                   "((a < b) + 1)",
+		"a + 1 < b * 2 == c":            "(((a + 1) < (b * 2)) == c)",
+		"a < b and not c == d or e":     "(((a < b) and (not (c == d))) or e)",
+		"not a or b":                    "((not a) or b)",
+		"a or b and c":                  "(a or (b and c))",
+		"f(a, -1) % 2":                  "(f(a, -1) % 2)",
+		"f(a-1, (b + c) * 2) <> -b * 3": "(f((a - 1), ((b + c) * 2)) <> ((- b) * 3))",
+		"a[i-1] + 1":                    "(a[(i - 1)] + 1)",
+		"-a[b[0]] * 2":                  "((- a[b[0]]) * 2)",
+		"p.x + l.a.y * 2":               "(p.x + (l.a.y * 2))",
+	}
+
+	for src, expected := range cases {
+		src, expected := src, expected
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			program, err := parser.ParseSource("test", "int main(){\n    r = "+src+";\n}\n")
+			if err != nil {
+				t.Fatal("Parse failed: ", err)
+			}
+			statement := (*program.Method[0].Block.Statements)[0].Statement.(*ast.AssignmentStatement)
+			if actual := render(statement.Exp); actual != expected {
+				t.Error("Pratt parser failed")
+				t.Error("Input: ", src)
+				t.Error("Expected: ", expected)
+				t.Error("Actual: ", actual)
+			}
+		})
+	}
+}
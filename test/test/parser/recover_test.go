@@ -0,0 +1,105 @@
+package parser
+
+import (
+	"CompilerInGo/parser"
+	"CompilerInGo/utils"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// parse 解析源程序，返回全部语法错误及部分AST中的方法名
+func parse(t *testing.T, src string) (parser.SyntaxErrors, []string) {
+	program, err := parser.ParseSource("test", src)
+	var syntaxErrors parser.SyntaxErrors
+	if err != nil && !errors.As(err, &syntaxErrors) {
+		t.Fatal("Parse failed: ", err)
+	}
+	if program == nil {
+		t.Fatal("Parse returned no program")
+	}
+	methods := make([]string, 0)
+	for _, method := range program.Method {
+		methods = append(methods, method.GetMethodName())
+	}
+	return syntaxErrors, methods
+}
+
+func TestRecover(t *testing.T) {
+	utils.InitLogger("CLOSE")
+	t.Parallel()
+
+	var cases = []struct {
+		src     string
+		rows    []uint   // 语法错误所在的行
+		methods []string // 部分AST中保留的方法
+	}{
+		{
+			src:     "int main(){\n    int a;\n    a = 1;\n    return a;\n}\n",
+			rows:    []uint{},
+			methods: []string{"main"},
+		},
+		// 语句中的错误，同步到分号后继续解析
+		{
+			src:     "int main(){\n    int a\n    a = ;\n    a = 1 +;\n    return a;\n}\n",
+			rows:    []uint{3, 4},
+			methods: []string{"main"},
+		},
+		// 代码块中的错误，同步到右大括号
+		{
+			src:     "int main(){\n    while(a < 1){\n        a = * 2;\n    }\n    call f(;\n    return 0;\n}\n",
+			rows:    []uint{3, 5},
+			methods: []string{"main"},
+		},
+		// 跳过出错的代码块
+		{
+			src:     "int main(){\n    if(a < ) {\n        a = 1;\n    }\n    a = 2;\n}\n",
+			rows:    []uint{2},
+			methods: []string{"main"},
+		},
+		// 方法头中的错误，同步到下一个方法头
+		{
+			src:     "int f(int){\n    return 1;\n}\nint main(){\n    return 0;\n}\n",
+			rows:    []uint{1},
+			methods: []string{"main"},
+		},
+		// 缺少右大括号，放弃当前方法
+		{
+			src:     "int f(){\n    return 1;\nint main(){\n    a = 1 1;\n    return 0;\n}\n",
+			rows:    []uint{3, 4},
+			methods: []string{"main"},
+		},
+		// 方法之外的Token
+		{
+			src:     "a = 1;\nint main(){\n    return 0;\n}\nwhile\n",
+			rows:    []uint{1, 5},
+			methods: []string{"main"},
+		},
+	}
+
+	for _, c := range cases {
+		c := c
+		t.Run("", func(t *testing.T) {
+			t.Parallel()
+			syntaxErrors, methods := parse(t, c.src)
+
+			rows := make([]uint, 0)
+			for _, e := range syntaxErrors {
+				rows = append(rows, e.Pos.Begin.Row)
+			}
+			if !reflect.DeepEqual(rows, c.rows) {
+				t.Error("Syntax error recovery failed")
+				t.Errorf("Input: %q", c.src)
+				t.Error("Expected rows: ", c.rows)
+				t.Error("Actual rows: ", rows)
+				t.Error("Errors: ", syntaxErrors)
+			}
+			if !reflect.DeepEqual(methods, c.methods) {
+				t.Error("Partial program failed")
+				t.Errorf("Input: %q", c.src)
+				t.Error("Expected: ", c.methods)
+				t.Error("Actual: ", methods)
+			}
+		})
+	}
+}
@@ -1,6 +1,19 @@
 package utils
 
-import "strings"
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+var (
+	ErrUnknownEscape    = errors.New("unknown escape sequence")   // 未知的转义序列
+	ErrMalformedEscape  = errors.New("malformed escape sequence") // 格式错误的转义序列
+	ErrInvalidCodePoint = errors.New("invalid code point")        // 非法的Unicode码点
+)
 
 // EscapeString 将字符串中的特殊字符转义
 func EscapeString(s string) string {
@@ -35,33 +48,126 @@ func EscapeRune(r rune) string {
 		return "\\b"
 	case '\f':
 		return "\\f"
+	case 0:
+		return "\\0"
+	}
+
+	if r < utf8.RuneSelf && !unicode.IsPrint(r) {
+		// 其他ASCII控制字符，使用\xHH
+		return fmt.Sprintf("\\x%02X", r)
+	} else if !unicode.IsPrint(r) {
+		// 其他不可打印字符，使用\u{XXXX}
+		return fmt.Sprintf("\\u{%X}", r)
 	}
 
 	// 不需转义字符，使用字符串返回
 	return string(r)
 }
 
+// DecodeEscape 解码s开头的一个转义序列，返回对应的字符及转义序列的字节长度
+// 支持 \' \" \\ \n \r \t \b \f \0 \xHH（ASCII）\u{XXXX}（1至6位十六进制Unicode码点）
+func DecodeEscape(s string) (rune, int, error) {
+	if len(s) == 0 || s[0] != '\\' {
+		// 不是转义序列
+		return utf8.RuneError, 0, ErrMalformedEscape
+	} else if len(s) == 1 {
+		// 只有反斜杠
+		return utf8.RuneError, 1, ErrMalformedEscape
+	}
+
+	switch s[1] {
+	case '\'':
+		return '\'', 2, nil
+	case '"':
+		return '"', 2, nil
+	case '\\':
+		return '\\', 2, nil
+	case 'n':
+		return '\n', 2, nil
+	case 'r':
+		return '\r', 2, nil
+	case 't':
+		return '\t', 2, nil
+	case 'b':
+		return '\b', 2, nil
+	case 'f':
+		return '\f', 2, nil
+	case '0':
+		return 0, 2, nil
+	case 'x':
+		// \xHH 恰好两位十六进制数字，且只能表示ASCII字符
+		if len(s) < 3 || !isHexDigit(s[2]) {
+			return utf8.RuneError, 2, ErrMalformedEscape
+		}
+		if len(s) < 4 || !isHexDigit(s[3]) {
+			return utf8.RuneError, 3, ErrMalformedEscape
+		}
+		value, _ := strconv.ParseUint(s[2:4], 16, 8)
+		if value >= utf8.RuneSelf {
+			return utf8.RuneError, 4, ErrInvalidCodePoint
+		}
+		return rune(value), 4, nil
+	case 'u':
+		// \u{XXXX} 花括号内为1至6位十六进制数字
+		end := strings.IndexByte(s, '}')
+		if len(s) < 3 || s[2] != '{' || end < 0 {
+			return utf8.RuneError, 2, ErrMalformedEscape
+		}
+		digits := s[3:end]
+		if len(digits) == 0 || len(digits) > 6 {
+			return utf8.RuneError, end + 1, ErrMalformedEscape
+		}
+		for i := 0; i < len(digits); i++ {
+			if !isHexDigit(digits[i]) {
+				return utf8.RuneError, end + 1, ErrMalformedEscape
+			}
+		}
+		value, _ := strconv.ParseUint(digits, 16, 32)
+		// 超出Unicode范围或为代理码点
+		if r := rune(value); utf8.ValidRune(r) {
+			return r, end + 1, nil
+		}
+		return utf8.RuneError, end + 1, ErrInvalidCodePoint
+	}
+
+	// 未知的转义序列
+	_, size := utf8.DecodeRuneInString(s[1:])
+	return utf8.RuneError, 1 + size, ErrUnknownEscape
+}
+
 // UnescapeRune 将单个转义字符组成的字符串还原为字符
 func UnescapeRune(s string) rune {
-	switch s {
-	case "\\'":
-		return '\''
-	case "\\\"":
-		return '"'
-	case "\\\\":
-		return '\\'
-	case "\\n":
-		return '\n'
-	case "\\r":
-		return '\r'
-	case "\\t":
-		return '\t'
-	case "\\b":
-		return '\b'
-	case "\\f":
-		return '\f'
+	if r, size, err := DecodeEscape(s); err == nil && size == len(s) {
+		return r
 	}
 
 	// 不需还原字符，返回第一个字符
 	return rune(s[0])
 }
+
+// UnescapeString 将字符串中的转义序列还原为字符
+// 出现非法转义序列时返回第一个错误，非法转义序列还原为utf8.RuneError
+func UnescapeString(s string) (string, error) {
+	var res strings.Builder
+	var firstErr error
+
+	for len(s) > 0 {
+		if s[0] != '\\' {
+			// 普通字符
+			r, size := utf8.DecodeRuneInString(s)
+			res.WriteRune(r)
+			s = s[size:]
+			continue
+		}
+
+		// 转义序列
+		r, size, err := DecodeEscape(s)
+		if err != nil && firstErr == nil {
+			firstErr = err
+		}
+		res.WriteRune(r)
+		s = s[size:]
+	}
+
+	return res.String(), firstErr
+}
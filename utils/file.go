@@ -1,35 +1,166 @@
 package utils
 
 import (
-	"bufio"
-	"github.com/kpango/glg"
+	"sort"
 	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
 )
 
-// GetLine 获取文件中指定行的内容
-func GetLine(file []byte, Pos Position) string {
-	// 创建读取器
-	reader := bufio.NewReader(strings.NewReader(string(file)))
-
-	var line []byte
-	var err error
-
-	// 读取第一行
-	line, _, err = reader.ReadLine()
-	for i := 0; i < int(Pos.Row-1); i++ {
-		// 读取到指定行
-		line, _, err = reader.ReadLine()
-	}
-	if err != nil {
-		// 读取错误
-		if err.Error() == "EOF" {
-			// 读取到文件末尾
-			return "EOF"
+// BOM UTF-8字节顺序标记
+const (
+	BOM     = "\uFEFF"
+	BOMRune = '\uFEFF'
+)
+
+// LineTable 行偏移表，记录源程序每一行开始的字节偏移
+// 换行可以是\n、\r\n或单独的\r，\r\n视为一个换行
+type LineTable struct {
+	src   []byte // 源程序
+	lines []int  // 每一行开始的字节偏移，第一行跳过BOM
+}
+
+// NewLineTable 创建源程序的行偏移表
+func NewLineTable(src []byte) *LineTable {
+	table := &LineTable{
+		src:   src,
+		lines: []int{0},
+	}
+
+	// 第一行跳过BOM
+	if len(src) >= len(BOM) && string(src[:len(BOM)]) == BOM {
+		table.lines[0] = len(BOM)
+	}
+
+	for i := 0; i < len(src); i++ {
+		switch src[i] {
+		case '\r':
+			// \r\n视为一个换行
+			if i+1 < len(src) && src[i+1] == '\n' {
+				i++
+			}
+			table.lines = append(table.lines, i+1)
+		case '\n':
+			table.lines = append(table.lines, i+1)
 		}
-		// 读取时发生错误
-		glg.Fatalln(err)
+	}
+
+	return table
+}
+
+// Len 获取源程序的字节数
+func (t *LineTable) Len() int {
+	return len(t.src)
+}
+
+// LineCount 获取行数
+func (t *LineTable) LineCount() int {
+	return len(t.lines)
+}
+
+// LineStart 获取第row行（从1开始）开始的字节偏移
+func (t *LineTable) LineStart(row uint) int {
+	return t.lines[row-1]
+}
+
+// lineEnd 获取第row行（从1开始）不含换行的结束字节偏移
+func (t *LineTable) lineEnd(row uint) int {
+	end := len(t.src)
+	if int(row) < len(t.lines) {
+		end = t.lines[row]
+	}
+	// 去掉换行
+	if end > t.lines[row-1] && t.src[end-1] == '\n' {
+		end--
+	}
+	if end > t.lines[row-1] && t.src[end-1] == '\r' {
+		end--
+	}
+	return end
+}
+
+// Line 获取第row行（从1开始）的内容，不含换行
+func (t *LineTable) Line(row uint) (string, bool) {
+	if row == 0 || int(row) > len(t.lines) {
+		return "", false
+	}
+	return string(t.src[t.lines[row-1]:t.lineEnd(row)]), true
+}
+
+// Location 将字节偏移转换为行号及字节、字符、UTF-16列号，行列号均从1开始
+// 超出范围的偏移按文件开头或末尾处理
+func (t *LineTable) Location(offset int) Location {
+	if offset < 0 {
+		offset = 0
+	} else if offset > len(t.src) {
+		offset = len(t.src)
+	}
+
+	// 二分查找偏移所在行
+	row := sort.Search(len(t.lines), func(i int) bool {
+		return t.lines[i] > offset
+	})
+	if row == 0 {
+		// 位于第一行的BOM中
+		row = 1
+		offset = t.lines[0]
+	}
+
+	// 统计行首到偏移之间的字符数及UTF-16编码单元数
+	line := t.src[t.lines[row-1]:offset]
+	runeCol, utf16Col := 1, 1
+	for len(line) > 0 {
+		r, size := utf8.DecodeRune(line)
+		line = line[size:]
+		runeCol++
+		if utf16.IsSurrogate(r) || r < 0x10000 {
+			utf16Col++
+		} else {
+			// 辅助平面字符占两个UTF-16编码单元
+			utf16Col += 2
+		}
+	}
+
+	return Location{
+		Row:      uint(row),
+		ByteCol:  uint(offset - t.lines[row-1] + 1),
+		RuneCol:  uint(runeCol),
+		UTF16Col: uint(utf16Col),
+	}
+}
+
+// GetLine 获取文件中指定位置所在行的内容
+func GetLine(lines *LineTable, Pos Position) string {
+	line, ok := lines.Line(Pos.Row)
+	if !ok {
+		// 读取到文件末尾
+		return "EOF"
 	}
 
 	// 返回指定行内容
-	return string(line)
+	return line
+}
+
+// Indicator 构造错误位置指示器，在出错行下方用^标出pos的范围
+// 出错行中的制表符原样保留，使指示器与任意制表符宽度下的显示对齐
+func Indicator(line string, pos PositionPair) string {
+	var str strings.Builder
+	for i, ch := range []rune(line) {
+		if i >= int(pos.Begin.Col)-1 {
+			break
+		}
+		if ch == '\t' {
+			str.WriteRune('\t')
+		} else {
+			str.WriteRune('-')
+		}
+	}
+	str.WriteRune('^')
+	// 错误位于同一行时，标出整个错误范围
+	if pos.End.Row == pos.Begin.Row {
+		for i := pos.Begin.Col; i < pos.End.Col; i++ {
+			str.WriteRune('^')
+		}
+	}
+	return str.String()
 }
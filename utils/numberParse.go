@@ -2,9 +2,15 @@ package utils
 
 import (
 	"errors"
+	"math"
+	"strconv"
 )
 
+// ErrRange 数值超出可表示范围
+var ErrRange = errors.New("value out of range")
+
 // ParseInt 将字符串转换为int64
+// 支持十进制、0x/0X十六进制、0o/0O八进制、0b/0B二进制，数字之间可以使用_分隔
 func ParseInt(s string) (int64, error) {
 	// 空字符串
 	if s == "" {
@@ -25,25 +31,70 @@ func ParseInt(s string) (int64, error) {
 		s = s[1:]
 	}
 
-	// 结果
-	var n int64
+	// 判断进制前缀
+	base := uint64(10)
+	if len(s) >= 2 && s[0] == '0' {
+		switch s[1] {
+		case 'x', 'X':
+			base = 16
+		case 'o', 'O':
+			base = 8
+		case 'b', 'B':
+			base = 2
+		}
+		if base != 10 {
+			s = s[2:]
+			// 允许前缀后紧跟一个_，如0x_1F
+			if len(s) > 0 && s[0] == '_' {
+				s = s[1:]
+			}
+		}
+	}
+
+	// 去除数字分隔符
+	isDigit := isDecimalDigit
+	if base == 16 {
+		isDigit = isHexDigit
+	}
+	s, ok := stripUnderscores(s, isDigit)
+	if !ok {
+		return 0, errors.New("failed to ParseInt: invalid '_' separator")
+	}
+	// 只有进制前缀
+	if s == "" {
+		return 0, errors.New("failed to ParseInt: invalid number string")
+	}
+
+	// 结果的绝对值，使用uint64以便表示-9223372036854775808
+	var n uint64
+	// 绝对值上限
+	limit := uint64(math.MaxInt64)
+	if neg {
+		limit++
+	}
 
 	// 遍历数字部分
-	for _, ch := range s {
-		if ch < '0' || ch > '9' {
+	for i := 0; i < len(s); i++ {
+		d, ok := digitValue(s[i])
+		if !ok || d >= base {
 			return 0, errors.New("failed to ParseInt: invalid char")
 		}
-		n = n*10 + int64(ch-'0')
+		// 检查溢出
+		if n > (limit-d)/base {
+			return 0, ErrRange
+		}
+		n = n*base + d
 	}
 
 	if neg {
-		// 返回负数
-		return -n, nil
+		// 返回负数，n为2^63时转换结果恰为math.MinInt64
+		return -int64(n), nil
 	}
-	return n, nil
+	return int64(n), nil
 }
 
 // ParseFloat 将字符串转换为float64
+// 支持小数、指数形式(1.5e-3)，数字之间可以使用_分隔，结果为正确舍入的最近浮点数
 func ParseFloat(s string) (float64, error) {
 	// 空字符串
 	if s == "" {
@@ -68,45 +119,113 @@ func ParseFloat(s string) (float64, error) {
 		s = s[1:]
 	}
 
-	// 整数部分
-	var integ float64
-
-	// 整数长度i
-	var i int
+	// 去除数字分隔符
+	s, ok := stripUnderscores(s, isDecimalDigit)
+	if !ok {
+		return 0, errors.New("failed to ParseFloat: invalid '_' separator")
+	}
 
-	for i = 0; i < len(s); i++ {
-		// 遇到小数点
-		if s[i] == '.' {
-			break
+	// 检查格式：digits [ '.' [digits] ] [ ('e'|'E') ['+'|'-'] digits ]
+	i := 0
+	// 整数部分
+	for i < len(s) && isDecimalDigit(s[i]) {
+		i++
+	}
+	if i == 0 {
+		return 0, errors.New("failed to ParseFloat: invalid char")
+	}
+	// 小数部分
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && isDecimalDigit(s[i]) {
+			i++
 		}
-		// 非数字，非法
-		if s[i] < '0' || s[i] > '9' {
-			return 0, errors.New("failed to ParseFloat: invalid char")
+	}
+	// 指数部分
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		i++
+		if i < len(s) && (s[i] == '+' || s[i] == '-') {
+			i++
+		}
+		expBegin := i
+		for i < len(s) && isDecimalDigit(s[i]) {
+			i++
+		}
+		// 指数部分没有数字
+		if i == expBegin {
+			return 0, errors.New("failed to ParseFloat: invalid exponent")
 		}
+	}
+	// 存在未识别的字符
+	if i != len(s) {
+		return 0, errors.New("failed to ParseFloat: invalid char")
+	}
 
-		// 拼接整数部分
-		integ = integ*10 + float64(s[i]-'0')
+	// 格式已检查，使用strconv进行正确舍入的转换
+	num, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		// 超出float64范围
+		return 0, ErrRange
 	}
 
-	// 无小数部分
-	if i == len(s) {
-		if neg {
-			return -integ, nil
+	if neg {
+		return -num, nil
+	}
+	return num, nil
+}
+
+// stripUnderscores 去除数字之间的_分隔符
+// _只能出现在两个满足isDigit的字符之间
+func stripUnderscores(s string, isDigit func(byte) bool) (string, bool) {
+	// 没有分隔符，直接返回
+	hasUnderscore := false
+	for i := 0; i < len(s); i++ {
+		if s[i] == '_' {
+			hasUnderscore = true
+			break
 		}
-		return integ, nil
+	}
+	if !hasUnderscore {
+		return s, true
 	}
 
-	// 小数部分
-	var frac float64
-	for j := len(s) - 1; j > i; j-- {
-		if s[j] < '0' || s[j] > '9' {
-			return 0, errors.New("failed to ParseFloat: invalid char")
+	res := make([]byte, 0, len(s))
+	for i := 0; i < len(s); i++ {
+		if s[i] != '_' {
+			res = append(res, s[i])
+			continue
+		}
+		// _前后必须都是数字
+		if i == 0 || i == len(s)-1 {
+			return "", false
+		}
+		if !isDigit(s[i-1]) || !isDigit(s[i+1]) {
+			return "", false
 		}
-		frac = frac/10 + float64(s[j]-'0')
 	}
+	return string(res), true
+}
 
-	if neg {
-		return -(integ + frac/10), nil
+// isDecimalDigit 判断是否为十进制数字
+func isDecimalDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// isHexDigit 判断是否为十六进制数字
+func isHexDigit(ch byte) bool {
+	_, ok := digitValue(ch)
+	return ok
+}
+
+// digitValue 获取数字字符（含十六进制）对应的值
+func digitValue(ch byte) (uint64, bool) {
+	switch {
+	case ch >= '0' && ch <= '9':
+		return uint64(ch - '0'), true
+	case ch >= 'a' && ch <= 'f':
+		return uint64(ch-'a') + 10, true
+	case ch >= 'A' && ch <= 'F':
+		return uint64(ch-'A') + 10, true
 	}
-	return integ + frac/10, nil
+	return 0, false
 }
@@ -6,6 +6,7 @@ type Position struct {
 	Col     uint
 	FilePos uint
 	Ch      rune
+	Size    uint // 当前字符在源程序中实际占用的字节数，非法UTF-8字节为1
 }
 
 // PositionPair 位置对（开始+结束）
@@ -13,3 +14,23 @@ type PositionPair struct {
 	Begin Position
 	End   Position
 }
+
+// Offset 获取当前字符开始的字节偏移
+func (p Position) Offset() uint {
+	if p.Ch == 0 {
+		// EOF
+		return p.FilePos
+	}
+	if p.Size > p.FilePos {
+		return 0
+	}
+	return p.FilePos - p.Size
+}
+
+// Location 行偏移表中的位置，行列号均从1开始
+type Location struct {
+	Row      uint // 行号
+	ByteCol  uint // 按字节计算的列号
+	RuneCol  uint // 按字符计算的列号
+	UTF16Col uint // 按UTF-16编码单元计算的列号，用于编辑器及LSP客户端
+}
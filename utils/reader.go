@@ -2,8 +2,7 @@ package utils
 
 import "io"
 
-// Reader 读取器接口
+// Reader 读取器接口，需支持回退一个字符
 type Reader interface {
-	io.RuneReader
-	io.Seeker
+	io.RuneScanner
 }